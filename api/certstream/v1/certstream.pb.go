@@ -0,0 +1,2268 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: certstream/v1/certstream.proto
+
+// Typed representation of the certstream messages for the gRPC service.
+// The messages mirror the JSON representation sent to websocket clients (see internal/models/certstream.go).
+
+package certstreamv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Stream selects the level of detail of the streamed messages, like the websocket endpoints do.
+type Stream int32
+
+const (
+	// Defaults to STREAM_LITE.
+	Stream_STREAM_UNSPECIFIED Stream = 0
+	// All details including the chain and the DER encoded certificate.
+	Stream_STREAM_FULL Stream = 1
+	// All details except the chain and the DER encoded certificate.
+	Stream_STREAM_LITE Stream = 2
+	// Only the domains of the certificate.
+	Stream_STREAM_DOMAINS Stream = 3
+	// Only the domains (or registrable domains) that weren't seen within the configured window (requires new domains).
+	Stream_STREAM_NEW_DOMAINS Stream = 4
+)
+
+// Enum value maps for Stream.
+var (
+	Stream_name = map[int32]string{
+		0: "STREAM_UNSPECIFIED",
+		1: "STREAM_FULL",
+		2: "STREAM_LITE",
+		3: "STREAM_DOMAINS",
+		4: "STREAM_NEW_DOMAINS",
+	}
+	Stream_value = map[string]int32{
+		"STREAM_UNSPECIFIED": 0,
+		"STREAM_FULL":        1,
+		"STREAM_LITE":        2,
+		"STREAM_DOMAINS":     3,
+		"STREAM_NEW_DOMAINS": 4,
+	}
+)
+
+func (x Stream) Enum() *Stream {
+	p := new(Stream)
+	*p = x
+	return p
+}
+
+func (x Stream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Stream) Descriptor() protoreflect.EnumDescriptor {
+	return file_certstream_v1_certstream_proto_enumTypes[0].Descriptor()
+}
+
+func (Stream) Type() protoreflect.EnumType {
+	return &file_certstream_v1_certstream_proto_enumTypes[0]
+}
+
+func (x Stream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Stream.Descriptor instead.
+func (Stream) EnumDescriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{0}
+}
+
+type Filter struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Stream Stream                 `protobuf:"varint,1,opt,name=stream,proto3,enum=certstream.v1.Stream" json:"stream,omitempty"`
+	// If set, only certificates for these domains and their subdomains are sent.
+	Domains []string `protobuf:"bytes,2,rep,name=domains,proto3" json:"domains,omitempty"`
+	// If set, buffered entries after this sequence number are replayed before live data (requires the replay buffer).
+	Since *uint64 `protobuf:"varint,3,opt,name=since,proto3,oneof" json:"since,omitempty"`
+	// If set, only certificates with at least this phishing score are sent (requires scoring).
+	MinScore int32 `protobuf:"varint,4,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"`
+	// If set, the domains of STREAM_DOMAINS are grouped by registrable domain (eTLD+1).
+	GroupByRegistrableDomain bool `protobuf:"varint,5,opt,name=group_by_registrable_domain,json=groupByRegistrableDomain,proto3" json:"group_by_registrable_domain,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Filter) Reset() {
+	*x = Filter{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Filter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Filter) ProtoMessage() {}
+
+func (x *Filter) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Filter.ProtoReflect.Descriptor instead.
+func (*Filter) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Filter) GetStream() Stream {
+	if x != nil {
+		return x.Stream
+	}
+	return Stream_STREAM_UNSPECIFIED
+}
+
+func (x *Filter) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *Filter) GetSince() uint64 {
+	if x != nil && x.Since != nil {
+		return *x.Since
+	}
+	return 0
+}
+
+func (x *Filter) GetMinScore() int32 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *Filter) GetGroupByRegistrableDomain() bool {
+	if x != nil {
+		return x.GroupByRegistrableDomain
+	}
+	return false
+}
+
+type Message struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Server-wide, monotonically increasing sequence number of the entry. It's 0 for gap notices and heartbeats.
+	Seq uint64 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Message_CertificateUpdate
+	//	*Message_DomainsUpdate
+	//	*Message_Gap
+	//	*Message_Heartbeat
+	Payload       isMessage_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Message) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Message) GetPayload() isMessage_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Message) GetCertificateUpdate() *CertificateUpdate {
+	if x != nil {
+		if x, ok := x.Payload.(*Message_CertificateUpdate); ok {
+			return x.CertificateUpdate
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetDomainsUpdate() *DomainsUpdate {
+	if x != nil {
+		if x, ok := x.Payload.(*Message_DomainsUpdate); ok {
+			return x.DomainsUpdate
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetGap() *Gap {
+	if x != nil {
+		if x, ok := x.Payload.(*Message_Gap); ok {
+			return x.Gap
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		if x, ok := x.Payload.(*Message_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
+type isMessage_Payload interface {
+	isMessage_Payload()
+}
+
+type Message_CertificateUpdate struct {
+	CertificateUpdate *CertificateUpdate `protobuf:"bytes,2,opt,name=certificate_update,json=certificateUpdate,proto3,oneof"`
+}
+
+type Message_DomainsUpdate struct {
+	DomainsUpdate *DomainsUpdate `protobuf:"bytes,3,opt,name=domains_update,json=domainsUpdate,proto3,oneof"`
+}
+
+type Message_Gap struct {
+	Gap *Gap `protobuf:"bytes,4,opt,name=gap,proto3,oneof"`
+}
+
+type Message_Heartbeat struct {
+	Heartbeat *Heartbeat `protobuf:"bytes,5,opt,name=heartbeat,proto3,oneof"`
+}
+
+func (*Message_CertificateUpdate) isMessage_Payload() {}
+
+func (*Message_DomainsUpdate) isMessage_Payload() {}
+
+func (*Message_Gap) isMessage_Payload() {}
+
+func (*Message_Heartbeat) isMessage_Payload() {}
+
+// CertificateUpdate corresponds to the "certificate_update" message of the full and lite streams.
+type CertificateUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          *Data                  `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CertificateUpdate) Reset() {
+	*x = CertificateUpdate{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CertificateUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CertificateUpdate) ProtoMessage() {}
+
+func (x *CertificateUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CertificateUpdate.ProtoReflect.Descriptor instead.
+func (*CertificateUpdate) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CertificateUpdate) GetData() *Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// DomainsUpdate corresponds to the "dns_entries" message of the domains-only stream and the "new_domains" message
+// of the new domains stream.
+type DomainsUpdate struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Domains []string               `protobuf:"bytes,1,rep,name=domains,proto3" json:"domains,omitempty"`
+	// Set instead of domains if the client requested the domains grouped by registrable domain.
+	Groups        []*DomainGroup `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DomainsUpdate) Reset() {
+	*x = DomainsUpdate{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainsUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainsUpdate) ProtoMessage() {}
+
+func (x *DomainsUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainsUpdate.ProtoReflect.Descriptor instead.
+func (*DomainsUpdate) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DomainsUpdate) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+func (x *DomainsUpdate) GetGroups() []*DomainGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type DomainGroup struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Registrable domain (eTLD+1) of the domains. Empty for IP addresses and invalid domains.
+	RegistrableDomain string   `protobuf:"bytes,1,opt,name=registrable_domain,json=registrableDomain,proto3" json:"registrable_domain,omitempty"`
+	Domains           []string `protobuf:"bytes,2,rep,name=domains,proto3" json:"domains,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DomainGroup) Reset() {
+	*x = DomainGroup{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DomainGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DomainGroup) ProtoMessage() {}
+
+func (x *DomainGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DomainGroup.ProtoReflect.Descriptor instead.
+func (*DomainGroup) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DomainGroup) GetRegistrableDomain() string {
+	if x != nil {
+		return x.RegistrableDomain
+	}
+	return ""
+}
+
+func (x *DomainGroup) GetDomains() []string {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+// Gap notifies the client that entries were dropped because it couldn't keep up.
+type Gap struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Skipped       uint64                 `protobuf:"varint,1,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Gap) Reset() {
+	*x = Gap{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Gap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gap) ProtoMessage() {}
+
+func (x *Gap) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gap.ProtoReflect.Descriptor instead.
+func (*Gap) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Gap) GetSkipped() uint64 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+// Heartbeat is sent periodically if heartbeats are enabled, so clients can detect dead streams.
+type Heartbeat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unix timestamp in seconds.
+	Timestamp float64 `protobuf:"fixed64,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Number of certificates processed by the server since it was started.
+	ProcessedCertificates int64 `protobuf:"varint,2,opt,name=processed_certificates,json=processedCertificates,proto3" json:"processed_certificates,omitempty"`
+	// Number of precertificates processed by the server since it was started.
+	ProcessedPrecertificates int64 `protobuf:"varint,3,opt,name=processed_precertificates,json=processedPrecertificates,proto3" json:"processed_precertificates,omitempty"`
+	// Number of entries the client missed because it couldn't keep up.
+	Skipped       uint64 `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Heartbeat) GetTimestamp() float64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetProcessedCertificates() int64 {
+	if x != nil {
+		return x.ProcessedCertificates
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetProcessedPrecertificates() int64 {
+	if x != nil {
+		return x.ProcessedPrecertificates
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetSkipped() uint64 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+type Data struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CertIndex  uint64                 `protobuf:"varint,1,opt,name=cert_index,json=certIndex,proto3" json:"cert_index,omitempty"`
+	CertLink   string                 `protobuf:"bytes,2,opt,name=cert_link,json=certLink,proto3" json:"cert_link,omitempty"`
+	Chain      []*LeafCert            `protobuf:"bytes,3,rep,name=chain,proto3" json:"chain,omitempty"`
+	LeafCert   *LeafCert              `protobuf:"bytes,4,opt,name=leaf_cert,json=leafCert,proto3" json:"leaf_cert,omitempty"`
+	Seen       float64                `protobuf:"fixed64,5,opt,name=seen,proto3" json:"seen,omitempty"`
+	Source     *Source                `protobuf:"bytes,6,opt,name=source,proto3" json:"source,omitempty"`
+	UpdateType string                 `protobuf:"bytes,7,opt,name=update_type,json=updateType,proto3" json:"update_type,omitempty"`
+	// Results of the certificate linter, only set if linting is enabled.
+	Lint *Lint `protobuf:"bytes,8,opt,name=lint,proto3" json:"lint,omitempty"`
+	// Phishing score of the domains, only set if scoring is enabled.
+	Score         *Score `protobuf:"bytes,9,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Data) Reset() {
+	*x = Data{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Data) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Data) ProtoMessage() {}
+
+func (x *Data) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Data.ProtoReflect.Descriptor instead.
+func (*Data) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Data) GetCertIndex() uint64 {
+	if x != nil {
+		return x.CertIndex
+	}
+	return 0
+}
+
+func (x *Data) GetCertLink() string {
+	if x != nil {
+		return x.CertLink
+	}
+	return ""
+}
+
+func (x *Data) GetChain() []*LeafCert {
+	if x != nil {
+		return x.Chain
+	}
+	return nil
+}
+
+func (x *Data) GetLeafCert() *LeafCert {
+	if x != nil {
+		return x.LeafCert
+	}
+	return nil
+}
+
+func (x *Data) GetSeen() float64 {
+	if x != nil {
+		return x.Seen
+	}
+	return 0
+}
+
+func (x *Data) GetSource() *Source {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *Data) GetUpdateType() string {
+	if x != nil {
+		return x.UpdateType
+	}
+	return ""
+}
+
+func (x *Data) GetLint() *Lint {
+	if x != nil {
+		return x.Lint
+	}
+	return nil
+}
+
+func (x *Data) GetScore() *Score {
+	if x != nil {
+		return x.Score
+	}
+	return nil
+}
+
+type Lint struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Failed checks. Empty if the certificate passed all checks.
+	Findings      []*LintFinding `protobuf:"bytes,1,rep,name=findings,proto3" json:"findings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lint) Reset() {
+	*x = Lint{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lint) ProtoMessage() {}
+
+func (x *Lint) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lint.ProtoReflect.Descriptor instead.
+func (*Lint) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Lint) GetFindings() []*LintFinding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+type LintFinding struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Set   string                 `protobuf:"bytes,2,opt,name=set,proto3" json:"set,omitempty"`
+	// "notice", "warn" or "error".
+	Severity      string `protobuf:"bytes,3,opt,name=severity,proto3" json:"severity,omitempty"`
+	Details       string `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintFinding) Reset() {
+	*x = LintFinding{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintFinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintFinding) ProtoMessage() {}
+
+func (x *LintFinding) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintFinding.ProtoReflect.Descriptor instead.
+func (*LintFinding) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LintFinding) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LintFinding) GetSet() string {
+	if x != nil {
+		return x.Set
+	}
+	return ""
+}
+
+func (x *LintFinding) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *LintFinding) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+type Score struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Highest score of all domains of the certificate.
+	Value         int32          `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Reasons       []*ScoreReason `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Score) Reset() {
+	*x = Score{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Score) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Score) ProtoMessage() {}
+
+func (x *Score) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Score.ProtoReflect.Descriptor instead.
+func (*Score) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Score) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Score) GetReasons() []*ScoreReason {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+type ScoreReason struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Domain        string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	Details       string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
+	Points        int32                  `protobuf:"varint,4,opt,name=points,proto3" json:"points,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoreReason) Reset() {
+	*x = ScoreReason{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreReason) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreReason) ProtoMessage() {}
+
+func (x *ScoreReason) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreReason.ProtoReflect.Descriptor instead.
+func (*ScoreReason) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ScoreReason) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *ScoreReason) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ScoreReason) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *ScoreReason) GetPoints() int32 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+type Source struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Timestamp     float64                `protobuf:"fixed64,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Source) Reset() {
+	*x = Source{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Source) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Source) ProtoMessage() {}
+
+func (x *Source) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Source.ProtoReflect.Descriptor instead.
+func (*Source) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Source) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Source) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Source) GetTimestamp() float64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Source) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type LeafCert struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	AllDomains []string               `protobuf:"bytes,1,rep,name=all_domains,json=allDomains,proto3" json:"all_domains,omitempty"`
+	// Raw DER encoded certificate. Only set on the full stream.
+	Der                []byte      `protobuf:"bytes,2,opt,name=der,proto3" json:"der,omitempty"`
+	Extensions         *Extensions `protobuf:"bytes,3,opt,name=extensions,proto3" json:"extensions,omitempty"`
+	Fingerprint        string      `protobuf:"bytes,4,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Sha1               string      `protobuf:"bytes,5,opt,name=sha1,proto3" json:"sha1,omitempty"`
+	Sha256             string      `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	NotAfter           int64       `protobuf:"varint,7,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	NotBefore          int64       `protobuf:"varint,8,opt,name=not_before,json=notBefore,proto3" json:"not_before,omitempty"`
+	SerialNumber       string      `protobuf:"bytes,9,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	SignatureAlgorithm string      `protobuf:"bytes,10,opt,name=signature_algorithm,json=signatureAlgorithm,proto3" json:"signature_algorithm,omitempty"`
+	Subject            *Subject    `protobuf:"bytes,11,opt,name=subject,proto3" json:"subject,omitempty"`
+	Issuer             *Subject    `protobuf:"bytes,12,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	IsCa               bool        `protobuf:"varint,13,opt,name=is_ca,json=isCa,proto3" json:"is_ca,omitempty"`
+	// The following fields are only set on the full stream.
+	PublicKey             *PublicKey       `protobuf:"bytes,14,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	IpAddresses           []string         `protobuf:"bytes,15,rep,name=ip_addresses,json=ipAddresses,proto3" json:"ip_addresses,omitempty"`
+	EmailAddresses        []string         `protobuf:"bytes,16,rep,name=email_addresses,json=emailAddresses,proto3" json:"email_addresses,omitempty"`
+	NameConstraints       *NameConstraints `protobuf:"bytes,17,opt,name=name_constraints,json=nameConstraints,proto3" json:"name_constraints,omitempty"`
+	PolicyOids            []string         `protobuf:"bytes,18,rep,name=policy_oids,json=policyOids,proto3" json:"policy_oids,omitempty"`
+	CrlDistributionPoints []string         `protobuf:"bytes,19,rep,name=crl_distribution_points,json=crlDistributionPoints,proto3" json:"crl_distribution_points,omitempty"`
+	OcspUrls              []string         `protobuf:"bytes,20,rep,name=ocsp_urls,json=ocspUrls,proto3" json:"ocsp_urls,omitempty"`
+	CaIssuerUrls          []string         `protobuf:"bytes,21,rep,name=ca_issuer_urls,json=caIssuerUrls,proto3" json:"ca_issuer_urls,omitempty"`
+	// DV, OV, IV or EV as indicated by the CA/Browser Forum policy OIDs.
+	ValidationLevel string `protobuf:"bytes,22,opt,name=validation_level,json=validationLevel,proto3" json:"validation_level,omitempty"`
+	ValidityDays    int64  `protobuf:"varint,23,opt,name=validity_days,json=validityDays,proto3" json:"validity_days,omitempty"`
+	Scts            []*SCT `protobuf:"bytes,24,rep,name=scts,proto3" json:"scts,omitempty"`
+	// Normalized domains, set on the lite stream as well.
+	Domains       []*Domain `protobuf:"bytes,25,rep,name=domains,proto3" json:"domains,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeafCert) Reset() {
+	*x = LeafCert{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeafCert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeafCert) ProtoMessage() {}
+
+func (x *LeafCert) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeafCert.ProtoReflect.Descriptor instead.
+func (*LeafCert) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LeafCert) GetAllDomains() []string {
+	if x != nil {
+		return x.AllDomains
+	}
+	return nil
+}
+
+func (x *LeafCert) GetDer() []byte {
+	if x != nil {
+		return x.Der
+	}
+	return nil
+}
+
+func (x *LeafCert) GetExtensions() *Extensions {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+func (x *LeafCert) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *LeafCert) GetSha1() string {
+	if x != nil {
+		return x.Sha1
+	}
+	return ""
+}
+
+func (x *LeafCert) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *LeafCert) GetNotAfter() int64 {
+	if x != nil {
+		return x.NotAfter
+	}
+	return 0
+}
+
+func (x *LeafCert) GetNotBefore() int64 {
+	if x != nil {
+		return x.NotBefore
+	}
+	return 0
+}
+
+func (x *LeafCert) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *LeafCert) GetSignatureAlgorithm() string {
+	if x != nil {
+		return x.SignatureAlgorithm
+	}
+	return ""
+}
+
+func (x *LeafCert) GetSubject() *Subject {
+	if x != nil {
+		return x.Subject
+	}
+	return nil
+}
+
+func (x *LeafCert) GetIssuer() *Subject {
+	if x != nil {
+		return x.Issuer
+	}
+	return nil
+}
+
+func (x *LeafCert) GetIsCa() bool {
+	if x != nil {
+		return x.IsCa
+	}
+	return false
+}
+
+func (x *LeafCert) GetPublicKey() *PublicKey {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *LeafCert) GetIpAddresses() []string {
+	if x != nil {
+		return x.IpAddresses
+	}
+	return nil
+}
+
+func (x *LeafCert) GetEmailAddresses() []string {
+	if x != nil {
+		return x.EmailAddresses
+	}
+	return nil
+}
+
+func (x *LeafCert) GetNameConstraints() *NameConstraints {
+	if x != nil {
+		return x.NameConstraints
+	}
+	return nil
+}
+
+func (x *LeafCert) GetPolicyOids() []string {
+	if x != nil {
+		return x.PolicyOids
+	}
+	return nil
+}
+
+func (x *LeafCert) GetCrlDistributionPoints() []string {
+	if x != nil {
+		return x.CrlDistributionPoints
+	}
+	return nil
+}
+
+func (x *LeafCert) GetOcspUrls() []string {
+	if x != nil {
+		return x.OcspUrls
+	}
+	return nil
+}
+
+func (x *LeafCert) GetCaIssuerUrls() []string {
+	if x != nil {
+		return x.CaIssuerUrls
+	}
+	return nil
+}
+
+func (x *LeafCert) GetValidationLevel() string {
+	if x != nil {
+		return x.ValidationLevel
+	}
+	return ""
+}
+
+func (x *LeafCert) GetValidityDays() int64 {
+	if x != nil {
+		return x.ValidityDays
+	}
+	return 0
+}
+
+func (x *LeafCert) GetScts() []*SCT {
+	if x != nil {
+		return x.Scts
+	}
+	return nil
+}
+
+func (x *LeafCert) GetDomains() []*Domain {
+	if x != nil {
+		return x.Domains
+	}
+	return nil
+}
+
+type Domain struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Lower case domain without the wildcard prefix.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Domain with punycode labels decoded, only set for internationalized domains.
+	Unicode string `protobuf:"bytes,2,opt,name=unicode,proto3" json:"unicode,omitempty"`
+	// Public suffix plus one label (eTLD+1). Empty for IP addresses and invalid domains.
+	RegistrableDomain string `protobuf:"bytes,3,opt,name=registrable_domain,json=registrableDomain,proto3" json:"registrable_domain,omitempty"`
+	Wildcard          bool   `protobuf:"varint,4,opt,name=wildcard,proto3" json:"wildcard,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Domain) Reset() {
+	*x = Domain{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Domain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Domain) ProtoMessage() {}
+
+func (x *Domain) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Domain.ProtoReflect.Descriptor instead.
+func (*Domain) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Domain) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Domain) GetUnicode() string {
+	if x != nil {
+		return x.Unicode
+	}
+	return ""
+}
+
+func (x *Domain) GetRegistrableDomain() string {
+	if x != nil {
+		return x.RegistrableDomain
+	}
+	return ""
+}
+
+func (x *Domain) GetWildcard() bool {
+	if x != nil {
+		return x.Wildcard
+	}
+	return false
+}
+
+// Signed certificate timestamp embedded in a certificate.
+type SCT struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Base64 encoded log ID as found in the log list.
+	LogId string `protobuf:"bytes,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// Name and operator of the log, if it's listed in the log list.
+	LogName            string  `protobuf:"bytes,2,opt,name=log_name,json=logName,proto3" json:"log_name,omitempty"`
+	LogOperator        string  `protobuf:"bytes,3,opt,name=log_operator,json=logOperator,proto3" json:"log_operator,omitempty"`
+	Timestamp          float64 `protobuf:"fixed64,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	SignatureAlgorithm string  `protobuf:"bytes,5,opt,name=signature_algorithm,json=signatureAlgorithm,proto3" json:"signature_algorithm,omitempty"`
+	// Result of the signature verification, unset if the signature was not verified.
+	Verified      *bool `protobuf:"varint,6,opt,name=verified,proto3,oneof" json:"verified,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SCT) Reset() {
+	*x = SCT{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SCT) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SCT) ProtoMessage() {}
+
+func (x *SCT) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SCT.ProtoReflect.Descriptor instead.
+func (*SCT) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SCT) GetLogId() string {
+	if x != nil {
+		return x.LogId
+	}
+	return ""
+}
+
+func (x *SCT) GetLogName() string {
+	if x != nil {
+		return x.LogName
+	}
+	return ""
+}
+
+func (x *SCT) GetLogOperator() string {
+	if x != nil {
+		return x.LogOperator
+	}
+	return ""
+}
+
+func (x *SCT) GetTimestamp() float64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *SCT) GetSignatureAlgorithm() string {
+	if x != nil {
+		return x.SignatureAlgorithm
+	}
+	return ""
+}
+
+func (x *SCT) GetVerified() bool {
+	if x != nil && x.Verified != nil {
+		return *x.Verified
+	}
+	return false
+}
+
+type PublicKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Public key algorithm, e.g. "rsa", "ecdsa" or "ed25519".
+	Algorithm string `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	// Key size in bits.
+	Size int32 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// Elliptic curve of ECDSA keys, e.g. "P-256".
+	Curve string `protobuf:"bytes,3,opt,name=curve,proto3" json:"curve,omitempty"`
+	// Base64 encoded SHA256 hash of the SubjectPublicKeyInfo.
+	SpkiSha256    string `protobuf:"bytes,4,opt,name=spki_sha256,json=spkiSha256,proto3" json:"spki_sha256,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublicKey) Reset() {
+	*x = PublicKey{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublicKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicKey) ProtoMessage() {}
+
+func (x *PublicKey) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicKey.ProtoReflect.Descriptor instead.
+func (*PublicKey) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PublicKey) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *PublicKey) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PublicKey) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+func (x *PublicKey) GetSpkiSha256() string {
+	if x != nil {
+		return x.SpkiSha256
+	}
+	return ""
+}
+
+type NameConstraints struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Critical                bool                   `protobuf:"varint,1,opt,name=critical,proto3" json:"critical,omitempty"`
+	PermittedDnsDomains     []string               `protobuf:"bytes,2,rep,name=permitted_dns_domains,json=permittedDnsDomains,proto3" json:"permitted_dns_domains,omitempty"`
+	ExcludedDnsDomains      []string               `protobuf:"bytes,3,rep,name=excluded_dns_domains,json=excludedDnsDomains,proto3" json:"excluded_dns_domains,omitempty"`
+	PermittedIpRanges       []string               `protobuf:"bytes,4,rep,name=permitted_ip_ranges,json=permittedIpRanges,proto3" json:"permitted_ip_ranges,omitempty"`
+	ExcludedIpRanges        []string               `protobuf:"bytes,5,rep,name=excluded_ip_ranges,json=excludedIpRanges,proto3" json:"excluded_ip_ranges,omitempty"`
+	PermittedEmailAddresses []string               `protobuf:"bytes,6,rep,name=permitted_email_addresses,json=permittedEmailAddresses,proto3" json:"permitted_email_addresses,omitempty"`
+	ExcludedEmailAddresses  []string               `protobuf:"bytes,7,rep,name=excluded_email_addresses,json=excludedEmailAddresses,proto3" json:"excluded_email_addresses,omitempty"`
+	PermittedUriDomains     []string               `protobuf:"bytes,8,rep,name=permitted_uri_domains,json=permittedUriDomains,proto3" json:"permitted_uri_domains,omitempty"`
+	ExcludedUriDomains      []string               `protobuf:"bytes,9,rep,name=excluded_uri_domains,json=excludedUriDomains,proto3" json:"excluded_uri_domains,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *NameConstraints) Reset() {
+	*x = NameConstraints{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NameConstraints) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NameConstraints) ProtoMessage() {}
+
+func (x *NameConstraints) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NameConstraints.ProtoReflect.Descriptor instead.
+func (*NameConstraints) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *NameConstraints) GetCritical() bool {
+	if x != nil {
+		return x.Critical
+	}
+	return false
+}
+
+func (x *NameConstraints) GetPermittedDnsDomains() []string {
+	if x != nil {
+		return x.PermittedDnsDomains
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetExcludedDnsDomains() []string {
+	if x != nil {
+		return x.ExcludedDnsDomains
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetPermittedIpRanges() []string {
+	if x != nil {
+		return x.PermittedIpRanges
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetExcludedIpRanges() []string {
+	if x != nil {
+		return x.ExcludedIpRanges
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetPermittedEmailAddresses() []string {
+	if x != nil {
+		return x.PermittedEmailAddresses
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetExcludedEmailAddresses() []string {
+	if x != nil {
+		return x.ExcludedEmailAddresses
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetPermittedUriDomains() []string {
+	if x != nil {
+		return x.PermittedUriDomains
+	}
+	return nil
+}
+
+func (x *NameConstraints) GetExcludedUriDomains() []string {
+	if x != nil {
+		return x.ExcludedUriDomains
+	}
+	return nil
+}
+
+type Subject struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	C             *string                `protobuf:"bytes,1,opt,name=c,proto3,oneof" json:"c,omitempty"`
+	Cn            *string                `protobuf:"bytes,2,opt,name=cn,proto3,oneof" json:"cn,omitempty"`
+	L             *string                `protobuf:"bytes,3,opt,name=l,proto3,oneof" json:"l,omitempty"`
+	O             *string                `protobuf:"bytes,4,opt,name=o,proto3,oneof" json:"o,omitempty"`
+	Ou            *string                `protobuf:"bytes,5,opt,name=ou,proto3,oneof" json:"ou,omitempty"`
+	St            *string                `protobuf:"bytes,6,opt,name=st,proto3,oneof" json:"st,omitempty"`
+	Aggregated    *string                `protobuf:"bytes,7,opt,name=aggregated,proto3,oneof" json:"aggregated,omitempty"`
+	EmailAddress  *string                `protobuf:"bytes,8,opt,name=email_address,json=emailAddress,proto3,oneof" json:"email_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subject) Reset() {
+	*x = Subject{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subject) ProtoMessage() {}
+
+func (x *Subject) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subject.ProtoReflect.Descriptor instead.
+func (*Subject) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Subject) GetC() string {
+	if x != nil && x.C != nil {
+		return *x.C
+	}
+	return ""
+}
+
+func (x *Subject) GetCn() string {
+	if x != nil && x.Cn != nil {
+		return *x.Cn
+	}
+	return ""
+}
+
+func (x *Subject) GetL() string {
+	if x != nil && x.L != nil {
+		return *x.L
+	}
+	return ""
+}
+
+func (x *Subject) GetO() string {
+	if x != nil && x.O != nil {
+		return *x.O
+	}
+	return ""
+}
+
+func (x *Subject) GetOu() string {
+	if x != nil && x.Ou != nil {
+		return *x.Ou
+	}
+	return ""
+}
+
+func (x *Subject) GetSt() string {
+	if x != nil && x.St != nil {
+		return *x.St
+	}
+	return ""
+}
+
+func (x *Subject) GetAggregated() string {
+	if x != nil && x.Aggregated != nil {
+		return *x.Aggregated
+	}
+	return ""
+}
+
+func (x *Subject) GetEmailAddress() string {
+	if x != nil && x.EmailAddress != nil {
+		return *x.EmailAddress
+	}
+	return ""
+}
+
+type Extensions struct {
+	state                         protoimpl.MessageState `protogen:"open.v1"`
+	AuthorityInfoAccess           *string                `protobuf:"bytes,1,opt,name=authority_info_access,json=authorityInfoAccess,proto3,oneof" json:"authority_info_access,omitempty"`
+	AuthorityKeyIdentifier        *string                `protobuf:"bytes,2,opt,name=authority_key_identifier,json=authorityKeyIdentifier,proto3,oneof" json:"authority_key_identifier,omitempty"`
+	BasicConstraints              *string                `protobuf:"bytes,3,opt,name=basic_constraints,json=basicConstraints,proto3,oneof" json:"basic_constraints,omitempty"`
+	CertificatePolicies           *string                `protobuf:"bytes,4,opt,name=certificate_policies,json=certificatePolicies,proto3,oneof" json:"certificate_policies,omitempty"`
+	CtlSignedCertificateTimestamp *string                `protobuf:"bytes,5,opt,name=ctl_signed_certificate_timestamp,json=ctlSignedCertificateTimestamp,proto3,oneof" json:"ctl_signed_certificate_timestamp,omitempty"`
+	ExtendedKeyUsage              *string                `protobuf:"bytes,6,opt,name=extended_key_usage,json=extendedKeyUsage,proto3,oneof" json:"extended_key_usage,omitempty"`
+	KeyUsage                      *string                `protobuf:"bytes,7,opt,name=key_usage,json=keyUsage,proto3,oneof" json:"key_usage,omitempty"`
+	SubjectAltName                *string                `protobuf:"bytes,8,opt,name=subject_alt_name,json=subjectAltName,proto3,oneof" json:"subject_alt_name,omitempty"`
+	SubjectKeyIdentifier          *string                `protobuf:"bytes,9,opt,name=subject_key_identifier,json=subjectKeyIdentifier,proto3,oneof" json:"subject_key_identifier,omitempty"`
+	CtlPoisonByte                 bool                   `protobuf:"varint,10,opt,name=ctl_poison_byte,json=ctlPoisonByte,proto3" json:"ctl_poison_byte,omitempty"`
+	unknownFields                 protoimpl.UnknownFields
+	sizeCache                     protoimpl.SizeCache
+}
+
+func (x *Extensions) Reset() {
+	*x = Extensions{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Extensions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Extensions) ProtoMessage() {}
+
+func (x *Extensions) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Extensions.ProtoReflect.Descriptor instead.
+func (*Extensions) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Extensions) GetAuthorityInfoAccess() string {
+	if x != nil && x.AuthorityInfoAccess != nil {
+		return *x.AuthorityInfoAccess
+	}
+	return ""
+}
+
+func (x *Extensions) GetAuthorityKeyIdentifier() string {
+	if x != nil && x.AuthorityKeyIdentifier != nil {
+		return *x.AuthorityKeyIdentifier
+	}
+	return ""
+}
+
+func (x *Extensions) GetBasicConstraints() string {
+	if x != nil && x.BasicConstraints != nil {
+		return *x.BasicConstraints
+	}
+	return ""
+}
+
+func (x *Extensions) GetCertificatePolicies() string {
+	if x != nil && x.CertificatePolicies != nil {
+		return *x.CertificatePolicies
+	}
+	return ""
+}
+
+func (x *Extensions) GetCtlSignedCertificateTimestamp() string {
+	if x != nil && x.CtlSignedCertificateTimestamp != nil {
+		return *x.CtlSignedCertificateTimestamp
+	}
+	return ""
+}
+
+func (x *Extensions) GetExtendedKeyUsage() string {
+	if x != nil && x.ExtendedKeyUsage != nil {
+		return *x.ExtendedKeyUsage
+	}
+	return ""
+}
+
+func (x *Extensions) GetKeyUsage() string {
+	if x != nil && x.KeyUsage != nil {
+		return *x.KeyUsage
+	}
+	return ""
+}
+
+func (x *Extensions) GetSubjectAltName() string {
+	if x != nil && x.SubjectAltName != nil {
+		return *x.SubjectAltName
+	}
+	return ""
+}
+
+func (x *Extensions) GetSubjectKeyIdentifier() string {
+	if x != nil && x.SubjectKeyIdentifier != nil {
+		return *x.SubjectKeyIdentifier
+	}
+	return ""
+}
+
+func (x *Extensions) GetCtlPoisonByte() bool {
+	if x != nil {
+		return x.CtlPoisonByte
+	}
+	return false
+}
+
+type ListLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLogsRequest) Reset() {
+	*x = ListLogsRequest{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLogsRequest) ProtoMessage() {}
+
+func (x *ListLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLogsRequest.ProtoReflect.Descriptor instead.
+func (*ListLogsRequest) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{20}
+}
+
+type ListLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Logs          []*Log                 `protobuf:"bytes,1,rep,name=logs,proto3" json:"logs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLogsResponse) Reset() {
+	*x = ListLogsResponse{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLogsResponse) ProtoMessage() {}
+
+func (x *ListLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLogsResponse.ProtoReflect.Descriptor instead.
+func (*ListLogsResponse) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListLogsResponse) GetLogs() []*Log {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+type Log struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Operator string                 `protobuf:"bytes,1,opt,name=operator,proto3" json:"operator,omitempty"`
+	Url      string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Number of certificates processed from this log since the server started.
+	ProcessedCertificates int64 `protobuf:"varint,3,opt,name=processed_certificates,json=processedCertificates,proto3" json:"processed_certificates,omitempty"`
+	// Index of the last processed entry of this log.
+	LastIndex     uint64 `protobuf:"varint,4,opt,name=last_index,json=lastIndex,proto3" json:"last_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Log) Reset() {
+	*x = Log{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Log) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Log) ProtoMessage() {}
+
+func (x *Log) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Log.ProtoReflect.Descriptor instead.
+func (*Log) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Log) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *Log) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Log) GetProcessedCertificates() int64 {
+	if x != nil {
+		return x.ProcessedCertificates
+	}
+	return 0
+}
+
+func (x *Log) GetLastIndex() uint64 {
+	if x != nil {
+		return x.LastIndex
+	}
+	return 0
+}
+
+type GetExampleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stream        Stream                 `protobuf:"varint,1,opt,name=stream,proto3,enum=certstream.v1.Stream" json:"stream,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExampleRequest) Reset() {
+	*x = GetExampleRequest{}
+	mi := &file_certstream_v1_certstream_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExampleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExampleRequest) ProtoMessage() {}
+
+func (x *GetExampleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_certstream_v1_certstream_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExampleRequest.ProtoReflect.Descriptor instead.
+func (*GetExampleRequest) Descriptor() ([]byte, []int) {
+	return file_certstream_v1_certstream_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetExampleRequest) GetStream() Stream {
+	if x != nil {
+		return x.Stream
+	}
+	return Stream_STREAM_UNSPECIFIED
+}
+
+var File_certstream_v1_certstream_proto protoreflect.FileDescriptor
+
+const file_certstream_v1_certstream_proto_rawDesc = "" +
+	"\n" +
+	"\x1ecertstream/v1/certstream.proto\x12\rcertstream.v1\"\xd2\x01\n" +
+	"\x06Filter\x12-\n" +
+	"\x06stream\x18\x01 \x01(\x0e2\x15.certstream.v1.StreamR\x06stream\x12\x18\n" +
+	"\adomains\x18\x02 \x03(\tR\adomains\x12\x19\n" +
+	"\x05since\x18\x03 \x01(\x04H\x00R\x05since\x88\x01\x01\x12\x1b\n" +
+	"\tmin_score\x18\x04 \x01(\x05R\bminScore\x12=\n" +
+	"\x1bgroup_by_registrable_domain\x18\x05 \x01(\bR\x18groupByRegistrableDomainB\b\n" +
+	"\x06_since\"\xa2\x02\n" +
+	"\aMessage\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12Q\n" +
+	"\x12certificate_update\x18\x02 \x01(\v2 .certstream.v1.CertificateUpdateH\x00R\x11certificateUpdate\x12E\n" +
+	"\x0edomains_update\x18\x03 \x01(\v2\x1c.certstream.v1.DomainsUpdateH\x00R\rdomainsUpdate\x12&\n" +
+	"\x03gap\x18\x04 \x01(\v2\x12.certstream.v1.GapH\x00R\x03gap\x128\n" +
+	"\theartbeat\x18\x05 \x01(\v2\x18.certstream.v1.HeartbeatH\x00R\theartbeatB\t\n" +
+	"\apayload\"<\n" +
+	"\x11CertificateUpdate\x12'\n" +
+	"\x04data\x18\x01 \x01(\v2\x13.certstream.v1.DataR\x04data\"]\n" +
+	"\rDomainsUpdate\x12\x18\n" +
+	"\adomains\x18\x01 \x03(\tR\adomains\x122\n" +
+	"\x06groups\x18\x02 \x03(\v2\x1a.certstream.v1.DomainGroupR\x06groups\"V\n" +
+	"\vDomainGroup\x12-\n" +
+	"\x12registrable_domain\x18\x01 \x01(\tR\x11registrableDomain\x12\x18\n" +
+	"\adomains\x18\x02 \x03(\tR\adomains\"\x1f\n" +
+	"\x03Gap\x12\x18\n" +
+	"\askipped\x18\x01 \x01(\x04R\askipped\"\xb7\x01\n" +
+	"\tHeartbeat\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x01R\ttimestamp\x125\n" +
+	"\x16processed_certificates\x18\x02 \x01(\x03R\x15processedCertificates\x12;\n" +
+	"\x19processed_precertificates\x18\x03 \x01(\x03R\x18processedPrecertificates\x12\x18\n" +
+	"\askipped\x18\x04 \x01(\x04R\askipped\"\xe0\x02\n" +
+	"\x04Data\x12\x1d\n" +
+	"\n" +
+	"cert_index\x18\x01 \x01(\x04R\tcertIndex\x12\x1b\n" +
+	"\tcert_link\x18\x02 \x01(\tR\bcertLink\x12-\n" +
+	"\x05chain\x18\x03 \x03(\v2\x17.certstream.v1.LeafCertR\x05chain\x124\n" +
+	"\tleaf_cert\x18\x04 \x01(\v2\x17.certstream.v1.LeafCertR\bleafCert\x12\x12\n" +
+	"\x04seen\x18\x05 \x01(\x01R\x04seen\x12-\n" +
+	"\x06source\x18\x06 \x01(\v2\x15.certstream.v1.SourceR\x06source\x12\x1f\n" +
+	"\vupdate_type\x18\a \x01(\tR\n" +
+	"updateType\x12'\n" +
+	"\x04lint\x18\b \x01(\v2\x13.certstream.v1.LintR\x04lint\x12*\n" +
+	"\x05score\x18\t \x01(\v2\x14.certstream.v1.ScoreR\x05score\">\n" +
+	"\x04Lint\x126\n" +
+	"\bfindings\x18\x01 \x03(\v2\x1a.certstream.v1.LintFindingR\bfindings\"i\n" +
+	"\vLintFinding\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03set\x18\x02 \x01(\tR\x03set\x12\x1a\n" +
+	"\bseverity\x18\x03 \x01(\tR\bseverity\x12\x18\n" +
+	"\adetails\x18\x04 \x01(\tR\adetails\"S\n" +
+	"\x05Score\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x05R\x05value\x124\n" +
+	"\areasons\x18\x02 \x03(\v2\x1a.certstream.v1.ScoreReasonR\areasons\"k\n" +
+	"\vScoreReason\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x18\n" +
+	"\adetails\x18\x03 \x01(\tR\adetails\x12\x16\n" +
+	"\x06points\x18\x04 \x01(\x05R\x06points\"`\n" +
+	"\x06Source\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x01R\ttimestamp\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\"\xe4\a\n" +
+	"\bLeafCert\x12\x1f\n" +
+	"\vall_domains\x18\x01 \x03(\tR\n" +
+	"allDomains\x12\x10\n" +
+	"\x03der\x18\x02 \x01(\fR\x03der\x129\n" +
+	"\n" +
+	"extensions\x18\x03 \x01(\v2\x19.certstream.v1.ExtensionsR\n" +
+	"extensions\x12 \n" +
+	"\vfingerprint\x18\x04 \x01(\tR\vfingerprint\x12\x12\n" +
+	"\x04sha1\x18\x05 \x01(\tR\x04sha1\x12\x16\n" +
+	"\x06sha256\x18\x06 \x01(\tR\x06sha256\x12\x1b\n" +
+	"\tnot_after\x18\a \x01(\x03R\bnotAfter\x12\x1d\n" +
+	"\n" +
+	"not_before\x18\b \x01(\x03R\tnotBefore\x12#\n" +
+	"\rserial_number\x18\t \x01(\tR\fserialNumber\x12/\n" +
+	"\x13signature_algorithm\x18\n" +
+	" \x01(\tR\x12signatureAlgorithm\x120\n" +
+	"\asubject\x18\v \x01(\v2\x16.certstream.v1.SubjectR\asubject\x12.\n" +
+	"\x06issuer\x18\f \x01(\v2\x16.certstream.v1.SubjectR\x06issuer\x12\x13\n" +
+	"\x05is_ca\x18\r \x01(\bR\x04isCa\x127\n" +
+	"\n" +
+	"public_key\x18\x0e \x01(\v2\x18.certstream.v1.PublicKeyR\tpublicKey\x12!\n" +
+	"\fip_addresses\x18\x0f \x03(\tR\vipAddresses\x12'\n" +
+	"\x0femail_addresses\x18\x10 \x03(\tR\x0eemailAddresses\x12I\n" +
+	"\x10name_constraints\x18\x11 \x01(\v2\x1e.certstream.v1.NameConstraintsR\x0fnameConstraints\x12\x1f\n" +
+	"\vpolicy_oids\x18\x12 \x03(\tR\n" +
+	"policyOids\x126\n" +
+	"\x17crl_distribution_points\x18\x13 \x03(\tR\x15crlDistributionPoints\x12\x1b\n" +
+	"\tocsp_urls\x18\x14 \x03(\tR\bocspUrls\x12$\n" +
+	"\x0eca_issuer_urls\x18\x15 \x03(\tR\fcaIssuerUrls\x12)\n" +
+	"\x10validation_level\x18\x16 \x01(\tR\x0fvalidationLevel\x12#\n" +
+	"\rvalidity_days\x18\x17 \x01(\x03R\fvalidityDays\x12&\n" +
+	"\x04scts\x18\x18 \x03(\v2\x12.certstream.v1.SCTR\x04scts\x12/\n" +
+	"\adomains\x18\x19 \x03(\v2\x15.certstream.v1.DomainR\adomains\"\x81\x01\n" +
+	"\x06Domain\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aunicode\x18\x02 \x01(\tR\aunicode\x12-\n" +
+	"\x12registrable_domain\x18\x03 \x01(\tR\x11registrableDomain\x12\x1a\n" +
+	"\bwildcard\x18\x04 \x01(\bR\bwildcard\"\xd7\x01\n" +
+	"\x03SCT\x12\x15\n" +
+	"\x06log_id\x18\x01 \x01(\tR\x05logId\x12\x19\n" +
+	"\blog_name\x18\x02 \x01(\tR\alogName\x12!\n" +
+	"\flog_operator\x18\x03 \x01(\tR\vlogOperator\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x01R\ttimestamp\x12/\n" +
+	"\x13signature_algorithm\x18\x05 \x01(\tR\x12signatureAlgorithm\x12\x1f\n" +
+	"\bverified\x18\x06 \x01(\bH\x00R\bverified\x88\x01\x01B\v\n" +
+	"\t_verified\"t\n" +
+	"\tPublicKey\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x05R\x04size\x12\x14\n" +
+	"\x05curve\x18\x03 \x01(\tR\x05curve\x12\x1f\n" +
+	"\vspki_sha256\x18\x04 \x01(\tR\n" +
+	"spkiSha256\"\xcd\x03\n" +
+	"\x0fNameConstraints\x12\x1a\n" +
+	"\bcritical\x18\x01 \x01(\bR\bcritical\x122\n" +
+	"\x15permitted_dns_domains\x18\x02 \x03(\tR\x13permittedDnsDomains\x120\n" +
+	"\x14excluded_dns_domains\x18\x03 \x03(\tR\x12excludedDnsDomains\x12.\n" +
+	"\x13permitted_ip_ranges\x18\x04 \x03(\tR\x11permittedIpRanges\x12,\n" +
+	"\x12excluded_ip_ranges\x18\x05 \x03(\tR\x10excludedIpRanges\x12:\n" +
+	"\x19permitted_email_addresses\x18\x06 \x03(\tR\x17permittedEmailAddresses\x128\n" +
+	"\x18excluded_email_addresses\x18\a \x03(\tR\x16excludedEmailAddresses\x122\n" +
+	"\x15permitted_uri_domains\x18\b \x03(\tR\x13permittedUriDomains\x120\n" +
+	"\x14excluded_uri_domains\x18\t \x03(\tR\x12excludedUriDomains\"\x98\x02\n" +
+	"\aSubject\x12\x11\n" +
+	"\x01c\x18\x01 \x01(\tH\x00R\x01c\x88\x01\x01\x12\x13\n" +
+	"\x02cn\x18\x02 \x01(\tH\x01R\x02cn\x88\x01\x01\x12\x11\n" +
+	"\x01l\x18\x03 \x01(\tH\x02R\x01l\x88\x01\x01\x12\x11\n" +
+	"\x01o\x18\x04 \x01(\tH\x03R\x01o\x88\x01\x01\x12\x13\n" +
+	"\x02ou\x18\x05 \x01(\tH\x04R\x02ou\x88\x01\x01\x12\x13\n" +
+	"\x02st\x18\x06 \x01(\tH\x05R\x02st\x88\x01\x01\x12#\n" +
+	"\n" +
+	"aggregated\x18\a \x01(\tH\x06R\n" +
+	"aggregated\x88\x01\x01\x12(\n" +
+	"\remail_address\x18\b \x01(\tH\aR\femailAddress\x88\x01\x01B\x04\n" +
+	"\x02_cB\x05\n" +
+	"\x03_cnB\x04\n" +
+	"\x02_lB\x04\n" +
+	"\x02_oB\x05\n" +
+	"\x03_ouB\x05\n" +
+	"\x03_stB\r\n" +
+	"\v_aggregatedB\x10\n" +
+	"\x0e_email_address\"\x83\x06\n" +
+	"\n" +
+	"Extensions\x127\n" +
+	"\x15authority_info_access\x18\x01 \x01(\tH\x00R\x13authorityInfoAccess\x88\x01\x01\x12=\n" +
+	"\x18authority_key_identifier\x18\x02 \x01(\tH\x01R\x16authorityKeyIdentifier\x88\x01\x01\x120\n" +
+	"\x11basic_constraints\x18\x03 \x01(\tH\x02R\x10basicConstraints\x88\x01\x01\x126\n" +
+	"\x14certificate_policies\x18\x04 \x01(\tH\x03R\x13certificatePolicies\x88\x01\x01\x12L\n" +
+	" ctl_signed_certificate_timestamp\x18\x05 \x01(\tH\x04R\x1dctlSignedCertificateTimestamp\x88\x01\x01\x121\n" +
+	"\x12extended_key_usage\x18\x06 \x01(\tH\x05R\x10extendedKeyUsage\x88\x01\x01\x12 \n" +
+	"\tkey_usage\x18\a \x01(\tH\x06R\bkeyUsage\x88\x01\x01\x12-\n" +
+	"\x10subject_alt_name\x18\b \x01(\tH\aR\x0esubjectAltName\x88\x01\x01\x129\n" +
+	"\x16subject_key_identifier\x18\t \x01(\tH\bR\x14subjectKeyIdentifier\x88\x01\x01\x12&\n" +
+	"\x0fctl_poison_byte\x18\n" +
+	" \x01(\bR\rctlPoisonByteB\x18\n" +
+	"\x16_authority_info_accessB\x1b\n" +
+	"\x19_authority_key_identifierB\x14\n" +
+	"\x12_basic_constraintsB\x17\n" +
+	"\x15_certificate_policiesB#\n" +
+	"!_ctl_signed_certificate_timestampB\x15\n" +
+	"\x13_extended_key_usageB\f\n" +
+	"\n" +
+	"_key_usageB\x13\n" +
+	"\x11_subject_alt_nameB\x19\n" +
+	"\x17_subject_key_identifier\"\x11\n" +
+	"\x0fListLogsRequest\":\n" +
+	"\x10ListLogsResponse\x12&\n" +
+	"\x04logs\x18\x01 \x03(\v2\x12.certstream.v1.LogR\x04logs\"\x89\x01\n" +
+	"\x03Log\x12\x1a\n" +
+	"\boperator\x18\x01 \x01(\tR\boperator\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x125\n" +
+	"\x16processed_certificates\x18\x03 \x01(\x03R\x15processedCertificates\x12\x1d\n" +
+	"\n" +
+	"last_index\x18\x04 \x01(\x04R\tlastIndex\"B\n" +
+	"\x11GetExampleRequest\x12-\n" +
+	"\x06stream\x18\x01 \x01(\x0e2\x15.certstream.v1.StreamR\x06stream*n\n" +
+	"\x06Stream\x12\x16\n" +
+	"\x12STREAM_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vSTREAM_FULL\x10\x01\x12\x0f\n" +
+	"\vSTREAM_LITE\x10\x02\x12\x12\n" +
+	"\x0eSTREAM_DOMAINS\x10\x03\x12\x16\n" +
+	"\x12STREAM_NEW_DOMAINS\x10\x042\xe6\x01\n" +
+	"\x11CertstreamService\x12<\n" +
+	"\tSubscribe\x12\x15.certstream.v1.Filter\x1a\x16.certstream.v1.Message0\x01\x12K\n" +
+	"\bListLogs\x12\x1e.certstream.v1.ListLogsRequest\x1a\x1f.certstream.v1.ListLogsResponse\x12F\n" +
+	"\n" +
+	"GetExample\x12 .certstream.v1.GetExampleRequest\x1a\x16.certstream.v1.MessageBKZIgithub.com/d-Rickyy-b/certstream-server-go/api/certstream/v1;certstreamv1b\x06proto3"
+
+var (
+	file_certstream_v1_certstream_proto_rawDescOnce sync.Once
+	file_certstream_v1_certstream_proto_rawDescData []byte
+)
+
+func file_certstream_v1_certstream_proto_rawDescGZIP() []byte {
+	file_certstream_v1_certstream_proto_rawDescOnce.Do(func() {
+		file_certstream_v1_certstream_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_certstream_v1_certstream_proto_rawDesc), len(file_certstream_v1_certstream_proto_rawDesc)))
+	})
+	return file_certstream_v1_certstream_proto_rawDescData
+}
+
+var file_certstream_v1_certstream_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_certstream_v1_certstream_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_certstream_v1_certstream_proto_goTypes = []any{
+	(Stream)(0),               // 0: certstream.v1.Stream
+	(*Filter)(nil),            // 1: certstream.v1.Filter
+	(*Message)(nil),           // 2: certstream.v1.Message
+	(*CertificateUpdate)(nil), // 3: certstream.v1.CertificateUpdate
+	(*DomainsUpdate)(nil),     // 4: certstream.v1.DomainsUpdate
+	(*DomainGroup)(nil),       // 5: certstream.v1.DomainGroup
+	(*Gap)(nil),               // 6: certstream.v1.Gap
+	(*Heartbeat)(nil),         // 7: certstream.v1.Heartbeat
+	(*Data)(nil),              // 8: certstream.v1.Data
+	(*Lint)(nil),              // 9: certstream.v1.Lint
+	(*LintFinding)(nil),       // 10: certstream.v1.LintFinding
+	(*Score)(nil),             // 11: certstream.v1.Score
+	(*ScoreReason)(nil),       // 12: certstream.v1.ScoreReason
+	(*Source)(nil),            // 13: certstream.v1.Source
+	(*LeafCert)(nil),          // 14: certstream.v1.LeafCert
+	(*Domain)(nil),            // 15: certstream.v1.Domain
+	(*SCT)(nil),               // 16: certstream.v1.SCT
+	(*PublicKey)(nil),         // 17: certstream.v1.PublicKey
+	(*NameConstraints)(nil),   // 18: certstream.v1.NameConstraints
+	(*Subject)(nil),           // 19: certstream.v1.Subject
+	(*Extensions)(nil),        // 20: certstream.v1.Extensions
+	(*ListLogsRequest)(nil),   // 21: certstream.v1.ListLogsRequest
+	(*ListLogsResponse)(nil),  // 22: certstream.v1.ListLogsResponse
+	(*Log)(nil),               // 23: certstream.v1.Log
+	(*GetExampleRequest)(nil), // 24: certstream.v1.GetExampleRequest
+}
+var file_certstream_v1_certstream_proto_depIdxs = []int32{
+	0,  // 0: certstream.v1.Filter.stream:type_name -> certstream.v1.Stream
+	3,  // 1: certstream.v1.Message.certificate_update:type_name -> certstream.v1.CertificateUpdate
+	4,  // 2: certstream.v1.Message.domains_update:type_name -> certstream.v1.DomainsUpdate
+	6,  // 3: certstream.v1.Message.gap:type_name -> certstream.v1.Gap
+	7,  // 4: certstream.v1.Message.heartbeat:type_name -> certstream.v1.Heartbeat
+	8,  // 5: certstream.v1.CertificateUpdate.data:type_name -> certstream.v1.Data
+	5,  // 6: certstream.v1.DomainsUpdate.groups:type_name -> certstream.v1.DomainGroup
+	14, // 7: certstream.v1.Data.chain:type_name -> certstream.v1.LeafCert
+	14, // 8: certstream.v1.Data.leaf_cert:type_name -> certstream.v1.LeafCert
+	13, // 9: certstream.v1.Data.source:type_name -> certstream.v1.Source
+	9,  // 10: certstream.v1.Data.lint:type_name -> certstream.v1.Lint
+	11, // 11: certstream.v1.Data.score:type_name -> certstream.v1.Score
+	10, // 12: certstream.v1.Lint.findings:type_name -> certstream.v1.LintFinding
+	12, // 13: certstream.v1.Score.reasons:type_name -> certstream.v1.ScoreReason
+	20, // 14: certstream.v1.LeafCert.extensions:type_name -> certstream.v1.Extensions
+	19, // 15: certstream.v1.LeafCert.subject:type_name -> certstream.v1.Subject
+	19, // 16: certstream.v1.LeafCert.issuer:type_name -> certstream.v1.Subject
+	17, // 17: certstream.v1.LeafCert.public_key:type_name -> certstream.v1.PublicKey
+	18, // 18: certstream.v1.LeafCert.name_constraints:type_name -> certstream.v1.NameConstraints
+	16, // 19: certstream.v1.LeafCert.scts:type_name -> certstream.v1.SCT
+	15, // 20: certstream.v1.LeafCert.domains:type_name -> certstream.v1.Domain
+	23, // 21: certstream.v1.ListLogsResponse.logs:type_name -> certstream.v1.Log
+	0,  // 22: certstream.v1.GetExampleRequest.stream:type_name -> certstream.v1.Stream
+	1,  // 23: certstream.v1.CertstreamService.Subscribe:input_type -> certstream.v1.Filter
+	21, // 24: certstream.v1.CertstreamService.ListLogs:input_type -> certstream.v1.ListLogsRequest
+	24, // 25: certstream.v1.CertstreamService.GetExample:input_type -> certstream.v1.GetExampleRequest
+	2,  // 26: certstream.v1.CertstreamService.Subscribe:output_type -> certstream.v1.Message
+	22, // 27: certstream.v1.CertstreamService.ListLogs:output_type -> certstream.v1.ListLogsResponse
+	2,  // 28: certstream.v1.CertstreamService.GetExample:output_type -> certstream.v1.Message
+	26, // [26:29] is the sub-list for method output_type
+	23, // [23:26] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_certstream_v1_certstream_proto_init() }
+func file_certstream_v1_certstream_proto_init() {
+	if File_certstream_v1_certstream_proto != nil {
+		return
+	}
+	file_certstream_v1_certstream_proto_msgTypes[0].OneofWrappers = []any{}
+	file_certstream_v1_certstream_proto_msgTypes[1].OneofWrappers = []any{
+		(*Message_CertificateUpdate)(nil),
+		(*Message_DomainsUpdate)(nil),
+		(*Message_Gap)(nil),
+		(*Message_Heartbeat)(nil),
+	}
+	file_certstream_v1_certstream_proto_msgTypes[15].OneofWrappers = []any{}
+	file_certstream_v1_certstream_proto_msgTypes[18].OneofWrappers = []any{}
+	file_certstream_v1_certstream_proto_msgTypes[19].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_certstream_v1_certstream_proto_rawDesc), len(file_certstream_v1_certstream_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_certstream_v1_certstream_proto_goTypes,
+		DependencyIndexes: file_certstream_v1_certstream_proto_depIdxs,
+		EnumInfos:         file_certstream_v1_certstream_proto_enumTypes,
+		MessageInfos:      file_certstream_v1_certstream_proto_msgTypes,
+	}.Build()
+	File_certstream_v1_certstream_proto = out.File
+	file_certstream_v1_certstream_proto_goTypes = nil
+	file_certstream_v1_certstream_proto_depIdxs = nil
+}
@@ -0,0 +1,214 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: certstream/v1/certstream.proto
+
+// Typed representation of the certstream messages for the gRPC service.
+// The messages mirror the JSON representation sent to websocket clients (see internal/models/certstream.go).
+
+package certstreamv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CertstreamService_Subscribe_FullMethodName  = "/certstream.v1.CertstreamService/Subscribe"
+	CertstreamService_ListLogs_FullMethodName   = "/certstream.v1.CertstreamService/ListLogs"
+	CertstreamService_GetExample_FullMethodName = "/certstream.v1.CertstreamService/GetExample"
+)
+
+// CertstreamServiceClient is the client API for CertstreamService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CertstreamService streams certificates found in the certificate transparency logs.
+type CertstreamServiceClient interface {
+	// Subscribe streams all new certificates matching the filter until the client cancels the call.
+	Subscribe(ctx context.Context, in *Filter, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Message], error)
+	// ListLogs returns the certificate transparency logs the server is watching.
+	ListLogs(ctx context.Context, in *ListLogsRequest, opts ...grpc.CallOption) (*ListLogsResponse, error)
+	// GetExample returns an example message for the given stream.
+	GetExample(ctx context.Context, in *GetExampleRequest, opts ...grpc.CallOption) (*Message, error)
+}
+
+type certstreamServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCertstreamServiceClient(cc grpc.ClientConnInterface) CertstreamServiceClient {
+	return &certstreamServiceClient{cc}
+}
+
+func (c *certstreamServiceClient) Subscribe(ctx context.Context, in *Filter, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Message], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CertstreamService_ServiceDesc.Streams[0], CertstreamService_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Filter, Message]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CertstreamService_SubscribeClient = grpc.ServerStreamingClient[Message]
+
+func (c *certstreamServiceClient) ListLogs(ctx context.Context, in *ListLogsRequest, opts ...grpc.CallOption) (*ListLogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLogsResponse)
+	err := c.cc.Invoke(ctx, CertstreamService_ListLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certstreamServiceClient) GetExample(ctx context.Context, in *GetExampleRequest, opts ...grpc.CallOption) (*Message, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Message)
+	err := c.cc.Invoke(ctx, CertstreamService_GetExample_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CertstreamServiceServer is the server API for CertstreamService service.
+// All implementations must embed UnimplementedCertstreamServiceServer
+// for forward compatibility.
+//
+// CertstreamService streams certificates found in the certificate transparency logs.
+type CertstreamServiceServer interface {
+	// Subscribe streams all new certificates matching the filter until the client cancels the call.
+	Subscribe(*Filter, grpc.ServerStreamingServer[Message]) error
+	// ListLogs returns the certificate transparency logs the server is watching.
+	ListLogs(context.Context, *ListLogsRequest) (*ListLogsResponse, error)
+	// GetExample returns an example message for the given stream.
+	GetExample(context.Context, *GetExampleRequest) (*Message, error)
+	mustEmbedUnimplementedCertstreamServiceServer()
+}
+
+// UnimplementedCertstreamServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCertstreamServiceServer struct{}
+
+func (UnimplementedCertstreamServiceServer) Subscribe(*Filter, grpc.ServerStreamingServer[Message]) error {
+	return status.Error(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedCertstreamServiceServer) ListLogs(context.Context, *ListLogsRequest) (*ListLogsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListLogs not implemented")
+}
+func (UnimplementedCertstreamServiceServer) GetExample(context.Context, *GetExampleRequest) (*Message, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetExample not implemented")
+}
+func (UnimplementedCertstreamServiceServer) mustEmbedUnimplementedCertstreamServiceServer() {}
+func (UnimplementedCertstreamServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeCertstreamServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CertstreamServiceServer will
+// result in compilation errors.
+type UnsafeCertstreamServiceServer interface {
+	mustEmbedUnimplementedCertstreamServiceServer()
+}
+
+func RegisterCertstreamServiceServer(s grpc.ServiceRegistrar, srv CertstreamServiceServer) {
+	// If the following call panics, it indicates UnimplementedCertstreamServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CertstreamService_ServiceDesc, srv)
+}
+
+func _CertstreamService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Filter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CertstreamServiceServer).Subscribe(m, &grpc.GenericServerStream[Filter, Message]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CertstreamService_SubscribeServer = grpc.ServerStreamingServer[Message]
+
+func _CertstreamService_ListLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertstreamServiceServer).ListLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CertstreamService_ListLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertstreamServiceServer).ListLogs(ctx, req.(*ListLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CertstreamService_GetExample_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExampleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertstreamServiceServer).GetExample(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CertstreamService_GetExample_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertstreamServiceServer).GetExample(ctx, req.(*GetExampleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CertstreamService_ServiceDesc is the grpc.ServiceDesc for CertstreamService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CertstreamService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "certstream.v1.CertstreamService",
+	HandlerType: (*CertstreamServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListLogs",
+			Handler:    _CertstreamService_ListLogs_Handler,
+		},
+		{
+			MethodName: "GetExample",
+			Handler:    _CertstreamService_GetExample_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _CertstreamService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "certstream/v1/certstream.proto",
+}
@@ -2,18 +2,22 @@ package certificatetransparency
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/sha1" //nolint:gosec
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"hash"
-	"log"
 	"math/big"
+	"net"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
 
 	ct "github.com/google/certificate-transparency-go"
@@ -48,7 +52,7 @@ func parseData(entry *ct.RawLogEntry, operatorName, logName, ctURL, logType stri
 	// Convert RawLogEntry to ct.LogEntry
 	logEntry, conversionErr := entry.ToLogEntry()
 	if conversionErr != nil {
-		log.Println("Could not convert entry to LogEntry: ", conversionErr)
+		logger.Debug("Could not convert entry to LogEntry", logging.KeyLogURL, ctURL, "index", entry.Index, logging.KeyError, conversionErr)
 		return models.Data{}, fmt.Errorf("could not convert entry to logentry: %w", conversionErr)
 	}
 
@@ -92,12 +96,21 @@ func parseData(entry *ct.RawLogEntry, operatorName, logName, ctURL, logType stri
 
 	chain, parseErr := parseCertificateChain(logEntry)
 	if parseErr != nil {
-		log.Println("Could not parse certificate chain: ", parseErr)
+		logger.Warn("Could not parse certificate chain", logging.KeyLogURL, ctURL, logging.KeyError, parseErr)
 		return models.Data{}, parseErr
 	}
 
 	data.Chain = chain
 
+	if config.AppConfig.General.SCT.VerifySignatures && logEntry.X509Cert != nil && len(data.LeafCert.SCTs) > 0 && len(logEntry.Chain) > 0 {
+		issuer, issuerErr := x509.ParseCertificate(logEntry.Chain[0].Data)
+		if issuerErr != nil {
+			logger.Debug("Could not parse issuer to verify SCTs", logging.KeyLogURL, ctURL, logging.KeyError, issuerErr)
+		} else {
+			verifyEmbeddedSCTs(&data.LeafCert, cert, issuer)
+		}
+	}
+
 	return data, nil
 }
 
@@ -145,13 +158,27 @@ func leafCertFromX509cert(cert x509.Certificate) models.LeafCert {
 	}
 
 	leafCert.Issuer = buildSubject(cert.Issuer)
+	leafCert.Domains = normalizeDomains(leafCert.AllDomains)
 
 	leafCert.AsDER = base64.StdEncoding.EncodeToString(cert.Raw)
 	leafCert.Fingerprint = calculateSHA1(cert.Raw)
 	leafCert.SHA1 = leafCert.Fingerprint
 	leafCert.SHA256 = calculateSHA256(cert.Raw)
 
-	// TODO fix Extensions - check x509util.go
+	leafCert.PublicKey = buildPublicKey(&cert)
+	leafCert.IPAddresses = formatIPAddresses(cert.IPAddresses)
+	leafCert.EmailAddresses = cert.EmailAddresses
+	leafCert.CRLDistributionPoints = cert.CRLDistributionPoints
+	leafCert.OCSPServers = cert.OCSPServer
+	leafCert.IssuingCertificateURLs = cert.IssuingCertificateURL
+	leafCert.ValidityDays = validityDays(cert.NotBefore, cert.NotAfter)
+
+	for _, policy := range cert.PolicyIdentifiers {
+		leafCert.PolicyOIDs = append(leafCert.PolicyOIDs, policy.String())
+	}
+
+	leafCert.ValidationLevel = validationLevel(leafCert.PolicyOIDs)
+
 	for _, extension := range cert.Extensions {
 		switch {
 		case extension.Id.Equal(x509.OIDExtensionAuthorityKeyId):
@@ -192,6 +219,13 @@ func leafCertFromX509cert(cert x509.Certificate) models.LeafCert {
 
 			result := buf.String()
 			leafCert.Extensions.AuthorityInfoAccess = &result
+		case extension.Id.Equal(x509.OIDExtensionNameConstraints):
+			leafCert.NameConstraints = buildNameConstraints(&cert)
+		case extension.Id.Equal(x509.OIDExtensionCTSCT):
+			scts := parseEmbeddedSCTs(&cert)
+			sctList := formatSCTExtension(scts)
+			leafCert.Extensions.CtlSignedCertificateTimestamp = &sctList
+			leafCert.SCTs = buildSCTs(scts)
 		case extension.Id.Equal(x509.OIDExtensionCTPoison):
 			leafCert.Extensions.CTLPoisonByte = true
 		case extension.Id.Equal(x509.OIDExtensionCertificatePolicies):
@@ -212,6 +246,114 @@ func leafCertFromX509cert(cert x509.Certificate) models.LeafCert {
 	return leafCert
 }
 
+// buildPublicKey describes the public key of the certificate. The size is left out for unsupported key types.
+func buildPublicKey(cert *x509.Certificate) *models.PublicKey {
+	spkiHash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	publicKey := &models.PublicKey{
+		Algorithm:  strings.ToLower(cert.PublicKeyAlgorithm.String()),
+		SPKISHA256: base64.StdEncoding.EncodeToString(spkiHash[:]),
+	}
+
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		publicKey.Size = key.N.BitLen()
+	case *ecdsa.PublicKey:
+		if key.Curve != nil {
+			publicKey.Size = key.Curve.Params().BitSize
+			publicKey.Curve = key.Curve.Params().Name
+		}
+	default:
+		if cert.PublicKeyAlgorithm == x509.Ed25519 {
+			publicKey.Size = 256
+		}
+	}
+
+	if cert.PublicKeyAlgorithm == x509.UnknownPublicKeyAlgorithm {
+		publicKey.Algorithm = "unknown"
+	}
+
+	return publicKey
+}
+
+// buildNameConstraints copies the name constraints of the certificate. IP ranges are given in CIDR notation.
+func buildNameConstraints(cert *x509.Certificate) *models.NameConstraints {
+	return &models.NameConstraints{
+		Critical:                cert.PermittedDNSDomainsCritical,
+		PermittedDNSDomains:     cert.PermittedDNSDomains,
+		ExcludedDNSDomains:      cert.ExcludedDNSDomains,
+		PermittedIPRanges:       formatIPRanges(cert.PermittedIPRanges),
+		ExcludedIPRanges:        formatIPRanges(cert.ExcludedIPRanges),
+		PermittedEmailAddresses: cert.PermittedEmailAddresses,
+		ExcludedEmailAddresses:  cert.ExcludedEmailAddresses,
+		PermittedURIDomains:     cert.PermittedURIDomains,
+		ExcludedURIDomains:      cert.ExcludedURIDomains,
+	}
+}
+
+func formatIPAddresses(ips []net.IP) []string {
+	if len(ips) == 0 {
+		return nil
+	}
+
+	result := make([]string, len(ips))
+	for i, ip := range ips {
+		result[i] = ip.String()
+	}
+
+	return result
+}
+
+func formatIPRanges(ranges []*net.IPNet) []string {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	result := make([]string, len(ranges))
+	for i, ipRange := range ranges {
+		result[i] = ipRange.String()
+	}
+
+	return result
+}
+
+// Policy OIDs of the CA/Browser Forum Baseline Requirements and EV Guidelines indicating the validation level.
+const (
+	policyOIDEV = "2.23.140.1.1"
+	policyOIDDV = "2.23.140.1.2.1"
+	policyOIDOV = "2.23.140.1.2.2"
+	policyOIDIV = "2.23.140.1.2.3"
+)
+
+// validationLevel returns "EV", "OV", "IV" or "DV" according to the CA/Browser Forum policy OIDs of the certificate.
+// If several are present, the highest level wins. CA specific EV policy OIDs are not taken into account.
+func validationLevel(policyOIDs []string) string {
+	for _, level := range []struct{ oid, name string }{
+		{policyOIDEV, "EV"},
+		{policyOIDOV, "OV"},
+		{policyOIDIV, "IV"},
+		{policyOIDDV, "DV"},
+	} {
+		if slices.Contains(policyOIDs, level.oid) {
+			return level.name
+		}
+	}
+
+	return ""
+}
+
+// validityDays returns the validity period in days, rounded up. Since notAfter is inclusive (RFC 5280, section
+// 4.1.2.5), a certificate valid from 00:00:00 until 23:59:59 of the same day is valid for one day.
+func validityDays(notBefore, notAfter time.Time) int64 {
+	if notAfter.Before(notBefore) {
+		return 0
+	}
+
+	seconds := int64(notAfter.Sub(notBefore)/time.Second) + 1
+	const secondsPerDay = 24 * 60 * 60
+
+	return (seconds + secondsPerDay - 1) / secondsPerDay
+}
+
 // buildSubject generates a Subject struct from the given pkix.Name.
 func buildSubject(certSubject pkix.Name) models.Subject {
 	subject := models.Subject{
@@ -300,7 +442,7 @@ func parseName(input []string) *string {
 func calculateHash(data []byte, certHasher hash.Hash) string {
 	_, e := certHasher.Write(data)
 	if e != nil {
-		log.Printf("Error while hashing cert: %s\n", e)
+		logger.Error("Error while hashing cert", logging.KeyError, e)
 		return ""
 	}
 
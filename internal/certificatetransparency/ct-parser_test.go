@@ -0,0 +1,168 @@
+package certificatetransparency
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	stdx509 "crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/certificate-transparency-go/x509"
+)
+
+// createTestCert creates a self-signed certificate from the template and parses it with the CT x509 package.
+func createTestCert(t *testing.T, template *stdx509.Certificate) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := stdx509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+func mustOID(t *testing.T, ints ...uint64) stdx509.OID {
+	t.Helper()
+
+	oid, err := stdx509.OIDFromInts(ints)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return oid
+}
+
+func TestLeafCertFromX509cert_Details(t *testing.T) {
+	notBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, ipRange, _ := net.ParseCIDR("10.0.0.0/8")
+
+	cert := createTestCert(t, &stdx509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(90*24*time.Hour - time.Second),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")},
+		EmailAddresses:        []string{"admin@example.com"},
+		Policies:              []stdx509.OID{mustOID(t, 2, 23, 140, 1, 2, 2)},
+		CRLDistributionPoints: []string{"http://crl.example.com/ca.crl"},
+		OCSPServer:            []string{"http://ocsp.example.com"},
+		IssuingCertificateURL: []string{"http://ca.example.com/ca.der"},
+
+		PermittedDNSDomainsCritical: true,
+		PermittedDNSDomains:         []string{".example.com"},
+		ExcludedIPRanges:            []*net.IPNet{ipRange},
+	})
+
+	leafCert := leafCertFromX509cert(*cert)
+
+	spkiHash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	if pk := leafCert.PublicKey; pk == nil || pk.Algorithm != "ecdsa" || pk.Size != 256 || pk.Curve != "P-256" ||
+		pk.SPKISHA256 != base64.StdEncoding.EncodeToString(spkiHash[:]) {
+		t.Errorf("unexpected public key: %+v", pk)
+	}
+
+	if want := []string{"192.0.2.1", "2001:db8::1"}; !slices.Equal(leafCert.IPAddresses, want) {
+		t.Errorf("want IP addresses %v, got %v", want, leafCert.IPAddresses)
+	}
+
+	if want := []string{"admin@example.com"}; !slices.Equal(leafCert.EmailAddresses, want) {
+		t.Errorf("want email addresses %v, got %v", want, leafCert.EmailAddresses)
+	}
+
+	if nc := leafCert.NameConstraints; nc == nil || !nc.Critical || !slices.Equal(nc.PermittedDNSDomains, []string{".example.com"}) ||
+		!slices.Equal(nc.ExcludedIPRanges, []string{"10.0.0.0/8"}) {
+		t.Errorf("unexpected name constraints: %+v", nc)
+	}
+
+	if want := []string{"2.23.140.1.2.2"}; !slices.Equal(leafCert.PolicyOIDs, want) {
+		t.Errorf("want policy OIDs %v, got %v", want, leafCert.PolicyOIDs)
+	}
+
+	if leafCert.ValidationLevel != "OV" {
+		t.Errorf("want validation level OV, got %q", leafCert.ValidationLevel)
+	}
+
+	if leafCert.CRLDistributionPoints[0] != "http://crl.example.com/ca.crl" || leafCert.OCSPServers[0] != "http://ocsp.example.com" ||
+		leafCert.IssuingCertificateURLs[0] != "http://ca.example.com/ca.der" {
+		t.Errorf("unexpected URLs: %v, %v, %v", leafCert.CRLDistributionPoints, leafCert.OCSPServers, leafCert.IssuingCertificateURLs)
+	}
+
+	if leafCert.ValidityDays != 90 {
+		t.Errorf("want validity of 90 days, got %d", leafCert.ValidityDays)
+	}
+}
+
+func TestLeafCertFromX509cert_NoNameConstraints(t *testing.T) {
+	cert := createTestCert(t, &stdx509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	})
+
+	leafCert := leafCertFromX509cert(*cert)
+
+	if leafCert.NameConstraints != nil || leafCert.IPAddresses != nil || leafCert.ValidationLevel != "" {
+		t.Errorf("want no name constraints, IP addresses and validation level, got %+v", leafCert)
+	}
+}
+
+func TestValidationLevel(t *testing.T) {
+	tests := []struct {
+		oids []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"1.3.6.1.4.1.44947.1.1.1"}, ""},
+		{[]string{"2.23.140.1.2.1", "1.3.6.1.4.1.44947.1.1.1"}, "DV"},
+		{[]string{"2.23.140.1.2.3"}, "IV"},
+		{[]string{"2.23.140.1.2.1", "2.23.140.1.1"}, "EV"},
+	}
+
+	for _, tt := range tests {
+		if got := validationLevel(tt.oids); got != tt.want {
+			t.Errorf("validationLevel(%v) = %q, want %q", tt.oids, got, tt.want)
+		}
+	}
+}
+
+func TestValidityDays(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		notAfter time.Time
+		want     int64
+	}{
+		{start.Add(24*time.Hour - time.Second), 1},
+		{start.Add(24 * time.Hour), 2},
+		{start.Add(397 * 24 * time.Hour), 398},
+		{start.Add(-time.Hour), 0},
+	}
+
+	for _, tt := range tests {
+		if got := validityDays(start, tt.notAfter); got != tt.want {
+			t.Errorf("validityDays(%v, %v) = %d, want %d", start, tt.notAfter, got, tt.want)
+		}
+	}
+}
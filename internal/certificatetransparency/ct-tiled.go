@@ -5,19 +5,28 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/telemetry"
+
 	ct "github.com/google/certificate-transparency-go"
 	"github.com/google/trillian/client/backoff"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/cryptobyte"
 )
 
 const TileSize = 256
 
+// tiledLogger is used by the client for tiled CT logs.
+var tiledLogger = logging.Logger(logging.SubsystemTiled)
+
 // TiledCheckpoint represents the checkpoint information from a tiled CT log.
 type TiledCheckpoint struct {
 	Origin string
@@ -88,7 +97,7 @@ func FetchCheckpoint(ctx context.Context, client *http.Client, baseURL string) (
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: unexpected status code %d", ErrRequestFailed, resp.StatusCode)
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode}
 	}
 
 	lines := make([]string, 0, 3)
@@ -230,6 +239,10 @@ type StaticCTClient struct {
 	backoff    backoff.Backoff
 	userAgent  string
 	ctIndex    uint64
+	// operator is added to the spans of the requests to the log.
+	operator string
+	// onError is called for each error that occurs while monitoring the log, e.g. to record it in the metrics.
+	onError func(error)
 }
 
 func NewStaticCTClient(url string, httpClient *http.Client, userAgent string, startIndex uint64) *StaticCTClient {
@@ -252,7 +265,9 @@ func (s *StaticCTClient) Monitor(ctx context.Context, foundCert func(*ct.RawLogE
 	for {
 		hadNewEntries, err := s.fetchAndProcessTiles(ctx, foundCert, foundPrecert)
 		if err != nil {
-			log.Printf("Error processing tiled log updates for '%s': %s\n", s.url, err)
+			s.logger().Error("Error processing tiled log updates", logging.KeyErrorKind, classifyError(err), logging.KeyError, err)
+			s.recordError(err)
+
 			return err
 		}
 
@@ -275,6 +290,18 @@ func (s *StaticCTClient) Monitor(ctx context.Context, foundCert func(*ct.RawLogE
 	}
 }
 
+// logger returns the logger of the tiled client, which adds the URL and operator of the CT log to each record.
+func (s *StaticCTClient) logger() *slog.Logger {
+	return tiledLogger.With(logging.KeyLogURL, s.url, logging.KeyOperator, s.operator)
+}
+
+// recordError passes the given error to the onError callback if one is set.
+func (s *StaticCTClient) recordError(err error) {
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
+
 // fetchAndProcessTiles checks for new entries in the tiled log and processes them.
 // It returns true if at least one full tile was fetched.
 func (s *StaticCTClient) fetchAndProcessTiles(ctx context.Context, foundCert func(*ct.RawLogEntry), foundPrecert func(*ct.RawLogEntry)) (bool, error) {
@@ -284,6 +311,9 @@ func (s *StaticCTClient) fetchAndProcessTiles(ctx context.Context, foundCert fun
 		return false, fmt.Errorf("fetching checkpoint: %w", fetchErr)
 	}
 
+	// Checkpoints don't contain a timestamp, so the time of the fetch is used
+	metrics.Metrics.SetTreeHead(normalizeCtlogURL(s.url), checkpoint.Size, time.Now())
+
 	currentTreeSize := checkpoint.Size
 	if currentTreeSize <= s.ctIndex {
 		// No new entries
@@ -305,7 +335,8 @@ func (s *StaticCTClient) fetchAndProcessTiles(ctx context.Context, foundCert fun
 	partialSize := currentTreeSize % TileSize
 	if partialSize > 0 {
 		if err := s.processTile(ctx, endTile, partialSize, foundCert, foundPrecert); err != nil {
-			log.Printf("Warning: error processing partial tile %d: %s\n", endTile, err)
+			s.logger().Warn("Error processing partial tile", "tile", endTile, logging.KeyErrorKind, classifyError(err), logging.KeyError, err)
+			s.recordError(err)
 			// Don't return error for partial tiles as they might be incomplete
 		}
 	}
@@ -321,6 +352,8 @@ func (s *StaticCTClient) processTile(ctx context.Context, tileIndex, partialWidt
 		return fmt.Errorf("fetching tile: %w", err)
 	}
 
+	metrics.Metrics.SetLastFetch(normalizeCtlogURL(s.url))
+
 	// Calculate the starting index for entries in this tile
 	baseIndex := tileIndex * TileSize
 
@@ -342,7 +375,7 @@ func (s *StaticCTClient) processTile(ctx context.Context, tileIndex, partialWidt
 		case EntryTypePrecert:
 			foundPrecert(rawEntry)
 		default:
-			log.Printf("Unknown entry type %d in tile %d, skipping entry at index %d\n", leaf.EntryType, tileIndex, entryIndex)
+			s.logger().Warn("Unknown entry type in tile, skipping entry", "entry_type", leaf.EntryType, "tile", tileIndex, "index", entryIndex)
 		}
 
 		// Update the index
@@ -354,7 +387,12 @@ func (s *StaticCTClient) processTile(ctx context.Context, tileIndex, partialWidt
 
 // fetchTile fetches a tile from the tiled CT log using the provided client.
 // If partialWidth > 0, fetches a partial tile with that width (1-255).
-func (s *StaticCTClient) fetchTile(ctx context.Context, tileIndex, partialWidth uint64) ([]TileLeaf, error) {
+func (s *StaticCTClient) fetchTile(ctx context.Context, tileIndex, partialWidth uint64) (leaves []TileLeaf, err error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "fetch tile", telemetry.LogAttributes(normalizeCtlogURL(s.url), s.operator),
+		trace.WithAttributes(attribute.Int64("ct.tile.index", int64(tileIndex)), attribute.Int64("ct.tile.width", int64(partialWidth))),
+	)
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	tilePath := encodeTilePath(tileIndex)
 
 	if partialWidth > 0 {
@@ -377,19 +415,22 @@ func (s *StaticCTClient) fetchTile(ctx context.Context, tileIndex, partialWidth
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: unexpected status code %d", ErrRequestFailed, resp.StatusCode)
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode}
 	}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading tile data: %w", err)
+	data, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return nil, fmt.Errorf("reading tile data: %w", readErr)
 	}
 
 	return ParseTileData(data)
 }
 
 // FetchCheckpoint fetches the checkpoint from a tiled CT log using the provided client.
-func (s *StaticCTClient) FetchCheckpoint(ctx context.Context) (*TiledCheckpoint, error) {
+func (s *StaticCTClient) FetchCheckpoint(ctx context.Context) (checkpoint *TiledCheckpoint, err error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "fetch checkpoint", telemetry.LogAttributes(normalizeCtlogURL(s.url), s.operator))
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	url := s.url + "/checkpoint"
 
 	req, newReqErr := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -406,7 +447,7 @@ func (s *StaticCTClient) FetchCheckpoint(ctx context.Context) (*TiledCheckpoint,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: unexpected status code %d", ErrRequestFailed, resp.StatusCode)
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode}
 	}
 
 	lines := make([]string, 0, 3)
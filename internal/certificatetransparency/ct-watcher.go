@@ -5,8 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -14,8 +15,14 @@ import (
 	"time"
 
 	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/lint"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/newdomains"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/scoring"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/search"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/telemetry"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/web"
 
 	ct "github.com/google/certificate-transparency-go"
@@ -23,8 +30,26 @@ import (
 	"github.com/google/certificate-transparency-go/jsonclient"
 	"github.com/google/certificate-transparency-go/loglist3"
 	"github.com/google/certificate-transparency-go/scanner"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// logger is used by the watcher, its workers and the parser.
+var logger = logging.Logger(logging.SubsystemWatcher)
+
+// linter checks the parsed certificates. It's nil if linting is disabled.
+var linter *lint.Linter
+
+// scorer rates the domains of the parsed certificates. It's nil if scoring is disabled.
+var scorer *scoring.Scorer
+
+// newDomains detects the domains that weren't seen within the configured window. It's nil if the new domains stream
+// is disabled.
+var newDomains *newdomains.Tracker
+
+// searchIndex stores the entries for the search API. It's nil if the search is disabled.
+var searchIndex *search.Index
+
 var UserAgent = fmt.Sprintf("Certstream Server v%s (github.com/d-Rickyy-b/certstream-server-go)", config.Version)
 
 // Watcher is a central component within certstream-server-go. It manages the workers for all the monitored ct logs.
@@ -66,7 +91,7 @@ func (w *Watcher) Start() {
 	if config.AppConfig.General.Recovery.Enabled {
 		ctIndexFilePath, err := filepath.Abs(config.AppConfig.General.Recovery.CTIndexFile)
 		if err != nil {
-			log.Printf("Error getting absolute path for CT index file: '%s', %s\n", config.AppConfig.General.Recovery.CTIndexFile, err)
+			logger.Error("Error getting absolute path for CT index file", "file", config.AppConfig.General.Recovery.CTIndexFile, logging.KeyError, err)
 			return
 		}
 
@@ -78,10 +103,45 @@ func (w *Watcher) Start() {
 		go metrics.Metrics.SaveCertIndexesAtInterval(storageInterval, ctIndexFilePath)
 	}
 
+	if deadLetterConfig := config.AppConfig.General.DeadLetter; deadLetterConfig.Enabled {
+		writer, err := newDeadLetterWriter(deadLetterConfig.File, deadLetterConfig.SampleRate)
+		if err != nil {
+			logger.Error("Could not open dead letter file", logging.KeyError, err)
+		} else {
+			deadLetters = writer
+			defer deadLetters.close()
+		}
+	}
+
+	if config.AppConfig.Lint.Enabled {
+		var err error
+		if linter, err = lint.New(config.AppConfig.Lint.Sets); err != nil {
+			logger.Error("Could not create certificate linter", logging.KeyError, err)
+		}
+	}
+
+	if config.AppConfig.Scoring.Enabled {
+		scorer = scoring.New(config.AppConfig.Scoring)
+	}
+
+	if config.AppConfig.NewDomains.Enabled {
+		w.startNewDomains()
+	}
+
+	if config.AppConfig.Search.Enabled {
+		idx, err := search.Open(config.AppConfig.Search)
+		if err != nil {
+			logger.Error("Could not open search index", "file", config.AppConfig.Search.File, logging.KeyError, err)
+		} else {
+			searchIndex = idx
+			web.SetSearchIndex(idx)
+		}
+	}
+
 	// initialize the watcher with currently available logs
 	w.updateLogs()
 
-	log.Println("Started CT watcher")
+	logger.Info("Started CT watcher")
 
 	go certHandler(w.certChan)
 	go w.watchNewLogs()
@@ -91,6 +151,51 @@ func (w *Watcher) Start() {
 	close(w.certChan)
 }
 
+// startNewDomains creates the new domains tracker, loads the domains seen before the last shutdown and starts
+// a background job to save them at regular intervals.
+func (w *Watcher) startNewDomains() {
+	tracker, err := newdomains.New(config.AppConfig.NewDomains)
+	if err != nil {
+		logger.Error("Could not create new domains tracker", logging.KeyError, err)
+		return
+	}
+
+	filePath := config.AppConfig.NewDomains.File
+
+	err = tracker.Load(filePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		logger.Info("No new domains file found, starting with an empty filter", "file", filePath)
+	case errors.Is(err, newdomains.ErrIncompatibleFile):
+		logger.Warn("New domains file was written with another configuration, starting with an empty filter", "file", filePath)
+	case err != nil:
+		logger.Error("Could not load new domains file, starting with an empty filter", "file", filePath, logging.KeyError, err)
+	}
+
+	newDomains = tracker
+
+	go func() {
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.context.Done():
+				return
+			case <-ticker.C:
+				saveNewDomains()
+			}
+		}
+	}()
+}
+
+// saveNewDomains writes the domains seen within the window to the configured file.
+func saveNewDomains() {
+	if err := newDomains.Save(config.AppConfig.NewDomains.File); err != nil {
+		logger.Error("Failed to save new domains file", logging.KeyError, err)
+	}
+}
+
 // watchNewLogs is a blocking method that continuously monitors the Google log list for new logs and starts
 // a worker for each new log found. It can be stopped by cancelling the watcher's context (e.g. via Stop()).
 func (w *Watcher) watchNewLogs() {
@@ -113,11 +218,13 @@ func (w *Watcher) updateLogs() {
 	// Get a list of urls of all CT logs provided by Google
 	logList, err := getAllLogs(googleLogListFetcher)
 	if err != nil {
-		log.Println(err)
+		logger.Error("Could not get log list", logging.KeyError, err)
 		return
 	}
 
-	log.Println("Checking for new ct logs...")
+	knownLogs.update(logList)
+
+	logger.Info("Checking for new ct logs...")
 
 	// Track all URLs that should be monitored after reconciliation
 	monitoredURLs := make(map[string]struct{})
@@ -134,7 +241,7 @@ func (w *Watcher) updateLogs() {
 			normURL := normalizeCtlogURL(url)
 
 			if transparencyLog.State.LogStatus() == loglist3.RetiredLogStatus {
-				log.Printf("Skipping retired CT log: %s\n", normURL)
+				logger.Debug("Skipping retired CT log", logging.KeyLogURL, normURL, logging.KeyOperator, operator.Name)
 				continue
 			}
 
@@ -152,7 +259,7 @@ func (w *Watcher) updateLogs() {
 			normURL := normalizeCtlogURL(url)
 
 			if transparencyLog.State.LogStatus() == loglist3.RetiredLogStatus {
-				log.Printf("Skipping retired CT log: %s\n", normURL)
+				logger.Debug("Skipping retired CT log", logging.KeyLogURL, normURL, logging.KeyOperator, operator.Name)
 				continue
 			}
 
@@ -164,7 +271,7 @@ func (w *Watcher) updateLogs() {
 		}
 	}
 
-	log.Printf("New ct logs found: %d\n", newCTs)
+	logger.Info("New ct logs found", "count", newCTs)
 
 	// Optionally stop workers for logs not in the monitoredURLs set
 	if *config.AppConfig.General.DropOldLogs {
@@ -173,17 +280,18 @@ func (w *Watcher) updateLogs() {
 		for _, ctWorker := range w.workers {
 			normURL := normalizeCtlogURL(ctWorker.ctURL)
 			if _, ok := monitoredURLs[normURL]; !ok {
-				log.Printf("Stopping worker. CT URL not found in LogList or retired: '%s'\n", ctWorker.ctURL)
+				ctWorker.logger().Info("Stopping worker. CT URL not found in LogList or retired")
 				ctWorker.stop()
 
 				removed++
 			}
 		}
 
-		log.Printf("Removed ct logs: %d\n", removed)
+		logger.Info("Removed ct logs", "count", removed)
 	}
 
-	log.Printf("Currently monitored ct logs: %d\n", len(w.workers))
+	logger.Info("Currently monitored ct logs", "count", len(w.workers))
+	web.Health.SetLogListLoaded()
 }
 
 // addLogIfNew checks if a log is already being watched and adds it if not.
@@ -203,6 +311,12 @@ func (w *Watcher) addLogIfNew(operatorName, description, url string, isTiled boo
 	w.wg.Add(1)
 
 	lastCTIndex := metrics.Metrics.GetCTIndex(normURL)
+	// Normalize CT URL. We remove trailing slashes and prepend "https://" if it's not already there.
+	url = strings.TrimRight(url, "/")
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		url = "https://" + url
+	}
+
 	ctWorker := worker{
 		name:         description,
 		operatorName: operatorName,
@@ -210,6 +324,7 @@ func (w *Watcher) addLogIfNew(operatorName, description, url string, isTiled boo
 		entryChan:    w.certChan,
 		ctIndex:      lastCTIndex,
 		isTiled:      isTiled,
+		log:          logger.With(logging.KeyLogURL, url, logging.KeyOperator, operatorName),
 	}
 	w.workers = append(w.workers, &ctWorker)
 
@@ -229,7 +344,7 @@ func (w *Watcher) addLogIfNew(operatorName, description, url string, isTiled boo
 // discardWorker removes a worker from the watcher's list of workers.
 // This needs to be done when a worker stops.
 func (w *Watcher) discardWorker(worker *worker) {
-	log.Println("Removing worker for CT log:", worker.ctURL)
+	worker.logger().Info("Removing worker for CT log")
 
 	w.workersMu.Lock()
 	defer w.workersMu.Unlock()
@@ -244,7 +359,7 @@ func (w *Watcher) discardWorker(worker *worker) {
 
 // Stop stops the watcher.
 func (w *Watcher) Stop() {
-	log.Printf("Stopping watcher\n")
+	logger.Info("Stopping watcher")
 
 	if config.AppConfig.General.Recovery.Enabled {
 		// Store current CT Indexes before shutting down
@@ -252,7 +367,17 @@ func (w *Watcher) Stop() {
 
 		err := metrics.Metrics.SaveCertIndexes(filePath)
 		if err != nil {
-			log.Printf("Failed to save CT index file: %v\n", err)
+			logger.Error("Failed to save CT index file", logging.KeyError, err)
+		}
+	}
+
+	if newDomains != nil {
+		saveNewDomains()
+	}
+
+	if searchIndex != nil {
+		if err := searchIndex.Close(); err != nil {
+			logger.Error("Failed to close search index", logging.KeyError, err)
 		}
 	}
 
@@ -269,30 +394,30 @@ func (w *Watcher) CreateIndexFile(filePath string) error {
 	httpClient := newHTTPClient()
 	w.context, w.cancelFunc = context.WithCancel(context.Background())
 
-	log.Println("Fetching current STH for all logs...")
+	logger.Info("Fetching current STH for all logs...")
 
 	for _, operator := range logs.Operators {
 		// Iterate over each log of the operator
 		for _, transparencyLog := range operator.Logs {
 			if transparencyLog.State.LogStatus() == loglist3.RetiredLogStatus {
-				log.Printf("Skipping retired CT log: %s\n", transparencyLog.URL)
+				logger.Debug("Skipping retired CT log", logging.KeyLogURL, transparencyLog.URL, logging.KeyOperator, operator.Name)
 				continue
 			}
 
 			normalizedURL := normalizeCtlogURL(transparencyLog.URL)
 			metrics.Metrics.Init(operator.Name, normalizedURL)
-			log.Println("Fetching STH for", normalizedURL)
+			logger.Info("Fetching STH", logging.KeyLogURL, normalizedURL, logging.KeyOperator, operator.Name)
 
 			jsonClient, e := client.New(transparencyLog.URL, httpClient, jsonclient.Options{UserAgent: UserAgent})
 			if e != nil {
-				log.Printf("Error creating JSON client: %s\n", e)
+				logger.Error("Error creating JSON client", logging.KeyLogURL, normalizedURL, logging.KeyError, e)
 				continue
 			}
 
 			sth, getSTHerr := jsonClient.GetSTH(w.context)
 			if getSTHerr != nil {
 				// TODO this can happen due to a 429 error. We should retry the request
-				log.Printf("Could not get STH for '%s': %s\n", transparencyLog.URL, getSTHerr)
+				logger.Warn("Could not get STH", logging.KeyLogURL, normalizedURL, logging.KeyOperator, operator.Name, logging.KeyErrorKind, classifyError(getSTHerr), logging.KeyError, getSTHerr)
 				continue
 			}
 
@@ -301,18 +426,18 @@ func (w *Watcher) CreateIndexFile(filePath string) error {
 
 		for _, transparencyLog := range operator.TiledLogs {
 			if transparencyLog.State.LogStatus() == loglist3.RetiredLogStatus {
-				log.Printf("Skipping retired CT log: %s\n", transparencyLog.MonitoringURL)
+				logger.Debug("Skipping retired CT log", logging.KeyLogURL, transparencyLog.MonitoringURL, logging.KeyOperator, operator.Name)
 				continue
 			}
 
 			normalizedURL := normalizeCtlogURL(transparencyLog.MonitoringURL)
 			metrics.Metrics.Init(operator.Name, normalizedURL)
-			log.Println("Fetching checkpoint for", normalizedURL)
+			logger.Info("Fetching checkpoint", logging.KeyLogURL, normalizedURL, logging.KeyOperator, operator.Name)
 
 			staticCTClient := NewStaticCTClient(transparencyLog.MonitoringURL, httpClient, UserAgent, 0)
 			checkpoint, fetchErr := staticCTClient.FetchCheckpoint(w.context)
 			if fetchErr != nil {
-				log.Printf("Could not get checkpoint for '%s': %s\n", transparencyLog.MonitoringURL, fetchErr)
+				logger.Error("Could not get checkpoint", logging.KeyLogURL, normalizedURL, logging.KeyOperator, operator.Name, logging.KeyErrorKind, classifyError(fetchErr), logging.KeyError, fetchErr)
 				return ErrFetchingSTHFailed
 			}
 
@@ -327,7 +452,7 @@ func (w *Watcher) CreateIndexFile(filePath string) error {
 		return saveErr
 	}
 
-	log.Println("Index file saved to", filePath)
+	logger.Info("Index file saved", "file", filePath)
 
 	return nil
 }
@@ -343,24 +468,20 @@ type worker struct {
 	running      bool
 	cancel       context.CancelFunc
 	isTiled      bool
+	// log adds the URL and operator of the CT log to each record.
+	log *slog.Logger
 }
 
 // startDownloadingCerts starts downloading certificates from the CT log. This method is blocking.
 func (w *worker) startDownloadingCerts(ctx context.Context) {
 	ctx, w.cancel = context.WithCancel(ctx)
 
-	// Normalize CT URL. We remove trailing slashes and prepend "https://" if it's not already there.
-	w.ctURL = strings.TrimRight(w.ctURL, "/")
-	if !strings.HasPrefix(w.ctURL, "https://") && !strings.HasPrefix(w.ctURL, "http://") {
-		w.ctURL = "https://" + w.ctURL
-	}
-
-	log.Printf("Initializing worker for CT log: %s\n", w.ctURL)
-	defer log.Printf("Stopping worker for CT log: %s\n", w.ctURL)
+	w.logger().Info("Initializing worker for CT log")
+	defer w.logger().Info("Stopping worker for CT log")
 
 	w.mu.Lock()
 	if w.running {
-		log.Printf("Worker for '%s' already running\n", w.ctURL)
+		w.logger().Warn("Worker already running")
 		w.mu.Unlock()
 
 		return
@@ -371,7 +492,7 @@ func (w *worker) startDownloadingCerts(ctx context.Context) {
 	w.mu.Unlock()
 
 	for {
-		log.Printf("Starting worker for CT log: %s\n", w.ctURL)
+		w.logger().Info("Starting worker for CT log")
 
 		var workerErr error
 		if w.isTiled {
@@ -383,38 +504,51 @@ func (w *worker) startDownloadingCerts(ctx context.Context) {
 		if workerErr != nil {
 			switch {
 			case errors.Is(workerErr, ErrFetchingSTHFailed):
-				log.Printf("Worker for '%s' failed - could not fetch STH\n", w.ctURL)
+				w.logger().Error("Worker failed - could not fetch STH")
 				return
 			case errors.Is(workerErr, ErrCreatingClient):
-				log.Printf("Worker for '%s' failed - could not create client\n", w.ctURL)
+				w.logger().Error("Worker failed - could not create client")
 				return
 			case strings.Contains(workerErr.Error(), "no such host"):
-				log.Printf("Worker for '%s' failed to resolve host: %s\n", w.ctURL, workerErr)
+				w.logger().Error("Worker failed to resolve host", logging.KeyErrorKind, errorTypeDNS, logging.KeyError, workerErr)
 				return
 			case errors.Is(workerErr, context.Canceled):
-				log.Printf("Worker for '%s' canceled\n", w.ctURL)
+				w.logger().Info("Worker canceled")
 				return
 			}
 
-			log.Printf("Worker for '%s' failed with unexpected error: %s\n", w.ctURL, workerErr)
+			w.logger().Error("Worker failed with unexpected error", logging.KeyErrorKind, classifyError(workerErr), logging.KeyError, workerErr)
 		}
 
 		// Check if the context was canceled
 		select {
 		case <-ctx.Done():
-			log.Printf("Context was cancelled; Stopping worker for '%s'\n", w.ctURL)
+			w.logger().Info("Context was cancelled; Stopping worker")
 
 			return
 		default:
-			log.Printf("Worker for '%s' sleeping for 5 seconds due to error\n", w.ctURL)
+			w.logger().Info("Worker sleeping for 5 seconds due to error")
 			time.Sleep(5 * time.Second)
-			log.Printf("Restarting worker for '%s'\n", w.ctURL)
+			w.logger().Info("Restarting worker")
+			metrics.Prometheus.IncWorkerRestart(w.operatorName, normalizeCtlogURL(w.ctURL))
 
 			continue
 		}
 	}
 }
 
+// logger returns the logger of the worker, which adds the URL and operator of the CT log to each record.
+func (w *worker) logger() *slog.Logger {
+	return w.log
+}
+
+// recordError increments the error metric of the worker's CT log for the type of the given error.
+func (w *worker) recordError(err error) {
+	if errorType := classifyError(err); errorType != "" {
+		metrics.Prometheus.IncLogError(w.operatorName, normalizeCtlogURL(w.ctURL), errorType)
+	}
+}
+
 func (w *worker) stop() {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -426,24 +560,26 @@ func (w *worker) stop() {
 func (w *worker) runStandardWorker(ctx context.Context) error {
 	jsonClient, e := client.New(w.ctURL, newHTTPClient(), jsonclient.Options{UserAgent: UserAgent})
 	if e != nil {
-		log.Printf("Error creating JSON client: %s\n", e)
+		w.logger().Error("Error creating JSON client", logging.KeyError, e)
 		return ErrCreatingClient
 	}
 
+	logClient := observedLogClient{LogClient: jsonClient, url: normalizeCtlogURL(w.ctURL), operator: w.operatorName, onError: w.recordError}
+
 	// If recovery is enabled, we start at the saved index. Otherwise, we start at the latest STH.
 	recoveryEnabled := config.AppConfig.General.Recovery.Enabled
 	if !recoveryEnabled {
-		sth, getSTHerr := jsonClient.GetSTH(ctx)
+		sth, getSTHerr := logClient.GetSTH(ctx)
 		if getSTHerr != nil {
 			// TODO this can happen due to a 429 error. We should retry the request
-			log.Printf("Could not get STH for '%s': %s\n", w.ctURL, getSTHerr)
+			w.logger().Error("Could not get STH", logging.KeyErrorKind, classifyError(getSTHerr), logging.KeyError, getSTHerr)
 			return ErrFetchingSTHFailed
 		}
 		// Start at the latest STH to skip all the past certificates
 		w.ctIndex = sth.TreeSize
 	}
 
-	certScanner := scanner.NewScanner(jsonClient, scanner.ScannerOptions{
+	certScanner := scanner.NewScanner(logClient, scanner.ScannerOptions{
 		FetcherOptions: scanner.FetcherOptions{
 			BatchSize:     100,
 			ParallelFetch: 1,
@@ -456,12 +592,15 @@ func (w *worker) runStandardWorker(ctx context.Context) error {
 		BufferSize:  config.AppConfig.General.BufferSizes.CTLog,
 	})
 
+	web.Health.SetWorkerStreaming(true)
+	defer web.Health.SetWorkerStreaming(false)
+
 	scanErr := certScanner.Scan(ctx, w.foundCertCallback, w.foundPrecertCallback)
 	if scanErr != nil {
 		return fmt.Errorf("error scanning for certificates: %w", scanErr)
 	}
 
-	log.Printf("Exiting worker %s without error!\n", w.ctURL)
+	w.logger().Info("Exiting worker without error")
 
 	return nil
 }
@@ -471,19 +610,26 @@ func (w *worker) runTiledWorker(ctx context.Context) error {
 	httpClient := newHTTPClient()
 
 	staticCTClient := NewStaticCTClient(w.ctURL, httpClient, UserAgent, w.ctIndex)
+	staticCTClient.onError = w.recordError
+	staticCTClient.operator = w.operatorName
 
 	// If recovery is enabled and the CT index is set, we start at the saved index. Otherwise, we start at the latest checkpoint.
 	validSavedCTIndexExists := config.AppConfig.General.Recovery.Enabled
 	if !validSavedCTIndexExists {
 		checkpoint, err := staticCTClient.FetchCheckpoint(ctx)
 		if err != nil {
-			log.Printf("Could not get checkpoint for '%s': %s\n", w.ctURL, err)
+			w.logger().Error("Could not get checkpoint", logging.KeyErrorKind, classifyError(err), logging.KeyError, err)
+			w.recordError(err)
+
 			return ErrFetchingSTHFailed
 		}
 		// Start at the latest checkpoint to skip all the past certificates
 		staticCTClient.ctIndex = checkpoint.Size
 	}
 
+	web.Health.SetWorkerStreaming(true)
+	defer web.Health.SetWorkerStreaming(false)
+
 	err := staticCTClient.Monitor(ctx, w.foundCertCallback, w.foundPrecertCallback)
 	if err != nil {
 		return fmt.Errorf("error scanning for certificates: %w", err)
@@ -492,6 +638,53 @@ func (w *worker) runTiledWorker(ctx context.Context) error {
 	return nil
 }
 
+// observedLogClient wraps the client used by the scanner and records the fetched tree heads, successful
+// fetches of entries and errors in the log metrics. The scanner retries failed fetches itself, so errors
+// would not be visible otherwise.
+type observedLogClient struct {
+	*client.LogClient
+	url      string
+	operator string
+	onError  func(error)
+}
+
+// GetSTH fetches the latest STH and records its tree size and timestamp.
+func (c observedLogClient) GetSTH(ctx context.Context) (*ct.SignedTreeHead, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "get STH", telemetry.LogAttributes(c.url, c.operator))
+
+	sth, err := c.LogClient.GetSTH(ctx)
+	telemetry.EndSpan(span, err)
+
+	if err != nil {
+		c.onError(err)
+		return nil, fmt.Errorf("error fetching STH: %w", err)
+	}
+
+	metrics.Metrics.SetTreeHead(c.url, sth.TreeSize, time.UnixMilli(int64(sth.Timestamp)))
+
+	return sth, nil
+}
+
+// GetRawEntries fetches the entries in the given range and records the successful fetch.
+func (c observedLogClient) GetRawEntries(ctx context.Context, start, end int64) (*ct.GetEntriesResponse, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, "get entries", telemetry.LogAttributes(c.url, c.operator),
+		trace.WithAttributes(attribute.Int64("ct.entries.start", start), attribute.Int64("ct.entries.end", end)),
+	)
+
+	entries, err := c.LogClient.GetRawEntries(ctx, start, end)
+	telemetry.EndSpan(span, err)
+
+	if err != nil {
+		c.onError(err)
+		// The error must not be wrapped, since the scanner checks its type to detect rate limiting
+		return nil, err //nolint:wrapcheck
+	}
+
+	metrics.Metrics.SetLastFetch(c.url)
+
+	return entries, nil
+}
+
 // foundCertCallback is the callback that handles cases where new regular certs are found.
 func (w *worker) foundCertCallback(rawEntry *ct.RawLogEntry) {
 	logType := models.SourceIsRFC6962
@@ -499,9 +692,9 @@ func (w *worker) foundCertCallback(rawEntry *ct.RawLogEntry) {
 		logType = models.SourceIsTiled
 	}
 
-	entry, parseErr := ParseCertstreamEntry(rawEntry, w.operatorName, w.name, w.ctURL, logType)
+	entry, parseErr := w.parseEntry(rawEntry, logType)
 	if parseErr != nil {
-		log.Println("Error parsing certstream entry: ", parseErr)
+		w.handleParseError(rawEntry, parseErr)
 		return
 	}
 
@@ -518,9 +711,9 @@ func (w *worker) foundPrecertCallback(rawEntry *ct.RawLogEntry) {
 		logType = models.SourceIsTiled
 	}
 
-	entry, parseErr := ParseCertstreamEntry(rawEntry, w.operatorName, w.name, w.ctURL, logType)
+	entry, parseErr := w.parseEntry(rawEntry, logType)
 	if parseErr != nil {
-		log.Println("Error parsing certstream entry: ", parseErr)
+		w.handleParseError(rawEntry, parseErr)
 		return
 	}
 
@@ -530,6 +723,48 @@ func (w *worker) foundPrecertCallback(rawEntry *ct.RawLogEntry) {
 	atomic.AddInt64(&metrics.ProcessedPrecerts, 1)
 }
 
+// parseEntry parses the raw entry within a span.
+func (w *worker) parseEntry(rawEntry *ct.RawLogEntry, logType string) (models.Entry, error) {
+	_, span := telemetry.Tracer().Start(context.Background(), "parse entry",
+		telemetry.LogAttributes(normalizeCtlogURL(w.ctURL), w.operatorName),
+		trace.WithAttributes(telemetry.AttrEntryIndex.Int64(rawEntry.Index)),
+	)
+
+	entry, err := ParseCertstreamEntry(rawEntry, w.operatorName, w.name, w.ctURL, logType)
+	if err == nil {
+		lintEntry(&entry)
+
+		if scorer != nil {
+			entry.Data.Score = scorer.Score(entry.Data.LeafCert.AllDomains)
+		}
+	}
+
+	telemetry.EndSpan(span, err)
+
+	return entry, err
+}
+
+// lintEntry runs the certificate linter on the leaf certificate, if enabled, and records the findings per issuer.
+func lintEntry(entry *models.Entry) {
+	if linter == nil {
+		return
+	}
+
+	entry.Data.Lint = linter.Lint(&entry.Data.LeafCert)
+
+	if metrics.LintResults != nil {
+		metrics.LintResults.Observe(metrics.IssuerKey(entry), entry.Data.Lint.Findings)
+	}
+}
+
+// handleParseError logs an entry that could not be parsed, records the failure in the metrics and
+// stores a sample of the failed entries in the dead letter file.
+func (w *worker) handleParseError(rawEntry *ct.RawLogEntry, parseErr error) {
+	w.logger().Warn("Error parsing certstream entry", "index", rawEntry.Index, logging.KeyErrorKind, classifyParseError(parseErr), logging.KeyError, parseErr)
+	metrics.Prometheus.IncLogError(w.operatorName, normalizeCtlogURL(w.ctURL), classifyParseError(parseErr))
+	deadLetters.write(rawEntry, w.ctURL, parseErr)
+}
+
 // certHandler takes the entries out of the entryChan channel and broadcasts them to all clients.
 // Only a single instance of the certHandler runs per certstream server.
 func certHandler(entryChan chan models.Entry) {
@@ -539,14 +774,29 @@ func certHandler(entryChan chan models.Entry) {
 		entry := <-entryChan
 		processed++
 
+		if newDomains != nil {
+			entry.NewDomains = newDomains.Observe(entry.Data.LeafCert.Domains)
+		}
+
+		if searchIndex != nil {
+			searchIndex.Add(entry)
+		}
+
 		if processed%1000 == 0 {
-			log.Printf("Processed %d entries | Queue length: %d\n", processed, len(entryChan))
+			logger.Debug("Processed entries", "count", processed, "queue_length", len(entryChan))
 			// Every thousandth entry, we store one certificate as example
 			web.SetExampleCert(entry)
 		}
 
 		// Run JSON encoding in the background and send the result to the clients.
+		_, span := telemetry.Tracer().Start(context.Background(), "broadcast entry",
+			telemetry.LogAttributes(entry.Data.Source.NormalizedURL, entry.Data.Source.Operator),
+			trace.WithAttributes(telemetry.AttrEntryIndex.Int64(int64(entry.Data.CertIndex))),
+		)
 		web.ClientHandler.Broadcast <- entry
+		span.End()
+		web.Health.EntrySeen()
+		metrics.Prometheus.ObserveEntryAge(time.Since(time.UnixMilli(int64(entry.Data.Source.Timestamp * 1_000))))
 
 		// Update metrics
 		url := entry.Data.Source.NormalizedURL
@@ -554,6 +804,10 @@ func certHandler(entryChan chan models.Entry) {
 		index := entry.Data.CertIndex
 
 		metrics.Metrics.Inc(operator, url, index)
+
+		if metrics.Issuers != nil {
+			metrics.Issuers.Observe(&entry)
+		}
 	}
 }
 
@@ -562,10 +816,13 @@ func certHandler(entryChan chan models.Entry) {
 type LogListFetcher func() (loglist3.LogList, error)
 
 // googleLogListFetcher fetches the list of all CT logs from Google Chromes CT LogList.
-func googleLogListFetcher() (loglist3.LogList, error) {
+func googleLogListFetcher() (logList loglist3.LogList, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	ctx, span := telemetry.Tracer().Start(ctx, "fetch log list", trace.WithAttributes(telemetry.AttrLogURL.String(loglist3.LogListURL)))
+	defer func() { telemetry.EndSpan(span, err) }()
+
 	httpClient := newHTTPClient()
 
 	req, newReqErr := http.NewRequestWithContext(ctx, http.MethodGet, loglist3.LogListURL, nil)
@@ -608,7 +865,7 @@ func getAllLogs(logListFetcher LogListFetcher) (loglist3.LogList, error) {
 
 		allLogs, err = logListFetcher()
 		if err != nil {
-			log.Printf("Error fetching log list from Google: %s\n", err)
+			logger.Error("Error fetching log list from Google", logging.KeyErrorKind, classifyError(err), logging.KeyError, err)
 			return loglist3.LogList{}, fmt.Errorf("failed to fetch log list from Google: %w", err)
 		}
 	}
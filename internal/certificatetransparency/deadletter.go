@@ -0,0 +1,98 @@
+package certificatetransparency
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+)
+
+// deadLetters stores a sample of the entries that could not be parsed. It's nil if the dead letter file is disabled.
+var deadLetters *deadLetterWriter
+
+// deadLetterWriter appends entries that could not be parsed to a file as JSON lines, so that parser bugs can be
+// reported upstream with the raw leaf attached.
+type deadLetterWriter struct {
+	mu         sync.Mutex
+	file       *os.File
+	sampleRate float64
+}
+
+// deadLetter is a single line of the dead letter file. Byte slices are base64 encoded.
+type deadLetter struct {
+	Time  string `json:"time"`
+	Log   string `json:"log"`
+	Index int64  `json:"index"`
+	Error string `json:"error"`
+	// LeafInput is the TLS encoded MerkleTreeLeaf as returned in the leaf_input field of the get-entries endpoint.
+	LeafInput []byte `json:"leaf_input,omitempty"`
+	// Cert is the (pre-)certificate of the entry, if available.
+	Cert []byte `json:"cert,omitempty"`
+}
+
+// newDeadLetterWriter opens the dead letter file at the given path for appending.
+// Only the given fraction of entries passed to write are stored.
+func newDeadLetterWriter(path string, sampleRate float64) (*deadLetterWriter, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dead letter file: %w", err)
+	}
+
+	return &deadLetterWriter{file: file, sampleRate: sampleRate}, nil
+}
+
+// write stores the raw entry together with the parse error, if the entry is sampled. It's a no-op on a nil writer.
+func (d *deadLetterWriter) write(rawEntry *ct.RawLogEntry, ctURL string, parseErr error) {
+	if d == nil || rawEntry == nil || rand.Float64() >= d.sampleRate { //nolint:gosec
+		return
+	}
+
+	letter := deadLetter{
+		Time:  time.Now().UTC().Format(time.RFC3339),
+		Log:   ctURL,
+		Index: rawEntry.Index,
+		Error: parseErr.Error(),
+		Cert:  rawEntry.Cert.Data,
+	}
+
+	leafInput, marshalErr := tls.Marshal(rawEntry.Leaf)
+	if marshalErr != nil {
+		logger.Warn("Could not encode leaf for the dead letter file", logging.KeyLogURL, ctURL, "index", rawEntry.Index, logging.KeyError, marshalErr)
+	} else {
+		letter.LeafInput = leafInput
+	}
+
+	data, err := json.Marshal(letter)
+	if err != nil {
+		logger.Error("Error encoding dead letter", logging.KeyError, err)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.file.Write(append(data, '\n')); err != nil {
+		logger.Error("Error writing dead letter file", logging.KeyError, err)
+	}
+}
+
+// close closes the dead letter file. It's a no-op on a nil writer.
+func (d *deadLetterWriter) close() {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.file.Close(); err != nil {
+		logger.Error("Error closing dead letter file", logging.KeyError, err)
+	}
+}
@@ -0,0 +1,53 @@
+package certificatetransparency
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/tls"
+)
+
+func TestDeadLetterWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead_letter.jsonl")
+
+	writer, err := newDeadLetterWriter(path, 1)
+	if err != nil {
+		t.Fatalf("could not create dead letter writer: %v", err)
+	}
+
+	rawEntry := ConvertTileLeafToRawLogEntry(TileLeaf{EntryType: 0, Timestamp: 1700000000000, X509Entry: []byte{0x30, 0x03, 0x02, 0x01, 0x01}}, 42)
+	writer.write(rawEntry, "https://ct.example.com/log", ErrNoCertFound)
+	writer.close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read dead letter file: %v", err)
+	}
+
+	var letter deadLetter
+	if err := json.Unmarshal(data, &letter); err != nil {
+		t.Fatalf("dead letter file contains invalid JSON: %v", err)
+	}
+
+	if letter.Index != 42 || letter.Log != "https://ct.example.com/log" || letter.Error != ErrNoCertFound.Error() {
+		t.Errorf("unexpected dead letter: %+v", letter)
+	}
+
+	var leaf ct.MerkleTreeLeaf
+	if _, err := tls.Unmarshal(letter.LeafInput, &leaf); err != nil {
+		t.Fatalf("leaf_input is not a valid MerkleTreeLeaf: %v", err)
+	}
+
+	if !bytes.Equal(leaf.TimestampedEntry.X509Entry.Data, rawEntry.Cert.Data) {
+		t.Errorf("want certificate %x in leaf, got %x", rawEntry.Cert.Data, leaf.TimestampedEntry.X509Entry.Data)
+	}
+
+	// A nil writer must be safe to use when the dead letter file is disabled
+	var disabled *deadLetterWriter
+	disabled.write(rawEntry, "https://ct.example.com/log", ErrNoCertFound)
+	disabled.close()
+}
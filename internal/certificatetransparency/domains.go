@@ -0,0 +1,70 @@
+package certificatetransparency
+
+import (
+	"net"
+	"strings"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// normalizeDomains converts the raw domains of a certificate to lower case, removes duplicates, decodes punycode
+// and determines the registrable domain using the Public Suffix List embedded in golang.org/x/net/publicsuffix.
+func normalizeDomains(domains []string) []models.Domain {
+	if len(domains) == 0 {
+		return nil
+	}
+
+	normalized := make([]models.Domain, 0, len(domains))
+	seen := make(map[string]struct{}, len(domains))
+
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if _, ok := seen[domain]; ok || domain == "" {
+			continue
+		}
+
+		seen[domain] = struct{}{}
+
+		name, wildcard := strings.CutPrefix(domain, "*.")
+		normalized = append(normalized, models.Domain{
+			Name:              name,
+			Unicode:           unicodeDomain(name),
+			RegistrableDomain: registrableDomain(name),
+			Wildcard:          wildcard,
+		})
+	}
+
+	return normalized
+}
+
+// unicodeDomain returns the domain with its punycode labels decoded or an empty string if it contains none.
+func unicodeDomain(domain string) string {
+	if !strings.Contains(domain, "xn--") {
+		return ""
+	}
+
+	decoded, err := idna.ToUnicode(domain)
+	if err != nil || decoded == domain {
+		return ""
+	}
+
+	return decoded
+}
+
+// registrableDomain returns the eTLD+1 of the domain or an empty string for IP addresses, public suffixes and
+// names that aren't valid domains, such as common names of client certificates.
+func registrableDomain(domain string) string {
+	if net.ParseIP(domain) != nil || strings.ContainsAny(domain, " /:@") {
+		return ""
+	}
+
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		return ""
+	}
+
+	return registrable
+}
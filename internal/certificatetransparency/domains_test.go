@@ -0,0 +1,43 @@
+package certificatetransparency
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+func TestNormalizeDomains(t *testing.T) {
+	domains := []string{
+		"Example.COM",
+		"example.com",
+		"*.example.com",
+		"www.example.co.uk.",
+		"xn--bcher-kva.example",
+		"foo.xn--p1ai",
+		"user.github.io",
+		"192.0.2.1",
+		"co.uk",
+		"Some Name",
+	}
+
+	want := []models.Domain{
+		{Name: "example.com", RegistrableDomain: "example.com"},
+		{Name: "example.com", RegistrableDomain: "example.com", Wildcard: true},
+		{Name: "www.example.co.uk", RegistrableDomain: "example.co.uk"},
+		{Name: "xn--bcher-kva.example", Unicode: "bücher.example", RegistrableDomain: "xn--bcher-kva.example"},
+		{Name: "foo.xn--p1ai", Unicode: "foo.рф", RegistrableDomain: "foo.xn--p1ai"},
+		{Name: "user.github.io", RegistrableDomain: "user.github.io"},
+		{Name: "192.0.2.1"},
+		{Name: "co.uk"},
+		{Name: "some name"},
+	}
+
+	if got := normalizeDomains(domains); !slices.Equal(got, want) {
+		t.Errorf("unexpected domains:\ngot:  %+v\nwant: %+v", got, want)
+	}
+
+	if got := normalizeDomains([]string{}); got != nil {
+		t.Errorf("want nil for no domains, got %+v", got)
+	}
+}
@@ -1,6 +1,14 @@
 package certificatetransparency
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/google/certificate-transparency-go/jsonclient"
+)
 
 var (
 	ErrCreatingClient          = errors.New("failed to create JSON client")
@@ -13,3 +21,75 @@ var (
 	ErrEntryNil                = errors.New("entry is nil")
 	ErrNoCertFound             = errors.New("no certificate found")
 )
+
+// Types of errors used as label for the per log error metrics.
+const (
+	errorTypeTimeout     = "timeout"
+	errorTypeDNS         = "dns"
+	errorTypeInvalidTile = "invalid_tile"
+	errorTypeParseNoCert = "parse_no_cert"
+	errorTypeParseX509   = "parse_x509"
+	errorTypeOther       = "other"
+)
+
+// HTTPStatusError is returned when a request to a tiled CT log responds with an unexpected status code.
+// It matches ErrRequestFailed with errors.Is.
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status code %d", ErrRequestFailed, e.StatusCode)
+}
+
+func (e *HTTPStatusError) Unwrap() error {
+	return ErrRequestFailed
+}
+
+// classifyError returns the type of an error that occurred while fetching from a CT log, e.g. "http_5xx" or "timeout".
+// It returns an empty string for errors caused by canceling the context, since those are no failures.
+func classifyError(err error) string {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return ""
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return httpStatusClass(statusErr.StatusCode)
+	}
+
+	var rspErr jsonclient.RspError
+	if errors.As(err, &rspErr) && rspErr.StatusCode != 0 {
+		return httpStatusClass(rspErr.StatusCode)
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) || strings.Contains(err.Error(), "no such host") {
+		return errorTypeDNS
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return errorTypeTimeout
+	}
+
+	if errors.Is(err, ErrInvalidDataTile) {
+		return errorTypeInvalidTile
+	}
+
+	return errorTypeOther
+}
+
+// classifyParseError returns the type of an error returned by ParseCertstreamEntry.
+func classifyParseError(err error) string {
+	if errors.Is(err, ErrNoCertFound) {
+		return errorTypeParseNoCert
+	}
+
+	return errorTypeParseX509
+}
+
+// httpStatusClass returns the class of an HTTP status code, e.g. "http_4xx" for 429.
+func httpStatusClass(statusCode int) string {
+	return fmt.Sprintf("http_%dxx", statusCode/100)
+}
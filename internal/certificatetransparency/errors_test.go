@@ -0,0 +1,38 @@
+package certificatetransparency
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/google/certificate-transparency-go/jsonclient"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{fmt.Errorf("error scanning: %w", context.Canceled), ""},
+		{&HTTPStatusError{StatusCode: 404}, "http_4xx"},
+		{fmt.Errorf("fetching tile: %w", &HTTPStatusError{StatusCode: 503}), "http_5xx"},
+		{jsonclient.RspError{StatusCode: 429, Err: fmt.Errorf("got HTTP Status %q", "429 Too Many Requests")}, "http_4xx"},
+		{&net.DNSError{Err: "no such host", Name: "ct.example.com", IsNotFound: true}, "dns"},
+		{fmt.Errorf("fetching checkpoint: %w", context.DeadlineExceeded), "timeout"},
+		{&net.DNSError{Err: "i/o timeout", IsTimeout: true}, "dns"},
+		{fmt.Errorf("processing tile 1: %w", fmt.Errorf("x509_entry: %w", ErrInvalidDataTile)), "invalid_tile"},
+		{ErrUnknownEntryType, "other"},
+	}
+
+	for _, test := range tests {
+		if got := classifyError(test.err); got != test.want {
+			t.Errorf("classifyError(%v) = %q, want %q", test.err, got, test.want)
+		}
+	}
+
+	if got := classifyParseError(ErrNoCertFound); got != "parse_no_cert" {
+		t.Errorf("classifyParseError(ErrNoCertFound) = %q, want %q", got, "parse_no_cert")
+	}
+}
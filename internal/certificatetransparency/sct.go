@@ -0,0 +1,205 @@
+package certificatetransparency
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/ctutil"
+	"github.com/google/certificate-transparency-go/loglist3"
+	"github.com/google/certificate-transparency-go/tls"
+	"github.com/google/certificate-transparency-go/x509"
+)
+
+// knownLogs maps the log IDs found in SCTs to the logs of the log list.
+var knownLogs = &logRegistry{logs: make(map[[32]byte]knownLog)}
+
+// knownLog is a CT log as listed in the log list.
+type knownLog struct {
+	name     string
+	operator string
+	// verifier verifies the signatures of the log. It's nil if the log's key could not be parsed.
+	verifier *ct.SignatureVerifier
+}
+
+// logRegistry contains all logs of the log list by their log ID, including retired ones, since certificates
+// carry SCTs of logs that were retired in the meantime.
+type logRegistry struct {
+	mu   sync.RWMutex
+	logs map[[32]byte]knownLog
+}
+
+// update adds the logs of the given log list to the registry.
+func (r *logRegistry) update(logList loglist3.LogList) {
+	logs := make(map[[32]byte]knownLog)
+
+	for _, operator := range logList.Operators {
+		for _, ctLog := range operator.Logs {
+			addKnownLog(logs, operator.Name, ctLog.Description, ctLog.LogID, ctLog.Key)
+		}
+
+		for _, ctLog := range operator.TiledLogs {
+			addKnownLog(logs, operator.Name, ctLog.Description, ctLog.LogID, ctLog.Key)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for logID, ctLog := range logs {
+		r.logs[logID] = ctLog
+	}
+}
+
+func addKnownLog(logs map[[32]byte]knownLog, operatorName, description string, logID, key []byte) {
+	if len(logID) != 32 {
+		return
+	}
+
+	ctLog := knownLog{name: description, operator: operatorName}
+
+	publicKey, err := x509.ParsePKIXPublicKey(key)
+	if err == nil {
+		ctLog.verifier, err = ct.NewSignatureVerifier(publicKey)
+	}
+
+	if err != nil {
+		logger.Debug("Could not parse key of CT log", "log", description, logging.KeyOperator, operatorName, logging.KeyError, err)
+	}
+
+	logs[[32]byte(logID)] = ctLog
+}
+
+// get returns the log with the given log ID.
+func (r *logRegistry) get(logID [32]byte) (knownLog, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ctLog, ok := r.logs[logID]
+
+	return ctLog, ok
+}
+
+// parseEmbeddedSCTs decodes the SCTs embedded in the certificate. SCTs that can't be decoded are skipped.
+func parseEmbeddedSCTs(cert *x509.Certificate) []*ct.SignedCertificateTimestamp {
+	scts := make([]*ct.SignedCertificateTimestamp, 0, len(cert.SCTList.SCTList))
+
+	for _, serializedSCT := range cert.SCTList.SCTList {
+		var sct ct.SignedCertificateTimestamp
+		if _, err := tls.Unmarshal(serializedSCT.Val, &sct); err != nil {
+			logger.Debug("Could not decode embedded SCT", logging.KeyError, err)
+			continue
+		}
+
+		scts = append(scts, &sct)
+	}
+
+	return scts
+}
+
+// buildSCTs converts the SCTs to their representation in the LeafCert. The log IDs are resolved via the log list.
+func buildSCTs(scts []*ct.SignedCertificateTimestamp) []models.SCT {
+	if len(scts) == 0 {
+		return nil
+	}
+
+	result := make([]models.SCT, len(scts))
+
+	for i, sct := range scts {
+		result[i] = models.SCT{
+			LogID:              base64.StdEncoding.EncodeToString(sct.LogID.KeyID[:]),
+			Timestamp:          float64(sct.Timestamp) / 1_000,
+			SignatureAlgorithm: formatSCTSignatureAlgorithm(sct.Signature.Algorithm),
+		}
+
+		if ctLog, ok := knownLogs.get(sct.LogID.KeyID); ok {
+			result[i].LogName = ctLog.name
+			result[i].LogOperator = ctLog.operator
+		}
+	}
+
+	return result
+}
+
+// verifyEmbeddedSCTs verifies the signatures of the SCTs embedded in the certificate and sets the Verified field of
+// the corresponding models.SCT. SCTs of unknown logs are left untouched. The issuer is needed, since the signature
+// covers the hash of the issuer's key.
+func verifyEmbeddedSCTs(leafCert *models.LeafCert, cert, issuer *x509.Certificate) {
+	scts := parseEmbeddedSCTs(cert)
+	if len(scts) != len(leafCert.SCTs) {
+		return
+	}
+
+	chain := []*x509.Certificate{cert, issuer}
+
+	for i, sct := range scts {
+		ctLog, ok := knownLogs.get(sct.LogID.KeyID)
+		if !ok || ctLog.verifier == nil {
+			continue
+		}
+
+		err := ctutil.VerifySCTWithVerifier(ctLog.verifier, chain, sct, true)
+		if err != nil {
+			logger.Debug("Invalid SCT signature", "log", ctLog.name, "serial_number", leafCert.SerialNumber, logging.KeyError, err)
+		}
+
+		verified := err == nil
+		leafCert.SCTs[i].Verified = &verified
+	}
+}
+
+// formatSCTExtension formats the SCTs the way OpenSSL prints the SCT list extension.
+func formatSCTExtension(scts []*ct.SignedCertificateTimestamp) string {
+	var builder strings.Builder
+
+	for _, sct := range scts {
+		builder.WriteString("Signed Certificate Timestamp:\n")
+		fmt.Fprintf(&builder, "    Version   : v%d (0x%x)\n", sct.SCTVersion+1, int(sct.SCTVersion))
+		builder.WriteString("    Log ID    : ")
+		builder.WriteString(formatHex(sct.LogID.KeyID[:]))
+		builder.WriteString("\n    Timestamp : ")
+		builder.WriteString(time.UnixMilli(int64(sct.Timestamp)).UTC().Format("Jan _2 15:04:05.000 2006 GMT")) //nolint:gosec
+		builder.WriteString("\n    Extensions: ")
+
+		if len(sct.Extensions) == 0 {
+			builder.WriteString("none")
+		} else {
+			builder.WriteString(formatHex(sct.Extensions))
+		}
+
+		builder.WriteString("\n    Signature : ")
+		builder.WriteString(formatSCTSignatureAlgorithm(sct.Signature.Algorithm))
+		builder.WriteString("\n                ")
+		builder.WriteString(formatHex(sct.Signature.Signature))
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// formatSCTSignatureAlgorithm formats the algorithm the same way as the signature algorithm of certificates,
+// e.g. "sha256, ecdsa".
+func formatSCTSignatureAlgorithm(algorithm tls.SignatureAndHashAlgorithm) string {
+	return strings.ToLower(algorithm.Hash.String() + ", " + algorithm.Signature.String())
+}
+
+// formatHex formats the bytes as upper case hex with colons, e.g. "0A:1B".
+func formatHex(data []byte) string {
+	var builder strings.Builder
+
+	for i, b := range data {
+		if i > 0 {
+			builder.WriteByte(':')
+		}
+
+		fmt.Fprintf(&builder, "%02X", b)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,226 @@
+package certificatetransparency
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	stdx509 "crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	ct "github.com/google/certificate-transparency-go"
+	"github.com/google/certificate-transparency-go/loglist3"
+	"github.com/google/certificate-transparency-go/tls"
+	"github.com/google/certificate-transparency-go/x509"
+	"github.com/google/certificate-transparency-go/x509util"
+)
+
+// sctTestSetup contains a CA, a CT log known to the registry and a leaf template signed by the CA.
+type sctTestSetup struct {
+	caKey    *ecdsa.PrivateKey
+	caCert   *stdx509.Certificate
+	logKey   *ecdsa.PrivateKey
+	logID    [32]byte
+	template *stdx509.Certificate
+	leafKey  *ecdsa.PrivateKey
+}
+
+func newSCTTestSetup(t *testing.T) *sctTestSetup {
+	t.Helper()
+
+	s := &sctTestSetup{caKey: mustGenerateKey(t), logKey: mustGenerateKey(t), leafKey: mustGenerateKey(t)}
+
+	caTemplate := &stdx509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              stdx509.KeyUsageCertSign,
+	}
+
+	caDER, err := stdx509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &s.caKey.PublicKey, s.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.caCert, err = stdx509.ParseCertificate(caDER); err != nil {
+		t.Fatal(err)
+	}
+
+	logSPKI, err := stdx509.MarshalPKIXPublicKey(&s.logKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.logID = sha256.Sum256(logSPKI)
+	knownLogs.update(loglist3.LogList{Operators: []*loglist3.Operator{{
+		Name: "Test operator",
+		Logs: []*loglist3.Log{{Description: "Test log", LogID: s.logID[:], Key: logSPKI}},
+	}}})
+
+	s.template = &stdx509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	return s
+}
+
+func mustGenerateKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+// issue creates a certificate from the template, signed by the CA, and returns it together with the parsed CA.
+func (s *sctTestSetup) issue(t *testing.T, template *stdx509.Certificate) (*x509.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	der, err := stdx509.CreateCertificate(rand.Reader, template, s.caCert, &s.leafKey.PublicKey, s.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer, err := x509.ParseCertificate(s.caCert.Raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, issuer
+}
+
+// issueWithSCT issues a certificate with an SCT of the test log embedded. Since the TBSCertificate is created
+// deterministically, the SCT can be signed over the certificate issued without the SCT list.
+func (s *sctTestSetup) issueWithSCT(t *testing.T, timestamp uint64) (*x509.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	precert, issuer := s.issue(t, s.template)
+
+	leaf := ct.MerkleTreeLeaf{
+		LeafType: ct.TimestampedEntryLeafType,
+		TimestampedEntry: &ct.TimestampedEntry{
+			EntryType: ct.PrecertLogEntryType,
+			Timestamp: timestamp,
+			PrecertEntry: &ct.PreCert{
+				IssuerKeyHash:  sha256.Sum256(issuer.RawSubjectPublicKeyInfo),
+				TBSCertificate: precert.RawTBSCertificate,
+			},
+		},
+	}
+	sct := ct.SignedCertificateTimestamp{SCTVersion: ct.V1, LogID: ct.LogID{KeyID: s.logID}, Timestamp: timestamp}
+
+	signatureInput, err := ct.SerializeSCTSignatureInput(sct, ct.LogEntry{Leaf: leaf})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256(signatureInput)
+
+	signature, err := s.logKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sct.Signature = ct.DigitallySigned{
+		Algorithm: tls.SignatureAndHashAlgorithm{Hash: tls.SHA256, Signature: tls.ECDSA},
+		Signature: signature,
+	}
+
+	sctList, err := x509util.MarshalSCTsIntoSCTList([]*ct.SignedCertificateTimestamp{&sct})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serializedList, err := tls.Marshal(*sctList)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extensionValue, err := asn1.Marshal(serializedList)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := *s.template
+	template.ExtraExtensions = []pkix.Extension{{Id: asn1.ObjectIdentifier(x509.OIDExtensionCTSCT), Value: extensionValue}}
+
+	return s.issue(t, &template)
+}
+
+func TestLeafCertFromX509cert_SCTs(t *testing.T) {
+	setup := newSCTTestSetup(t)
+	cert, _ := setup.issueWithSCT(t, 1700000000123)
+
+	leafCert := leafCertFromX509cert(*cert)
+
+	if len(leafCert.SCTs) != 1 {
+		t.Fatalf("want 1 SCT, got %d", len(leafCert.SCTs))
+	}
+
+	sct := leafCert.SCTs[0]
+	if sct.LogID != base64.StdEncoding.EncodeToString(setup.logID[:]) || sct.LogName != "Test log" || sct.LogOperator != "Test operator" {
+		t.Errorf("unexpected log of SCT: %+v", sct)
+	}
+
+	if sct.Timestamp != 1700000000.123 || sct.SignatureAlgorithm != "sha256, ecdsa" || sct.Verified != nil {
+		t.Errorf("unexpected SCT: %+v", sct)
+	}
+
+	extension := leafCert.Extensions.CtlSignedCertificateTimestamp
+	if extension == nil || !strings.Contains(*extension, "Timestamp : Nov 14 22:13:20.123 2023 GMT") {
+		t.Errorf("unexpected SCT extension: %v", extension)
+	}
+}
+
+func TestVerifyEmbeddedSCTs(t *testing.T) {
+	setup := newSCTTestSetup(t)
+	cert, issuer := setup.issueWithSCT(t, 1700000000123)
+
+	leafCert := leafCertFromX509cert(*cert)
+	verifyEmbeddedSCTs(&leafCert, cert, issuer)
+
+	if verified := leafCert.SCTs[0].Verified; verified == nil || !*verified {
+		t.Errorf("want verified SCT, got %v", verified)
+	}
+
+	// The SCT was signed for a certificate issued by the CA, so verification fails with another issuer
+	otherIssuer, _ := newSCTTestSetup(t).issue(t, setup.caCert)
+	leafCert = leafCertFromX509cert(*cert)
+	verifyEmbeddedSCTs(&leafCert, cert, otherIssuer)
+
+	if verified := leafCert.SCTs[0].Verified; verified == nil || *verified {
+		t.Errorf("want unverified SCT, got %v", verified)
+	}
+}
+
+func TestLeafCertFromX509cert_NoSCTs(t *testing.T) {
+	cert, _ := newSCTTestSetup(t).issue(t, &stdx509.Certificate{SerialNumber: big.NewInt(3), NotAfter: time.Now()})
+
+	leafCert := leafCertFromX509cert(*cert)
+
+	if leafCert.SCTs != nil || leafCert.Extensions.CtlSignedCertificateTimestamp != nil {
+		t.Errorf("want no SCTs, got %+v", leafCert.SCTs)
+	}
+}
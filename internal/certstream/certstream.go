@@ -5,21 +5,29 @@ package certstream
 // It also handles signals for graceful shutdown of the server.
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/d-Rickyy-b/certstream-server-go/internal/certificatetransparency"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/telemetry"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/web"
 )
 
+// logger is used for messages concerning the whole server, e.g. starting and stopping it.
+var logger = logging.Logger(logging.SubsystemServer)
+
 type Certstream struct {
 	webserver     *web.Server
 	metricsServer *web.Server
+	grpcServer    *web.GRPCServer
+	telemetry     *telemetry.Telemetry
 	watcher       *certificatetransparency.Watcher
 	config        config.Config
 }
@@ -35,6 +43,10 @@ func NewRawCertstream(config config.Config) *Certstream {
 func NewCertstreamServer(config config.Config) (*Certstream, error) {
 	cs := NewRawCertstream(config)
 
+	if err := logging.Setup(config.Logging); err != nil {
+		return nil, fmt.Errorf("error setting up logging: %w", err)
+	}
+
 	// Initialize the webserver used for the websocket server
 	webserver := web.NewWebsocketServer(
 		config.Webserver.ListenAddr,
@@ -45,8 +57,35 @@ func NewCertstreamServer(config config.Config) (*Certstream, error) {
 	cs.webserver = webserver
 	cs.watcher = certificatetransparency.NewWatcher()
 
+	if config.IssuerStats.Enabled {
+		metrics.Issuers = metrics.NewIssuerStats(config.IssuerStats)
+	}
+
+	if config.Lint.Enabled {
+		metrics.LintResults = metrics.NewLintStats(config.Lint)
+	}
+
 	// Setup metrics server
 	cs.setupMetrics(webserver)
+	cs.setupHealthChecks(webserver)
+
+	if config.GRPC.Enabled {
+		cs.grpcServer = web.NewGRPCServer(
+			config.GRPC.ListenAddr,
+			config.GRPC.ListenPort,
+			config.GRPC.CertPath,
+			config.GRPC.CertKeyPath,
+		)
+	}
+
+	if config.OpenTelemetry.Enabled {
+		t, err := telemetry.Setup(context.Background(), config.OpenTelemetry)
+		if err != nil {
+			return nil, fmt.Errorf("error setting up OpenTelemetry: %w", err)
+		}
+
+		cs.telemetry = t
+	}
 
 	return cs, nil
 }
@@ -67,10 +106,10 @@ func (cs *Certstream) setupMetrics(webserver *web.Server) {
 		// If prometheus is enabled, and interface is either unconfigured or same as webserver config, use existing webserver
 		if (cs.config.Prometheus.ListenAddr == "" || cs.config.Prometheus.ListenAddr == cs.config.Webserver.ListenAddr) &&
 			(cs.config.Prometheus.ListenPort == 0 || cs.config.Prometheus.ListenPort == cs.config.Webserver.ListenPort) {
-			log.Println("Starting prometheus server on same interface as webserver")
+			logger.Info("Starting prometheus server on same interface as webserver")
 			webserver.RegisterPrometheus(cs.config.Prometheus.MetricsURL, metrics.Prometheus.Write)
 		} else {
-			log.Println("Starting prometheus server on new interface")
+			logger.Info("Starting prometheus server on new interface")
 
 			cs.metricsServer = web.NewMetricsServer(
 				cs.config.Prometheus.ListenAddr,
@@ -83,10 +122,32 @@ func (cs *Certstream) setupMetrics(webserver *web.Server) {
 	}
 }
 
-// Start starts the webserver and the watcher.
+// setupHealthChecks registers the liveness and readiness probes on the metrics server and optionally on the webserver.
+// If there's no separate metrics server, the probes are registered on the webserver instead.
+func (cs *Certstream) setupHealthChecks(webserver *web.Server) {
+	if cs.metricsServer != nil {
+		cs.metricsServer.RegisterHealthChecks()
+	}
+
+	if cs.healthChecksOnWebserver() {
+		if cs.metricsServer == nil && !cs.config.Health.WebsocketServer {
+			logger.Info("Serving health checks on the webserver since there is no separate metrics server")
+		}
+
+		webserver.RegisterHealthChecks()
+	}
+}
+
+// healthChecksOnWebserver reports whether the probes are served by the webserver, either because it's configured
+// or because there's no separate metrics server.
+func (cs *Certstream) healthChecksOnWebserver() bool {
+	return cs.metricsServer == nil || cs.config.Health.WebsocketServer
+}
+
+// Start starts the webserver, the gRPC server and the watcher.
 // This is a blocking function that will run until the server is stopped.
 func (cs *Certstream) Start() {
-	log.Printf("Starting certstream-server-go v%s\n", config.Version)
+	logger.Info("Starting certstream-server-go", "version", config.Version)
 
 	// handle signals in a separate goroutine
 	signals := make(chan os.Signal, 1)
@@ -101,7 +162,8 @@ func (cs *Certstream) Start() {
 
 	// Start webserver and metrics server
 	if cs.webserver == nil {
-		log.Fatalln("Webserver not initialized! Exiting...")
+		logger.Error("Webserver not initialized! Exiting...")
+		os.Exit(1)
 	}
 
 	go cs.webserver.Start()
@@ -110,23 +172,46 @@ func (cs *Certstream) Start() {
 		go cs.metricsServer.Start()
 	}
 
+	if cs.grpcServer != nil {
+		go cs.grpcServer.Start()
+	}
+
 	// Start the watcher - this is a blocking function
 	cs.watcher.Start()
 }
 
-// Stop stops the watcher and the webserver.
+// Stop stops the watcher, the gRPC server and the webserver and flushes the pending telemetry.
 func (cs *Certstream) Stop() {
 	if cs.watcher != nil {
 		cs.watcher.Stop()
 	}
 
+	if cs.grpcServer != nil {
+		cs.grpcServer.Stop()
+	}
+
 	if cs.webserver != nil {
 		cs.webserver.Stop()
 	}
 
+	if cs.config.Webserver.Replay.Enabled {
+		if err := web.ClientHandler.SaveReplayBuffer(cs.config.Webserver.Replay.File); err != nil {
+			logger.Error("Failed to save replay buffer", logging.KeyError, err)
+		}
+	}
+
 	if cs.metricsServer != nil {
 		cs.metricsServer.Stop()
 	}
+
+	if cs.telemetry != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := cs.telemetry.Shutdown(ctx); err != nil {
+			logger.Error("Could not flush telemetry", logging.KeyError, err)
+		}
+	}
 }
 
 // CreateIndexFile creates the index file for the certificate transparency logs.
@@ -148,10 +233,10 @@ func (cs *Certstream) CreateIndexFile(outFile string) error {
 // signalHandler listens for signals in order to gracefully shut down the server.
 // Executes the callback function when a signal is received.
 func signalHandler(signals chan os.Signal, callback func()) {
-	log.Println("Listening for signals...")
+	logger.Debug("Listening for signals...")
 
 	sig := <-signals
-	log.Printf("Received signal %v. Shutting down...\n", sig)
+	logger.Info("Received signal. Shutting down...", "signal", sig.String())
 	callback()
 	os.Exit(0)
 }
@@ -0,0 +1,40 @@
+package certstream
+
+import (
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/web"
+)
+
+func TestHealthChecksOnWebserver(t *testing.T) {
+	cases := []struct {
+		name            string
+		prometheus      bool
+		prometheusPort  int
+		websocketServer bool
+		want            bool
+	}{
+		{"prometheus disabled", false, 0, false, true},
+		{"prometheus on webserver", true, 0, false, true},
+		{"separate metrics server", true, 9090, false, false},
+		{"separate metrics server and websocket server", true, 9090, true, true},
+	}
+
+	for _, testcase := range cases {
+		var conf config.Config
+		conf.Webserver.ListenAddr = "127.0.0.1"
+		conf.Webserver.ListenPort = 8080
+		conf.Prometheus.Enabled = testcase.prometheus
+		conf.Prometheus.ListenPort = testcase.prometheusPort
+		conf.Prometheus.MetricsURL = "/metrics"
+		conf.Health.WebsocketServer = testcase.websocketServer
+
+		cs := NewRawCertstream(conf)
+		cs.setupMetrics(web.NewMetricsServer(conf.Webserver.ListenAddr, conf.Webserver.ListenPort, "", ""))
+
+		if got := cs.healthChecksOnWebserver(); got != testcase.want {
+			t.Errorf("%s: want probes on webserver %v, got %v", testcase.name, testcase.want, got)
+		}
+	}
+}
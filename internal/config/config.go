@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -16,6 +18,9 @@ var (
 	Version   = "1.9.0"
 
 	ErrInvalidConfig = errors.New("invalid configuration")
+
+	// SlowConsumerPolicies contains all valid policies for clients that can't keep up. The first one is the default.
+	SlowConsumerPolicies = []string{"drop_newest", "drop_oldest", "disconnect", "gap_notice"}
 )
 
 type ServerConfig struct {
@@ -40,14 +45,220 @@ type BufferSizes struct {
 	BroadcastManager int `mapstructure:"broadcastmanager"`
 }
 
+type ReplayConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Size is the maximum number of entries kept in the replay buffer.
+	Size int `mapstructure:"size"`
+	// File is an optional path where the replay buffer is stored on shutdown and loaded from on startup.
+	File string `mapstructure:"file"`
+}
+
+// APIKey describes a single consumer that is allowed to access the websocket server.
+type APIKey struct {
+	Key string `mapstructure:"key"`
+	// Name replaces the remote address of the client in logs and metrics.
+	Name string `mapstructure:"name"`
+	// Endpoints is a list of the stream types the key may access ("full", "lite", "domains", "new_domains"). Empty means all.
+	Endpoints []string `mapstructure:"endpoints"`
+	// MaxConnections is the maximum number of concurrent connections for this key. 0 means unlimited.
+	MaxConnections int `mapstructure:"max_connections"`
+}
+
+type AuthConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Keys    []APIKey `mapstructure:"keys"`
+	// KeysFile is an optional path to a separate YAML file containing additional keys.
+	KeysFile string `mapstructure:"keys_file"`
+}
+
+type JWTConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// JWKSFile is the path to a local JWKS file. Either JWKSFile or JWKSURL must be set.
+	JWKSFile string `mapstructure:"jwks_file"`
+	// JWKSURL is the URL of a remote JWKS, e.g. of an OIDC provider.
+	JWKSURL string `mapstructure:"jwks_url"`
+	// JWKSRefreshInterval is the number of seconds after which the keys are fetched again from JWKSURL.
+	JWKSRefreshInterval int    `mapstructure:"jwks_refresh_interval"`
+	Issuer              string `mapstructure:"issuer"`
+	Audience            string `mapstructure:"audience"`
+	// StreamsClaim is the name of the claim containing the list of allowed stream types ("full", "lite", "domains", "new_domains").
+	StreamsClaim string `mapstructure:"streams_claim"`
+	// DomainsClaim is the name of the claim containing a list of domains the client is restricted to.
+	DomainsClaim string `mapstructure:"domains_claim"`
+}
+
+type LimitsConfig struct {
+	// MaxClients is the maximum number of concurrently connected clients. 0 means unlimited.
+	MaxClients int `mapstructure:"max_clients"`
+	// MaxClientsPerIP is the maximum number of concurrent connections per IP address. 0 means unlimited.
+	MaxClientsPerIP int `mapstructure:"max_clients_per_ip"`
+	// MaxClientsPerKey is the default connection limit for API keys without their own max_connections. 0 means unlimited.
+	MaxClientsPerKey int `mapstructure:"max_clients_per_key"`
+	// ConnectionRate is the number of new connections per second allowed per IP address. 0 disables rate limiting.
+	ConnectionRate float64 `mapstructure:"connection_rate"`
+	// ConnectionBurst is the number of connections an IP address can open at once before being rate limited.
+	ConnectionBurst int `mapstructure:"connection_burst"`
+}
+
+type SlowConsumerConfig struct {
+	// Policy is the default policy for clients that can't keep up: drop_newest, drop_oldest, disconnect or gap_notice.
+	Policy string `mapstructure:"policy"`
+	// EndpointPolicies overrides the default policy per endpoint ("full", "lite", "domains", "new_domains").
+	EndpointPolicies map[string]string `mapstructure:"endpoint_policies"`
+	// AllowClientOverride allows clients to choose their policy via the "slow_consumer_policy" query parameter.
+	AllowClientOverride bool `mapstructure:"allow_client_override"`
+	// DisconnectAfterDrops is the number of consecutive drops after which a client is disconnected. 0 disables the check.
+	DisconnectAfterDrops int `mapstructure:"disconnect_after_drops"`
+	// DisconnectLossPercent is the share of dropped entries in percent after which a client is disconnected. 0 disables the check.
+	DisconnectLossPercent float64 `mapstructure:"disconnect_loss_percent"`
+}
+
+type BatchingConfig struct {
+	// Enabled allows clients to request batching via the "batch_size" and "batch_interval" query parameters.
+	Enabled bool `mapstructure:"enabled"`
+	// MaxSize is the maximum number of entries per batch a client can request.
+	MaxSize int `mapstructure:"max_size"`
+	// MaxInterval is the maximum time in milliseconds a client can request entries to be held back.
+	MaxInterval int `mapstructure:"max_interval"`
+}
+
+type HeartbeatConfig struct {
+	// Enabled sends heartbeat messages to all clients in addition to websocket pings.
+	Enabled bool `mapstructure:"enabled"`
+	// Interval is the number of seconds between two heartbeat messages.
+	Interval int `mapstructure:"interval"`
+}
+
+type StatsConfig struct {
+	// Enabled adds the /stats and /latest.json endpoints known from the original certstream server.
+	Enabled bool `mapstructure:"enabled"`
+	// LatestSize is the number of entries returned by /latest.json.
+	LatestSize int `mapstructure:"latest_size"`
+}
+
+type HealthConfig struct {
+	// WebsocketServer additionally serves the probes on the websocket server. Without a separate metrics server, they're always served there.
+	WebsocketServer bool `mapstructure:"websocket_server"`
+	// MinWorkers is the number of CT log workers that must be streaming for the server to be ready.
+	MinWorkers int `mapstructure:"min_workers"`
+	// MaxEntryAge is the number of seconds without a new entry after which the server is not ready anymore.
+	MaxEntryAge int `mapstructure:"max_entry_age"`
+	// BroadcastFillThreshold is the fill level (0-1) of the broadcast channel above which it's considered congested.
+	BroadcastFillThreshold float64 `mapstructure:"broadcast_fill_threshold"`
+	// BroadcastFillDuration is the number of seconds the broadcast channel may be congested before the server is not ready anymore.
+	BroadcastFillDuration int `mapstructure:"broadcast_fill_duration"`
+}
+
+type OpenTelemetryConfig struct {
+	// Enabled exports traces and metrics to an OTLP collector via HTTP.
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the host and port of the collector, e.g. "localhost:4318".
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure uses plain HTTP instead of HTTPS to connect to the collector.
+	Insecure bool `mapstructure:"insecure"`
+	// Headers are sent with each export request, e.g. for authentication.
+	Headers map[string]string `mapstructure:"headers"`
+	// ServiceName is reported as service.name resource attribute.
+	ServiceName string `mapstructure:"service_name"`
+	// TraceSampleRatio is the fraction of traces that are sampled (0-1). Every parsed entry creates a trace.
+	TraceSampleRatio float64 `mapstructure:"trace_sample_ratio"`
+	// MetricsInterval is the number of seconds between two metric exports. 0 disables the metrics export.
+	MetricsInterval int `mapstructure:"metrics_interval"`
+}
+
+type LoggingConfig struct {
+	// Format is either "text" or "json".
+	Format string `mapstructure:"format"`
+	// Output is "stdout", "stderr" or the path of a file the logs are appended to.
+	Output string `mapstructure:"output"`
+	// Level is the default level for all subsystems: "debug", "info", "warn" or "error".
+	Level string `mapstructure:"level"`
+	// Levels overwrites the level for single subsystems: server, watcher, tiled, web and metrics.
+	Levels map[string]string `mapstructure:"levels"`
+}
+
+type LintConfig struct {
+	// Enabled runs the certificate linter on each leaf certificate and adds the findings to the entries.
+	Enabled bool `mapstructure:"enabled"`
+	// Sets are the lint sets to run: "rfc5280", "cabf_br" and "community".
+	Sets []string `mapstructure:"sets"`
+	// TopIssuers is the number of issuers exported as Prometheus series. All other issuers are summed up as "other".
+	TopIssuers int `mapstructure:"top_issuers"`
+	// MaxIssuers is the maximum number of issuers tracked. Further issuers are counted as "other".
+	MaxIssuers int `mapstructure:"max_issuers"`
+}
+
+type ScoringConfig struct {
+	// Enabled rates the domains of each certificate and adds the score to the entries.
+	Enabled bool `mapstructure:"enabled"`
+	// Keywords maps suspicious keywords to the points of a domain containing them.
+	Keywords map[string]int `mapstructure:"keywords"`
+	// Brands are the brand names that are checked for look-alike domains.
+	Brands []string `mapstructure:"brands"`
+	// SuspiciousTLDs are top-level domains that are frequently abused for phishing.
+	SuspiciousTLDs []string `mapstructure:"suspicious_tlds"`
+}
+
+type NewDomainsConfig struct {
+	// Enabled tracks the domains seen in CT and offers the stream of new domains.
+	Enabled bool `mapstructure:"enabled"`
+	// Mode is "registrable_domain" to track the eTLD+1 of the domains or "fqdn" to track the full domains.
+	Mode string `mapstructure:"mode"`
+	// Window is the number of hours after which a domain that wasn't seen again is reported as new again.
+	Window int `mapstructure:"window"`
+	// Capacity is the expected number of distinct domains within the window. It determines the memory usage.
+	Capacity          int     `mapstructure:"capacity"`
+	FalsePositiveRate float64 `mapstructure:"false_positive_rate"`
+	// File is the path the filter is persisted to. Defaults to "new_domains.bloom" next to the CT index file.
+	File string `mapstructure:"file"`
+}
+
+type SearchConfig struct {
+	// Enabled stores the certificates in a local index and offers the search API.
+	Enabled bool `mapstructure:"enabled"`
+	// File is the path of the index database. Defaults to "search.db" next to the CT index file.
+	File string `mapstructure:"file"`
+	// Retention is the number of hours after which certificates are removed from the index.
+	Retention int `mapstructure:"retention"`
+	// MaxEntries is the maximum number of certificates in the index. The oldest are removed first. 0 means unlimited.
+	MaxEntries int `mapstructure:"max_entries"`
+	// PageSize is the number of results per page if the client doesn't request a limit.
+	PageSize    int `mapstructure:"page_size"`
+	MaxPageSize int `mapstructure:"max_page_size"`
+}
+
+type IssuerStatsConfig struct {
+	// Enabled counts the certificates per issuer, log operator and signature algorithm.
+	Enabled bool `mapstructure:"enabled"`
+	// IssuerKey identifies the issuers by "name" (the aggregated issuer name) or "spki" (the SHA256 hash of the
+	// public key of the issuer certificate).
+	IssuerKey string `mapstructure:"issuer_key"`
+	// TopN is the number of issuers exported as Prometheus series. All other issuers are summed up as "other".
+	TopN int `mapstructure:"top_n"`
+	// MaxIssuers is the maximum number of issuers tracked. Further issuers are counted as "other".
+	MaxIssuers int `mapstructure:"max_issuers"`
+}
+
 type Config struct {
 	Webserver struct {
 		ServerConfig `mapstructure:",squash"`
 
-		FullURL            string `mapstructure:"full_url"`
-		LiteURL            string `mapstructure:"lite_url"`
-		DomainsOnlyURL     string `mapstructure:"domains_only_url"`
-		CompressionEnabled bool   `mapstructure:"compression_enabled"`
+		FullURL            string             `mapstructure:"full_url"`
+		LiteURL            string             `mapstructure:"lite_url"`
+		DomainsOnlyURL     string             `mapstructure:"domains_only_url"`
+		NewDomainsURL      string             `mapstructure:"new_domains_url"`
+		SearchURL          string             `mapstructure:"search_url"`
+		CompressionEnabled bool               `mapstructure:"compression_enabled"`
+		Replay             ReplayConfig       `mapstructure:"replay"`
+		Auth               AuthConfig         `mapstructure:"auth"`
+		JWT                JWTConfig          `mapstructure:"jwt"`
+		Limits             LimitsConfig       `mapstructure:"limits"`
+		SlowConsumer       SlowConsumerConfig `mapstructure:"slow_consumer"`
+		Batching           BatchingConfig     `mapstructure:"batching"`
+		Heartbeat          HeartbeatConfig    `mapstructure:"heartbeat"`
+		Stats              StatsConfig        `mapstructure:"stats"`
+		// BroadcastShards is the number of goroutines the clients are distributed across. 0 means one per CPU.
+		BroadcastShards int `mapstructure:"broadcast_shards"`
 	}
 	Prometheus struct {
 		ServerConfig `mapstructure:",squash"`
@@ -56,7 +267,20 @@ type Config struct {
 		MetricsURL          string `mapstructure:"metrics_url"`
 		ExposeSystemMetrics bool   `mapstructure:"expose_system_metrics"`
 	}
-	General struct {
+	GRPC struct {
+		ServerConfig `mapstructure:",squash"`
+
+		Enabled bool `mapstructure:"enabled"`
+	}
+	Health        HealthConfig        `mapstructure:"health"`
+	OpenTelemetry OpenTelemetryConfig `mapstructure:"opentelemetry"`
+	Logging       LoggingConfig       `mapstructure:"logging"`
+	Lint          LintConfig          `mapstructure:"lint"`
+	Scoring       ScoringConfig       `mapstructure:"scoring"`
+	NewDomains    NewDomainsConfig    `mapstructure:"new_domains"`
+	Search        SearchConfig        `mapstructure:"search"`
+	IssuerStats   IssuerStatsConfig   `mapstructure:"issuer_stats"`
+	General       struct {
 		// DisableDefaultLogs indicates whether the default logs used in Google Chrome and provided by Google should be disabled.
 		DisableDefaultLogs bool `mapstructure:"disable_default_logs"`
 		// AdditionalLogs contains additional logs provided by the user that can be used in addition to the default logs.
@@ -68,6 +292,18 @@ type Config struct {
 			Enabled     bool   `mapstructure:"enabled"`
 			CTIndexFile string `mapstructure:"ct_index_file"`
 		} `mapstructure:"recovery"`
+		// DeadLetter configures a file to which a sample of the entries that could not be parsed is written.
+		DeadLetter struct {
+			Enabled bool   `mapstructure:"enabled"`
+			File    string `mapstructure:"file"`
+			// SampleRate is the fraction of failed entries that are written to the file, between 0 and 1.
+			SampleRate float64 `mapstructure:"sample_rate"`
+		} `mapstructure:"dead_letter"`
+		// SCT configures the handling of the SCTs embedded in certificates.
+		SCT struct {
+			// VerifySignatures enables the verification of the SCT signatures against the keys of the logs in the log list.
+			VerifySignatures bool `mapstructure:"verify_signatures"`
+		} `mapstructure:"sct"`
 	}
 }
 
@@ -97,10 +333,38 @@ func initViper(configPath string) *viper.Viper {
 	v.SetDefault("webserver.full_url", "/full-stream")
 	v.SetDefault("webserver.lite_url", "/")
 	v.SetDefault("webserver.domains_only_url", "/domains-only")
+	v.SetDefault("webserver.new_domains_url", "/new-domains")
+	v.SetDefault("webserver.search_url", "/search")
 	v.SetDefault("webserver.real_ip", false)
 	v.SetDefault("webserver.trusted_proxies", []string{})
 	v.SetDefault("webserver.whitelist", []string{})
 	v.SetDefault("webserver.compression_enabled", false)
+	v.SetDefault("webserver.replay.enabled", false)
+	v.SetDefault("webserver.replay.size", 10000)
+	v.SetDefault("webserver.replay.file", "")
+	v.SetDefault("webserver.auth.enabled", false)
+	v.SetDefault("webserver.auth.keys_file", "")
+	v.SetDefault("webserver.jwt.enabled", false)
+	v.SetDefault("webserver.jwt.jwks_refresh_interval", 3600)
+	v.SetDefault("webserver.jwt.streams_claim", "streams")
+	v.SetDefault("webserver.jwt.domains_claim", "domains")
+	v.SetDefault("webserver.limits.max_clients", 0)
+	v.SetDefault("webserver.limits.max_clients_per_ip", 0)
+	v.SetDefault("webserver.limits.max_clients_per_key", 0)
+	v.SetDefault("webserver.limits.connection_rate", 0)
+	v.SetDefault("webserver.limits.connection_burst", 10)
+	v.SetDefault("webserver.slow_consumer.policy", "drop_newest")
+	v.SetDefault("webserver.slow_consumer.allow_client_override", false)
+	v.SetDefault("webserver.slow_consumer.disconnect_after_drops", 1000)
+	v.SetDefault("webserver.slow_consumer.disconnect_loss_percent", 0)
+	v.SetDefault("webserver.broadcast_shards", 0)
+	v.SetDefault("webserver.batching.enabled", false)
+	v.SetDefault("webserver.batching.max_size", 500)
+	v.SetDefault("webserver.batching.max_interval", 1000)
+	v.SetDefault("webserver.heartbeat.enabled", false)
+	v.SetDefault("webserver.heartbeat.interval", 30)
+	v.SetDefault("webserver.stats.enabled", false)
+	v.SetDefault("webserver.stats.latest_size", 25)
 
 	v.SetDefault("prometheus.enabled", false)
 	v.SetDefault("prometheus.listen_addr", "0.0.0.0")
@@ -111,6 +375,52 @@ func initViper(configPath string) *viper.Viper {
 	v.SetDefault("prometheus.trusted_proxies", []string{})
 	v.SetDefault("prometheus.whitelist", []string{})
 
+	v.SetDefault("grpc.enabled", false)
+	v.SetDefault("grpc.listen_addr", "0.0.0.0")
+	v.SetDefault("grpc.listen_port", 8081)
+
+	v.SetDefault("health.websocket_server", false)
+	v.SetDefault("health.min_workers", 1)
+	v.SetDefault("health.max_entry_age", 120)
+	v.SetDefault("health.broadcast_fill_threshold", 0.9)
+	v.SetDefault("health.broadcast_fill_duration", 60)
+
+	v.SetDefault("logging.format", "text")
+	v.SetDefault("logging.output", "stderr")
+	v.SetDefault("logging.level", "info")
+
+	v.SetDefault("lint.enabled", false)
+	v.SetDefault("lint.sets", []string{"rfc5280", "cabf_br"})
+	v.SetDefault("new_domains.enabled", false)
+	v.SetDefault("new_domains.mode", "registrable_domain")
+	v.SetDefault("new_domains.window", 720)
+	v.SetDefault("new_domains.capacity", 5_000_000)
+	v.SetDefault("new_domains.false_positive_rate", 0.001)
+	v.SetDefault("search.enabled", false)
+	v.SetDefault("search.retention", 168)
+	v.SetDefault("search.max_entries", 0)
+	v.SetDefault("search.page_size", 100)
+	v.SetDefault("search.max_page_size", 1000)
+	v.SetDefault("issuer_stats.enabled", false)
+	v.SetDefault("issuer_stats.issuer_key", "name")
+	v.SetDefault("issuer_stats.top_n", 20)
+	v.SetDefault("issuer_stats.max_issuers", 10_000)
+	v.SetDefault("scoring.enabled", false)
+	v.SetDefault("scoring.keywords", map[string]int{
+		"login": 25, "signin": 25, "verify": 20, "verification": 20, "account": 20, "password": 30, "wallet": 25,
+		"secure": 15, "security": 15, "update": 15, "support": 10, "banking": 20, "invoice": 15, "recovery": 20,
+	})
+	v.SetDefault("scoring.brands", []string{
+		"paypal", "apple", "microsoft", "google", "amazon", "netflix", "facebook", "instagram", "coinbase", "binance",
+	})
+	v.SetDefault("scoring.suspicious_tlds", []string{"zip", "mov", "xyz", "top", "tk", "ml", "ga", "cf", "gq", "buzz", "click", "country", "work"})
+	v.SetDefault("opentelemetry.enabled", false)
+	v.SetDefault("opentelemetry.endpoint", "localhost:4318")
+	v.SetDefault("opentelemetry.insecure", false)
+	v.SetDefault("opentelemetry.service_name", "certstream-server-go")
+	v.SetDefault("opentelemetry.trace_sample_ratio", 0.001)
+	v.SetDefault("opentelemetry.metrics_interval", 60)
+
 	v.SetDefault("general.disable_default_logs", false)
 	v.SetDefault("general.buffer_sizes.websocket", 300)
 	v.SetDefault("general.buffer_sizes.ctlog", 1000)
@@ -118,6 +428,10 @@ func initViper(configPath string) *viper.Viper {
 	v.SetDefault("general.drop_old_logs", true)
 	v.SetDefault("general.recovery.enabled", false)
 	v.SetDefault("general.recovery.ct_index_file", "./ct_index.json")
+	v.SetDefault("general.dead_letter.enabled", false)
+	v.SetDefault("general.dead_letter.file", "./dead_letter.jsonl")
+	v.SetDefault("general.dead_letter.sample_rate", 1.0)
+	v.SetDefault("general.sct.verify_signatures", false)
 
 	if configPath != "" {
 		v.SetConfigFile(configPath)
@@ -160,6 +474,15 @@ func loadConfigFromViper(v *viper.Viper) (Config, error) {
 		return cfg, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if cfg.Webserver.Auth.KeysFile != "" {
+		keys, err := readAPIKeysFile(cfg.Webserver.Auth.KeysFile)
+		if err != nil {
+			return cfg, err
+		}
+
+		cfg.Webserver.Auth.Keys = append(cfg.Webserver.Auth.Keys, keys...)
+	}
+
 	if !validateConfig(&cfg) {
 		return cfg, ErrInvalidConfig
 	}
@@ -169,6 +492,91 @@ func loadConfigFromViper(v *viper.Viper) (Config, error) {
 	return cfg, nil
 }
 
+// readAPIKeysFile reads the list of API keys from a separate YAML file.
+// The file is expected to contain a top level "keys" list with the same format as in the main config.
+func readAPIKeysFile(keysFilePath string) ([]APIKey, error) {
+	v := viper.New()
+	v.SetConfigFile(keysFilePath)
+	v.SetConfigType("yaml")
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read API keys file: %w", err)
+	}
+
+	var keysFile struct {
+		Keys []APIKey `mapstructure:"keys"`
+	}
+
+	if err := v.Unmarshal(&keysFile); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal API keys file: %w", err)
+	}
+
+	log.Printf("Loaded %d API keys from '%s'\n", len(keysFile.Keys), keysFilePath)
+
+	return keysFile.Keys, nil
+}
+
+// validateAPIKeys removes invalid or duplicate keys and sets a default name for unnamed keys.
+func validateAPIKeys(keys []APIKey) []APIKey {
+	validEndpoints := []string{"full", "lite", "domains", "new_domains"}
+	seen := make(map[string]struct{}, len(keys))
+	validKeys := make([]APIKey, 0, len(keys))
+
+	for i, apiKey := range keys {
+		if apiKey.Key == "" {
+			log.Printf("Ignoring API key #%d without a key\n", i)
+			continue
+		}
+
+		if _, ok := seen[apiKey.Key]; ok {
+			log.Printf("Ignoring duplicate API key '%s'\n", apiKey.Name)
+			continue
+		}
+
+		if apiKey.Name == "" {
+			apiKey.Name = fmt.Sprintf("apikey-%d", i)
+		}
+
+		endpoints := make([]string, 0, len(apiKey.Endpoints))
+		for _, endpoint := range apiKey.Endpoints {
+			if !slices.Contains(validEndpoints, endpoint) {
+				log.Printf("Ignoring invalid endpoint '%s' for API key '%s'\n", endpoint, apiKey.Name)
+				continue
+			}
+
+			endpoints = append(endpoints, endpoint)
+		}
+
+		apiKey.Endpoints = endpoints
+		seen[apiKey.Key] = struct{}{}
+		validKeys = append(validKeys, apiKey)
+	}
+
+	return validKeys
+}
+
+// validateSlowConsumerConfig resets invalid slow consumer policies to the default policy.
+func validateSlowConsumerConfig(slowConsumer *SlowConsumerConfig) {
+	if !slices.Contains(SlowConsumerPolicies, slowConsumer.Policy) {
+		log.Printf("Invalid slow consumer policy '%s'. Defaulting to '%s'\n", slowConsumer.Policy, SlowConsumerPolicies[0])
+
+		slowConsumer.Policy = SlowConsumerPolicies[0]
+	}
+
+	for endpoint, policy := range slowConsumer.EndpointPolicies {
+		if !slices.Contains(SlowConsumerPolicies, policy) {
+			log.Printf("Ignoring invalid slow consumer policy '%s' for endpoint '%s'\n", policy, endpoint)
+			delete(slowConsumer.EndpointPolicies, endpoint)
+		}
+	}
+
+	if slowConsumer.DisconnectAfterDrops < 0 {
+		log.Printf("Invalid number of drops '%d' for disconnecting slow consumers. Disabling the check\n", slowConsumer.DisconnectAfterDrops)
+
+		slowConsumer.DisconnectAfterDrops = 0
+	}
+}
+
 // validateConfig validates the config values and sets defaults for missing values.
 func validateConfig(config *Config) bool {
 	// Still matches invalid IP addresses but good enough for detecting completely wrong formats
@@ -204,6 +612,18 @@ func validateConfig(config *Config) bool {
 		config.Webserver.FullURL = "/domains-only"
 	}
 
+	if config.Webserver.NewDomainsURL == "" || !URLPathRegex.MatchString(config.Webserver.NewDomainsURL) {
+		log.Println("Webhook new domains URL is not set or does not match pattern '/...'")
+
+		config.Webserver.NewDomainsURL = "/new-domains"
+	}
+
+	if config.Webserver.SearchURL == "" || !URLPathRegex.MatchString(config.Webserver.SearchURL) {
+		log.Println("Search URL is not set or does not match pattern '/...'")
+
+		config.Webserver.SearchURL = "/search"
+	}
+
 	if config.Webserver.FullURL == config.Webserver.LiteURL {
 		log.Fatalln("Webhook full URL is the same as lite URL - please fix the config!")
 	}
@@ -212,6 +632,68 @@ func validateConfig(config *Config) bool {
 		config.Webserver.FullURL = "/domains-only"
 	}
 
+	if config.Webserver.Replay.Enabled && config.Webserver.Replay.Size <= 0 {
+		log.Println("Replay buffer size is not set or invalid. Defaulting to 10000")
+
+		config.Webserver.Replay.Size = 10000
+	}
+
+	if config.Webserver.BroadcastShards < 0 {
+		log.Println("Number of broadcast shards is invalid. Defaulting to one shard per CPU")
+
+		config.Webserver.BroadcastShards = 0
+	}
+
+	config.Webserver.Auth.Keys = validateAPIKeys(config.Webserver.Auth.Keys)
+	if config.Webserver.Auth.Enabled && len(config.Webserver.Auth.Keys) == 0 {
+		log.Println("Authentication is enabled, but no valid API keys are configured. All clients will be rejected!")
+	}
+
+	if config.Webserver.JWT.Enabled {
+		if config.Webserver.JWT.JWKSFile == "" && config.Webserver.JWT.JWKSURL == "" {
+			log.Fatalln("JWT authentication is enabled, but neither jwks_file nor jwks_url is set")
+			return false
+		}
+
+		if config.Webserver.JWT.JWKSRefreshInterval <= 0 {
+			config.Webserver.JWT.JWKSRefreshInterval = 3600
+		}
+	}
+
+	if config.Webserver.Limits.ConnectionRate > 0 && config.Webserver.Limits.ConnectionBurst <= 0 {
+		log.Println("Connection burst is not set or invalid. Defaulting to 10")
+
+		config.Webserver.Limits.ConnectionBurst = 10
+	}
+
+	validateSlowConsumerConfig(&config.Webserver.SlowConsumer)
+
+	if config.Webserver.Batching.Enabled {
+		if config.Webserver.Batching.MaxSize <= 0 {
+			log.Println("Batching max_size is not set or invalid. Defaulting to 500")
+
+			config.Webserver.Batching.MaxSize = 500
+		}
+
+		if config.Webserver.Batching.MaxInterval <= 0 {
+			log.Println("Batching max_interval is not set or invalid. Defaulting to 1000")
+
+			config.Webserver.Batching.MaxInterval = 1000
+		}
+	}
+
+	if config.Webserver.Heartbeat.Enabled && config.Webserver.Heartbeat.Interval <= 0 {
+		log.Println("Heartbeat interval is not set or invalid. Defaulting to 30")
+
+		config.Webserver.Heartbeat.Interval = 30
+	}
+
+	if config.Webserver.Stats.Enabled && config.Webserver.Stats.LatestSize <= 0 {
+		log.Println("Stats latest_size is not set or invalid. Defaulting to 25")
+
+		config.Webserver.Stats.LatestSize = 25
+	}
+
 	for _, ip := range config.Webserver.TrustedProxies {
 		if net.ParseIP(ip) != nil {
 			continue
@@ -267,6 +749,41 @@ func validateConfig(config *Config) bool {
 		}
 	}
 
+	if config.GRPC.Enabled {
+		if config.GRPC.ListenAddr == "" || net.ParseIP(config.GRPC.ListenAddr) == nil {
+			log.Fatalln("gRPC listen IP is not a valid IP")
+			return false
+		}
+
+		if config.GRPC.ListenPort == 0 {
+			log.Fatalln("gRPC listen port is not set")
+			return false
+		}
+
+		if config.GRPC.ListenPort == config.Webserver.ListenPort && config.GRPC.ListenAddr == config.Webserver.ListenAddr {
+			log.Fatalln("gRPC listen port is the same as the webserver port - please fix the config!")
+			return false
+		}
+	}
+
+	if config.Health.BroadcastFillThreshold <= 0 || config.Health.BroadcastFillThreshold > 1 {
+		log.Println("Health broadcast_fill_threshold must be between 0 and 1. Defaulting to 0.9")
+
+		config.Health.BroadcastFillThreshold = 0.9
+	}
+
+	if config.OpenTelemetry.TraceSampleRatio < 0 || config.OpenTelemetry.TraceSampleRatio > 1 {
+		log.Println("OpenTelemetry trace_sample_ratio must be between 0 and 1. Defaulting to 0.001")
+
+		config.OpenTelemetry.TraceSampleRatio = 0.001
+	}
+
+	if config.General.DeadLetter.SampleRate <= 0 || config.General.DeadLetter.SampleRate > 1 {
+		log.Println("Dead letter sample_rate must be between 0 and 1. Defaulting to 1")
+
+		config.General.DeadLetter.SampleRate = 1
+	}
+
 	var validLogs, validTiledLogs []LogConfig
 
 	if len(config.General.AdditionalLogs) > 0 {
@@ -324,5 +841,132 @@ func validateConfig(config *Config) bool {
 		config.General.Recovery.CTIndexFile = "./ct_index.json"
 	}
 
+	if config.Lint.Enabled {
+		validateLintConfig(config)
+	}
+
+	if config.NewDomains.Enabled {
+		validateNewDomainsConfig(config)
+	}
+
+	if config.Search.Enabled {
+		validateSearchConfig(config)
+	}
+
+	if config.IssuerStats.Enabled {
+		validateIssuerStatsConfig(config)
+	}
+
 	return true
 }
+
+// validateLintConfig replaces invalid values of the lint config with their defaults.
+func validateLintConfig(config *Config) {
+	lint := &config.Lint
+
+	if lint.TopIssuers <= 0 {
+		log.Println("Lint top_issuers is not set or invalid. Defaulting to 20")
+
+		lint.TopIssuers = 20
+	}
+
+	if lint.MaxIssuers < lint.TopIssuers {
+		log.Printf("Lint max_issuers is lower than top_issuers. Defaulting to %d\n", max(10_000, lint.TopIssuers))
+
+		lint.MaxIssuers = max(10_000, lint.TopIssuers)
+	}
+}
+
+// validateNewDomainsConfig replaces invalid values of the new domains config with their defaults.
+func validateNewDomainsConfig(config *Config) {
+	newDomains := &config.NewDomains
+
+	if newDomains.Mode != "registrable_domain" && newDomains.Mode != "fqdn" {
+		log.Printf("New domains mode '%s' is invalid. Defaulting to registrable_domain\n", newDomains.Mode)
+
+		newDomains.Mode = "registrable_domain"
+	}
+
+	if newDomains.Window <= 0 {
+		log.Println("New domains window is not set or invalid. Defaulting to 720 hours")
+
+		newDomains.Window = 720
+	}
+
+	if newDomains.Capacity <= 0 {
+		log.Println("New domains capacity is not set or invalid. Defaulting to 5000000")
+
+		newDomains.Capacity = 5_000_000
+	}
+
+	if newDomains.FalsePositiveRate <= 0 || newDomains.FalsePositiveRate >= 1 {
+		log.Println("New domains false positive rate is not set or invalid. Defaulting to 0.001")
+
+		newDomains.FalsePositiveRate = 0.001
+	}
+
+	if newDomains.File == "" {
+		ctIndexFile := config.General.Recovery.CTIndexFile
+		if ctIndexFile == "" {
+			ctIndexFile = "./ct_index.json"
+		}
+
+		newDomains.File = filepath.Join(filepath.Dir(ctIndexFile), "new_domains.bloom")
+	}
+}
+
+// validateSearchConfig replaces invalid values of the search config with their defaults.
+func validateSearchConfig(config *Config) {
+	search := &config.Search
+
+	if search.Retention <= 0 {
+		log.Println("Search retention is not set or invalid. Defaulting to 168 hours")
+
+		search.Retention = 168
+	}
+
+	if search.MaxEntries < 0 {
+		log.Println("Search max_entries is invalid. Defaulting to 0 (unlimited)")
+
+		search.MaxEntries = 0
+	}
+
+	if search.MaxPageSize <= 0 {
+		log.Println("Search max_page_size is not set or invalid. Defaulting to 1000")
+
+		search.MaxPageSize = 1000
+	}
+
+	if search.PageSize <= 0 || search.PageSize > search.MaxPageSize {
+		log.Printf("Search page_size is not set or invalid. Defaulting to %d\n", min(100, search.MaxPageSize))
+
+		search.PageSize = min(100, search.MaxPageSize)
+	}
+
+	if search.File == "" {
+		search.File = filepath.Join(filepath.Dir(config.General.Recovery.CTIndexFile), "search.db")
+	}
+}
+
+// validateIssuerStatsConfig replaces invalid values of the issuer stats config with their defaults.
+func validateIssuerStatsConfig(config *Config) {
+	issuerStats := &config.IssuerStats
+
+	if issuerStats.IssuerKey != "name" && issuerStats.IssuerKey != "spki" {
+		log.Printf("Issuer stats issuer_key '%s' is invalid. Defaulting to name\n", issuerStats.IssuerKey)
+
+		issuerStats.IssuerKey = "name"
+	}
+
+	if issuerStats.TopN <= 0 {
+		log.Println("Issuer stats top_n is not set or invalid. Defaulting to 20")
+
+		issuerStats.TopN = 20
+	}
+
+	if issuerStats.MaxIssuers < issuerStats.TopN {
+		log.Printf("Issuer stats max_issuers is lower than top_n. Defaulting to %d\n", max(10_000, issuerStats.TopN))
+
+		issuerStats.MaxIssuers = max(10_000, issuerStats.TopN)
+	}
+}
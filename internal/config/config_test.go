@@ -527,3 +527,74 @@ webserver:
 	// and rely on the valid-case tests above to confirm the happy path.
 	_ = configPath // acknowledged: tested via valid-case tests
 }
+
+// TestReadConfigViper_APIKeys verifies that API keys from the config file and a separate
+// keys file are merged, and that invalid or duplicate keys are dropped.
+func TestReadConfigViper_APIKeys(t *testing.T) {
+	keysFilePath := filepath.Join(t.TempDir(), "keys.yaml")
+	keysYAML := `
+keys:
+  - key: "secret-b"
+    name: "partner-b"
+    endpoints: ["lite", "invalid"]
+  - key: "secret-a"
+    name: "duplicate"
+`
+	if err := os.WriteFile(keysFilePath, []byte(keysYAML), 0o600); err != nil {
+		t.Fatalf("failed to write keys file: %v", err)
+	}
+
+	yaml := `
+webserver:
+  listen_addr: "0.0.0.0"
+  listen_port: 8080
+  auth:
+    enabled: true
+    keys_file: "` + keysFilePath + `"
+    keys:
+      - key: "secret-a"
+        name: "partner-a"
+        max_connections: 3
+      - name: "no-key"
+`
+	cfg, err := ReadConfig(writeConfigFile(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := cfg.Webserver.Auth.Keys
+	if len(keys) != 2 {
+		t.Fatalf("Auth.Keys: want 2 entries, got %d", len(keys))
+	}
+	if keys[0].Name != "partner-a" || keys[0].MaxConnections != 3 {
+		t.Errorf("Auth.Keys[0]: want partner-a with 3 connections, got %q with %d", keys[0].Name, keys[0].MaxConnections)
+	}
+	if keys[1].Name != "partner-b" || len(keys[1].Endpoints) != 1 || keys[1].Endpoints[0] != "lite" {
+		t.Errorf("Auth.Keys[1]: want partner-b with endpoints [lite], got %q with %v", keys[1].Name, keys[1].Endpoints)
+	}
+}
+
+func TestReadConfigViper_DisconnectAfterDrops(t *testing.T) {
+	cfg, err := ReadConfig(writeConfigFile(t, minimalValidYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Webserver.SlowConsumer.DisconnectAfterDrops != 1000 {
+		t.Errorf("DisconnectAfterDrops: want default 1000, got %d", cfg.Webserver.SlowConsumer.DisconnectAfterDrops)
+	}
+
+	yaml := minimalValidYAML + `
+  slow_consumer:
+    disconnect_after_drops: 0
+`
+
+	cfg, err = ReadConfig(writeConfigFile(t, yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Webserver.SlowConsumer.DisconnectAfterDrops != 0 {
+		t.Errorf("DisconnectAfterDrops: want 0 to disable the check, got %d", cfg.Webserver.SlowConsumer.DisconnectAfterDrops)
+	}
+}
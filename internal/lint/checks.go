@@ -0,0 +1,335 @@
+package lint
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// checks contains all known checks. The rfc5280 checks run for all certificates, the others only for subscriber
+// certificates.
+var checks = []check{
+	{"e_rfc_serial_number_too_long", SetRFC5280, SeverityError, checkSerialNumberTooLong},
+	{"e_rfc_serial_number_not_positive", SetRFC5280, SeverityError, checkSerialNumberNotPositive},
+	{"e_rfc_validity_negative", SetRFC5280, SeverityError, checkValidityNegative},
+	{"e_rfc_subject_empty_without_san", SetRFC5280, SeverityError, checkSubjectEmptyWithoutSAN},
+	{"e_rfc_dns_name_malformed", SetRFC5280, SeverityError, checkDNSNameMalformed},
+	{"e_rfc_ca_key_usage_missing", SetRFC5280, SeverityError, checkCAKeyUsageMissing},
+	{"w_rfc_aki_missing", SetRFC5280, SeverityWarn, checkAKIMissing},
+	{"w_rfc_ca_ski_missing", SetRFC5280, SeverityWarn, checkCASKIMissing},
+
+	{"e_cab_validity_too_long", SetCABFBR, SeverityError, checkValidityTooLong},
+	{"e_cab_rsa_key_too_small", SetCABFBR, SeverityError, checkRSAKeyTooSmall},
+	{"e_cab_ecdsa_curve_not_allowed", SetCABFBR, SeverityError, checkECDSACurveNotAllowed},
+	{"e_cab_key_algorithm_not_allowed", SetCABFBR, SeverityError, checkKeyAlgorithmNotAllowed},
+	{"e_cab_weak_signature_algorithm", SetCABFBR, SeverityError, checkWeakSignatureAlgorithm},
+	{"e_cab_san_missing", SetCABFBR, SeverityError, checkSANMissing},
+	{"e_cab_cn_not_in_san", SetCABFBR, SeverityError, checkCNNotInSAN},
+	{"e_cab_dns_name_underscore", SetCABFBR, SeverityError, checkDNSNameUnderscore},
+	{"e_cab_dns_name_internal", SetCABFBR, SeverityError, checkDNSNameInternal},
+	{"e_cab_ip_address_reserved", SetCABFBR, SeverityError, checkIPAddressReserved},
+	{"e_cab_email_address_in_san", SetCABFBR, SeverityError, checkEmailAddressInSAN},
+	{"e_cab_policy_missing", SetCABFBR, SeverityError, checkPolicyMissing},
+	{"e_cab_revocation_info_missing", SetCABFBR, SeverityError, checkRevocationInfoMissing},
+	{"w_cab_ca_issuers_missing", SetCABFBR, SeverityWarn, checkCAIssuersMissing},
+
+	{"w_community_ocsp_without_crl", SetCommunity, SeverityWarn, checkOCSPWithoutCRL},
+	{"w_community_dns_name_upper_case", SetCommunity, SeverityWarn, checkDNSNameUpperCase},
+	{"n_community_many_dns_names", SetCommunity, SeverityNotice, checkManyDNSNames},
+}
+
+// maxDNSNames is the number of DNS names above which n_community_many_dns_names is reported.
+const maxDNSNames = 100
+
+// maxValidityDays contains the maximum validity of subscriber certificates according to ballot SC-081 of the
+// CA/Browser Forum, by the date from which certificates must comply.
+var maxValidityDays = []struct {
+	since time.Time
+	days  int64
+}{
+	{time.Date(2029, time.March, 15, 0, 0, 0, 0, time.UTC), 47},
+	{time.Date(2027, time.March, 15, 0, 0, 0, 0, time.UTC), 100},
+	{time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC), 200},
+	{time.Time{}, 398},
+}
+
+// dnsNames returns the DNS names of the subject alternative name extension.
+func dnsNames(lc *models.LeafCert) []string {
+	if lc.Extensions.SubjectAltName == nil {
+		return nil
+	}
+
+	var names []string
+
+	for _, name := range strings.Split(*lc.Extensions.SubjectAltName, ", ") {
+		if dnsName, ok := strings.CutPrefix(name, "DNS:"); ok {
+			names = append(names, dnsName)
+		}
+	}
+
+	return names
+}
+
+// firstMatch returns the first of the values the function returns true for, or an empty string.
+func firstMatch(values []string, matches func(string) bool) string {
+	for _, value := range values {
+		if matches(value) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func checkSerialNumberTooLong(lc *models.LeafCert) string {
+	// The serial number is positive, so a leading bit of 1 requires an additional zero octet in DER
+	octets := len(lc.SerialNumber) / 2
+	if lc.SerialNumber != "" && lc.SerialNumber[0] >= '8' {
+		octets++
+	}
+
+	if octets > 20 {
+		return fmt.Sprintf("serial number has %d octets", octets)
+	}
+
+	return ""
+}
+
+func checkSerialNumberNotPositive(lc *models.LeafCert) string {
+	// Serials with an odd number of digits are padded with a leading zero, so the sign isn't necessarily the first
+	// character, e.g. "0-10"
+	if strings.Contains(lc.SerialNumber, "-") || strings.Trim(lc.SerialNumber, "0") == "" {
+		return "serial number " + lc.SerialNumber
+	}
+
+	return ""
+}
+
+func checkValidityNegative(lc *models.LeafCert) string {
+	if lc.NotAfter < lc.NotBefore {
+		return "not_after is before not_before"
+	}
+
+	return ""
+}
+
+func checkSubjectEmptyWithoutSAN(lc *models.LeafCert) string {
+	s := lc.Subject
+	for _, field := range []*string{s.C, s.CN, s.L, s.O, s.OU, s.ST, s.EmailAddress} {
+		if field != nil && *field != "" {
+			return ""
+		}
+	}
+
+	if lc.Extensions.SubjectAltName == nil {
+		return "subject is empty and there is no subject alternative name"
+	}
+
+	return ""
+}
+
+func checkDNSNameMalformed(lc *models.LeafCert) string {
+	return firstMatch(dnsNames(lc), func(name string) bool { return !isValidDNSName(name) })
+}
+
+// isValidDNSName checks the preferred name syntax of RFC 1034, allowing underscores and a wildcard as leftmost label.
+func isValidDNSName(name string) bool {
+	name = strings.TrimPrefix(name, "*.")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for i := range len(label) {
+			c := label[i]
+			if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func checkCAKeyUsageMissing(lc *models.LeafCert) string {
+	if lc.IsCA && lc.Extensions.KeyUsage == nil {
+		return "CA certificate without key usage extension"
+	}
+
+	return ""
+}
+
+func checkAKIMissing(lc *models.LeafCert) string {
+	selfIssued := lc.Subject.Aggregated != nil && lc.Issuer.Aggregated != nil && *lc.Subject.Aggregated == *lc.Issuer.Aggregated
+	if !selfIssued && lc.Extensions.AuthorityKeyIdentifier == nil {
+		return "authority key identifier extension is missing"
+	}
+
+	return ""
+}
+
+func checkCASKIMissing(lc *models.LeafCert) string {
+	if lc.IsCA && lc.Extensions.SubjectKeyIdentifier == nil {
+		return "CA certificate without subject key identifier extension"
+	}
+
+	return ""
+}
+
+func checkValidityTooLong(lc *models.LeafCert) string {
+	notBefore := time.Unix(lc.NotBefore, 0)
+
+	for _, limit := range maxValidityDays {
+		if !notBefore.Before(limit.since) {
+			if lc.ValidityDays > limit.days {
+				return fmt.Sprintf("validity of %d days exceeds %d days", lc.ValidityDays, limit.days)
+			}
+
+			return ""
+		}
+	}
+
+	return ""
+}
+
+func checkRSAKeyTooSmall(lc *models.LeafCert) string {
+	if lc.PublicKey != nil && lc.PublicKey.Algorithm == "rsa" && (lc.PublicKey.Size < 2048 || lc.PublicKey.Size%8 != 0) {
+		return fmt.Sprintf("RSA key with %d bits", lc.PublicKey.Size)
+	}
+
+	return ""
+}
+
+func checkECDSACurveNotAllowed(lc *models.LeafCert) string {
+	if lc.PublicKey != nil && lc.PublicKey.Algorithm == "ecdsa" && !slices.Contains([]string{"P-256", "P-384", "P-521"}, lc.PublicKey.Curve) {
+		return "ECDSA key on curve " + lc.PublicKey.Curve
+	}
+
+	return ""
+}
+
+func checkKeyAlgorithmNotAllowed(lc *models.LeafCert) string {
+	if lc.PublicKey != nil && lc.PublicKey.Algorithm != "rsa" && lc.PublicKey.Algorithm != "ecdsa" {
+		return "public key algorithm " + lc.PublicKey.Algorithm
+	}
+
+	return ""
+}
+
+func checkWeakSignatureAlgorithm(lc *models.LeafCert) string {
+	for _, prefix := range []string{"md2", "md5", "sha1", "dsa, sha1", "ecdsa, sha1"} {
+		if strings.HasPrefix(lc.SignatureAlgorithm, prefix) {
+			return "signature algorithm " + lc.SignatureAlgorithm
+		}
+	}
+
+	return ""
+}
+
+func checkSANMissing(lc *models.LeafCert) string {
+	if lc.Extensions.SubjectAltName == nil {
+		return "subject alternative name extension is missing"
+	}
+
+	return ""
+}
+
+func checkCNNotInSAN(lc *models.LeafCert) string {
+	if lc.Subject.CN == nil || *lc.Subject.CN == "" || lc.Extensions.SubjectAltName == nil {
+		return ""
+	}
+
+	cn := *lc.Subject.CN
+	if slices.ContainsFunc(dnsNames(lc), func(name string) bool { return strings.EqualFold(name, cn) }) || slices.Contains(lc.IPAddresses, cn) {
+		return ""
+	}
+
+	return "common name " + cn + " is not a subject alternative name"
+}
+
+func checkDNSNameUnderscore(lc *models.LeafCert) string {
+	return firstMatch(dnsNames(lc), func(name string) bool { return strings.Contains(name, "_") })
+}
+
+// checkDNSNameInternal reports names without a dot and names below TLDs reserved for internal use.
+func checkDNSNameInternal(lc *models.LeafCert) string {
+	return firstMatch(dnsNames(lc), func(name string) bool {
+		name = strings.ToLower(strings.TrimSuffix(name, "."))
+		if !strings.Contains(name, ".") {
+			return true
+		}
+
+		tld := name[strings.LastIndexByte(name, '.')+1:]
+
+		return slices.Contains([]string{"local", "localhost", "internal", "lan", "home", "corp", "intranet", "test", "invalid", "example"}, tld)
+	})
+}
+
+func checkIPAddressReserved(lc *models.LeafCert) string {
+	return firstMatch(lc.IPAddresses, func(address string) bool {
+		ip := net.ParseIP(address)
+		return ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() || ip.IsMulticast()
+	})
+}
+
+func checkEmailAddressInSAN(lc *models.LeafCert) string {
+	if len(lc.EmailAddresses) > 0 {
+		return lc.EmailAddresses[0]
+	}
+
+	return ""
+}
+
+func checkPolicyMissing(lc *models.LeafCert) string {
+	if lc.ValidationLevel == "" {
+		return "no CA/Browser Forum reserved policy OID"
+	}
+
+	return ""
+}
+
+// checkRevocationInfoMissing reports certificates without CRL distribution point and OCSP URL. Short-lived
+// certificates with a validity of up to 10 days are exempt.
+func checkRevocationInfoMissing(lc *models.LeafCert) string {
+	if lc.ValidityDays > 10 && len(lc.CRLDistributionPoints) == 0 && len(lc.OCSPServers) == 0 {
+		return "neither CRL distribution point nor OCSP URL"
+	}
+
+	return ""
+}
+
+func checkCAIssuersMissing(lc *models.LeafCert) string {
+	if len(lc.IssuingCertificateURLs) == 0 {
+		return "no CA issuers URL in authority information access"
+	}
+
+	return ""
+}
+
+func checkOCSPWithoutCRL(lc *models.LeafCert) string {
+	if len(lc.OCSPServers) > 0 && len(lc.CRLDistributionPoints) == 0 {
+		return "OCSP URL without CRL distribution point"
+	}
+
+	return ""
+}
+
+func checkDNSNameUpperCase(lc *models.LeafCert) string {
+	return firstMatch(dnsNames(lc), func(name string) bool { return strings.ToLower(name) != name })
+}
+
+func checkManyDNSNames(lc *models.LeafCert) string {
+	if count := len(dnsNames(lc)); count > maxDNSNames {
+		return fmt.Sprintf("%d DNS names", count)
+	}
+
+	return ""
+}
@@ -0,0 +1,83 @@
+package lint
+
+// The lint package checks certificates for violations of RFC 5280, the CA/Browser Forum Baseline Requirements and
+// common best practices, similar to zlint. The checks work on the parsed models.LeafCert, so the DER data doesn't
+// need to be parsed a second time.
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+var ErrUnknownSet = errors.New("unknown lint set")
+
+// Names of the lint sets.
+const (
+	SetRFC5280   = "rfc5280"
+	SetCABFBR    = "cabf_br"
+	SetCommunity = "community"
+)
+
+// Severities of the checks. The name of each check starts with the first letter of its severity.
+const (
+	SeverityNotice = "notice"
+	SeverityWarn   = "warn"
+	SeverityError  = "error"
+)
+
+// check is a single lint. It returns the details of the violation, or an empty string if the certificate passed.
+type check struct {
+	name     string
+	set      string
+	severity string
+	run      func(lc *models.LeafCert) string
+}
+
+// Linter runs the checks of the configured lint sets.
+type Linter struct {
+	checks []check
+}
+
+// New creates a linter running all checks of the given lint sets.
+func New(sets []string) (*Linter, error) {
+	for _, set := range sets {
+		if set != SetRFC5280 && set != SetCABFBR && set != SetCommunity {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSet, set)
+		}
+	}
+
+	linter := &Linter{}
+
+	for _, c := range checks {
+		if slices.Contains(sets, c.set) {
+			linter.checks = append(linter.checks, c)
+		}
+	}
+
+	return linter, nil
+}
+
+// Lint runs the checks against the certificate. Checks for subscriber certificates are skipped for CA certificates.
+func (l *Linter) Lint(lc *models.LeafCert) *models.Lint {
+	result := &models.Lint{Findings: []models.LintFinding{}}
+
+	for _, c := range l.checks {
+		if lc.IsCA && c.set != SetRFC5280 {
+			continue
+		}
+
+		if details := c.run(lc); details != "" {
+			result.Findings = append(result.Findings, models.LintFinding{
+				Name:     c.name,
+				Set:      c.set,
+				Severity: c.severity,
+				Details:  details,
+			})
+		}
+	}
+
+	return result
+}
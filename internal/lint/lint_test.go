@@ -0,0 +1,139 @@
+package lint
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// compliantCert returns a subscriber certificate passing all checks.
+func compliantCert() models.LeafCert {
+	str := func(s string) *string { return &s }
+	notBefore := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	return models.LeafCert{
+		AllDomains: []string{"example.com", "www.example.com"},
+		Extensions: models.Extensions{
+			AuthorityKeyIdentifier: str("keyid:01:02"),
+			SubjectAltName:         str("DNS:example.com, DNS:www.example.com"),
+		},
+		NotBefore:              notBefore.Unix(),
+		NotAfter:               notBefore.Add(90*24*time.Hour - time.Second).Unix(),
+		SerialNumber:           "0498BDF812FAF923FEBD5EF7B374899FC61A",
+		SignatureAlgorithm:     "sha256, rsa",
+		Subject:                models.Subject{CN: str("example.com"), Aggregated: str("/CN=example.com")},
+		Issuer:                 models.Subject{CN: str("R3"), O: str("Let's Encrypt"), Aggregated: str("/C=US/CN=R3/O=Let's Encrypt")},
+		PublicKey:              &models.PublicKey{Algorithm: "ecdsa", Size: 256, Curve: "P-256"},
+		PolicyOIDs:             []string{"2.23.140.1.2.1"},
+		CRLDistributionPoints:  []string{"http://crl.example.com/1.crl"},
+		IssuingCertificateURLs: []string{"http://ca.example.com/ca.der"},
+		ValidationLevel:        "DV",
+		ValidityDays:           90,
+	}
+}
+
+func findingNames(result *models.Lint) []string {
+	names := make([]string, 0, len(result.Findings))
+	for _, finding := range result.Findings {
+		names = append(names, finding.Name)
+	}
+
+	return names
+}
+
+func TestNew_UnknownSet(t *testing.T) {
+	if _, err := New([]string{SetRFC5280, "etsi"}); !errors.Is(err, ErrUnknownSet) {
+		t.Errorf("want ErrUnknownSet, got %v", err)
+	}
+}
+
+func TestLint_CompliantCert(t *testing.T) {
+	linter, err := New([]string{SetRFC5280, SetCABFBR, SetCommunity})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafCert := compliantCert()
+
+	result := linter.Lint(&leafCert)
+	if result.Findings == nil || len(result.Findings) != 0 {
+		t.Errorf("want empty findings, got %v", result.Findings)
+	}
+}
+
+func TestLint_Findings(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(lc *models.LeafCert)
+		want   string
+	}{
+		{"validity over 200 days after March 2026", func(lc *models.LeafCert) { lc.ValidityDays = 201 }, "e_cab_validity_too_long"},
+		{"small RSA key", func(lc *models.LeafCert) { lc.PublicKey = &models.PublicKey{Algorithm: "rsa", Size: 1024} }, "e_cab_rsa_key_too_small"},
+		{"ed25519 key", func(lc *models.LeafCert) { lc.PublicKey = &models.PublicKey{Algorithm: "ed25519", Size: 256} }, "e_cab_key_algorithm_not_allowed"},
+		{"sha1 signature", func(lc *models.LeafCert) { lc.SignatureAlgorithm = "sha1, rsa" }, "e_cab_weak_signature_algorithm"},
+		{"missing SAN", func(lc *models.LeafCert) { lc.Extensions.SubjectAltName = nil }, "e_cab_san_missing"},
+		{"underscore", func(lc *models.LeafCert) { *lc.Extensions.SubjectAltName += ", DNS:a_b.example.com" }, "e_cab_dns_name_underscore"},
+		{"malformed name", func(lc *models.LeafCert) { *lc.Extensions.SubjectAltName += ", DNS:-a.example.com" }, "e_rfc_dns_name_malformed"},
+		{"internal name", func(lc *models.LeafCert) { *lc.Extensions.SubjectAltName += ", DNS:server.local" }, "e_cab_dns_name_internal"},
+		{"private IP", func(lc *models.LeafCert) { lc.IPAddresses = []string{"10.0.0.1"} }, "e_cab_ip_address_reserved"},
+		{"CN not in SAN", func(lc *models.LeafCert) { *lc.Subject.CN = "other.example.com" }, "e_cab_cn_not_in_san"},
+		{"no policy", func(lc *models.LeafCert) { lc.ValidationLevel = "" }, "e_cab_policy_missing"},
+		{"no revocation info", func(lc *models.LeafCert) { lc.CRLDistributionPoints = nil }, "e_cab_revocation_info_missing"},
+		{"serial too long", func(lc *models.LeafCert) { lc.SerialNumber = "80" + lc.SerialNumber + "00000000" }, "e_rfc_serial_number_too_long"},
+		{"negative serial", func(lc *models.LeafCert) { lc.SerialNumber = "-10" }, "e_rfc_serial_number_not_positive"},
+		{"padded negative serial", func(lc *models.LeafCert) { lc.SerialNumber = "0-10" }, "e_rfc_serial_number_not_positive"},
+		{"zero serial", func(lc *models.LeafCert) { lc.SerialNumber = "00" }, "e_rfc_serial_number_not_positive"},
+		{"no AKI", func(lc *models.LeafCert) { lc.Extensions.AuthorityKeyIdentifier = nil }, "w_rfc_aki_missing"},
+		{"upper case", func(lc *models.LeafCert) { *lc.Extensions.SubjectAltName += ", DNS:WWW.example.com" }, "w_community_dns_name_upper_case"},
+	}
+
+	linter, err := New([]string{SetRFC5280, SetCABFBR, SetCommunity})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leafCert := compliantCert()
+			tt.modify(&leafCert)
+
+			if names := findingNames(linter.Lint(&leafCert)); !slices.Contains(names, tt.want) {
+				t.Errorf("want finding %s, got %v", tt.want, names)
+			}
+		})
+	}
+}
+
+func TestLint_Sets(t *testing.T) {
+	linter, err := New([]string{SetRFC5280})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafCert := compliantCert()
+	leafCert.ValidityDays = 500
+	leafCert.Extensions.AuthorityKeyIdentifier = nil
+
+	if names := findingNames(linter.Lint(&leafCert)); !slices.Equal(names, []string{"w_rfc_aki_missing"}) {
+		t.Errorf("want only rfc5280 findings, got %v", names)
+	}
+}
+
+func TestLint_CACert(t *testing.T) {
+	linter, err := New([]string{SetRFC5280, SetCABFBR})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafCert := compliantCert()
+	leafCert.IsCA = true
+	leafCert.ValidityDays = 3650
+
+	names := findingNames(linter.Lint(&leafCert))
+	if !slices.Equal(names, []string{"e_rfc_ca_key_usage_missing", "w_rfc_ca_ski_missing"}) {
+		t.Errorf("want only CA findings of rfc5280, got %v", names)
+	}
+}
@@ -0,0 +1,224 @@
+package logging
+
+// The logging package provides structured loggers based on log/slog for the subsystems of the server.
+// Each subsystem has its own level, so that e.g. the web server can log client connections at debug level
+// while the CT watcher only logs warnings. The loggers can be created before Setup is called and pick up the
+// configured handler and levels once it was called.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+// Names of the subsystems that can be configured with their own level.
+const (
+	SubsystemServer  = "server"
+	SubsystemWatcher = "watcher"
+	SubsystemTiled   = "tiled"
+	SubsystemWeb     = "web"
+	SubsystemMetrics = "metrics"
+	SubsystemSearch  = "search"
+)
+
+// Keys of the attributes used consistently across all subsystems.
+const (
+	KeyLogURL    = "log_url"
+	KeyOperator  = "operator"
+	KeyClient    = "client"
+	KeyError     = "error"
+	KeyErrorKind = "error_kind"
+)
+
+var (
+	// root is the handler all loggers write to. It's replaced by Setup.
+	root atomic.Pointer[slog.Handler]
+
+	levelsMu sync.Mutex
+	// levels contains the level of each subsystem. Subsystems without an entry use defaultLevel.
+	levels       = make(map[string]*slog.LevelVar)
+	defaultLevel = slog.LevelInfo
+)
+
+func init() {
+	var handler slog.Handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+	root.Store(&handler)
+}
+
+// Logger returns the logger for the given subsystem. Each record carries the subsystem as attribute.
+func Logger(subsystem string) *slog.Logger {
+	return slog.New(&subsystemHandler{level: levelFor(subsystem)}).With("subsystem", subsystem)
+}
+
+// levelFor returns the level variable of the given subsystem, creating it with the default level if necessary.
+func levelFor(subsystem string) *slog.LevelVar {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	level, ok := levels[subsystem]
+	if !ok {
+		level = &slog.LevelVar{}
+		level.Set(defaultLevel)
+		levels[subsystem] = level
+	}
+
+	return level
+}
+
+// Setup configures the format, output and levels of all loggers according to the config.
+// The standard library logger and slog's default logger are redirected to the configured output as well.
+func Setup(conf config.LoggingConfig) error {
+	output, err := openOutput(conf.Output)
+	if err != nil {
+		return err
+	}
+
+	level, err := parseLevel(conf.Level)
+	if err != nil {
+		return err
+	}
+
+	subsystemLevels := make(map[string]slog.Level, len(conf.Levels))
+
+	for subsystem, name := range conf.Levels {
+		subsystemLevel, err := parseLevel(name)
+		if err != nil {
+			return fmt.Errorf("invalid level for subsystem '%s': %w", subsystem, err)
+		}
+
+		subsystemLevels[strings.ToLower(subsystem)] = subsystemLevel
+	}
+
+	// The levels are checked by the subsystem handlers, so the root handler accepts all records
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+
+	var handler slog.Handler
+	if strings.EqualFold(conf.Format, "json") {
+		handler = slog.NewJSONHandler(output, options)
+	} else {
+		handler = slog.NewTextHandler(output, options)
+	}
+
+	root.Store(&handler)
+
+	levelsMu.Lock()
+	defaultLevel = level
+
+	for subsystem, levelVar := range levels {
+		if subsystemLevel, ok := subsystemLevels[subsystem]; ok {
+			levelVar.Set(subsystemLevel)
+		} else {
+			levelVar.Set(level)
+		}
+	}
+
+	for subsystem, subsystemLevel := range subsystemLevels {
+		if _, ok := levels[subsystem]; !ok {
+			levelVar := &slog.LevelVar{}
+			levelVar.Set(subsystemLevel)
+			levels[subsystem] = levelVar
+		}
+	}
+	levelsMu.Unlock()
+
+	// Messages of third party libraries and code not yet using a subsystem logger end up in the same output
+	slog.SetDefault(Logger(SubsystemServer))
+
+	return nil
+}
+
+// openOutput returns the writer for the given output, which is either "stdout", "stderr" or a file path.
+func openOutput(output string) (io.Writer, error) {
+	switch strings.ToLower(output) {
+	case "", "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	}
+
+	file, err := os.OpenFile(output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	return file, nil
+}
+
+// parseLevel parses a level name such as "debug", "info", "warn" or "error".
+func parseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if name == "" {
+		return slog.LevelInfo, nil
+	}
+
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("invalid log level '%s': %w", name, err)
+	}
+
+	return level, nil
+}
+
+// subsystemHandler checks the level of its subsystem and passes the records on to the current root handler.
+type subsystemHandler struct {
+	level *slog.LevelVar
+	// ops are the WithAttrs and WithGroup calls, which are applied to the root handler when handling a record,
+	// since the root handler may be replaced after the logger was created.
+	ops []func(slog.Handler) slog.Handler
+	// derived caches the result of applying ops to the root handler, so that it's only rebuilt after Setup.
+	derived atomic.Pointer[derivedHandler]
+}
+
+// derivedHandler is a root handler with the ops of a subsystemHandler applied.
+type derivedHandler struct {
+	// root is the root handler the handler was derived from.
+	root    *slog.Handler
+	handler slog.Handler
+}
+
+func (h *subsystemHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *subsystemHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler().Handle(ctx, record) //nolint:wrapcheck
+}
+
+// handler returns the current root handler with the ops applied. Since Setup stores a new pointer whenever it
+// replaces the root handler, the cached handler is up to date as long as it was derived from the same pointer.
+func (h *subsystemHandler) handler() slog.Handler {
+	rootHandler := root.Load()
+	if derived := h.derived.Load(); derived != nil && derived.root == rootHandler {
+		return derived.handler
+	}
+
+	handler := *rootHandler
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+
+	h.derived.Store(&derivedHandler{root: rootHandler, handler: handler})
+
+	return handler
+}
+
+func (h *subsystemHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *subsystemHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *subsystemHandler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+
+	return &subsystemHandler{level: h.level, ops: append(ops, op)}
+}
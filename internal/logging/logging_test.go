@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+func TestSetup_SubsystemLevels(t *testing.T) {
+	t.Cleanup(func() { _ = Setup(config.LoggingConfig{}) })
+
+	// Loggers created before Setup must pick up the configured handler and levels
+	webLogger := Logger(SubsystemWeb)
+	watcherLogger := Logger(SubsystemWatcher).With(KeyLogURL, "ct.example.com/log")
+
+	path := filepath.Join(t.TempDir(), "certstream.log")
+
+	err := Setup(config.LoggingConfig{
+		Format: "json",
+		Output: path,
+		Level:  "info",
+		Levels: map[string]string{"web": "warn", "watcher": "debug"},
+	})
+	if err != nil {
+		t.Fatalf("could not set up logging: %v", err)
+	}
+
+	webLogger.Info("client connected", KeyClient, "client-1")
+	webLogger.Warn("client too slow", KeyClient, "client-1")
+	watcherLogger.Debug("processed entries", "count", 1000)
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("could not open log file: %v", err)
+	}
+	defer file.Close()
+
+	var records []map[string]any
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("log line is no valid JSON: %s", scanner.Text())
+		}
+
+		records = append(records, record)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("want 2 records, got %d: %v", len(records), records)
+	}
+
+	if records[0]["msg"] != "client too slow" || records[0]["subsystem"] != "web" || records[0][KeyClient] != "client-1" {
+		t.Errorf("unexpected web record: %v", records[0])
+	}
+
+	if records[1]["level"] != "DEBUG" || records[1]["subsystem"] != "watcher" || records[1][KeyLogURL] != "ct.example.com/log" {
+		t.Errorf("unexpected watcher record: %v", records[1])
+	}
+}
+
+func TestSubsystemHandler_CachesDerivedHandler(t *testing.T) {
+	t.Cleanup(func() { _ = Setup(config.LoggingConfig{}) })
+
+	handler, ok := Logger(SubsystemWeb).With(KeyClient, "client-1").Handler().(*subsystemHandler)
+	if !ok {
+		t.Fatalf("want a subsystemHandler")
+	}
+
+	first := handler.handler()
+	if second := handler.handler(); second != first {
+		t.Errorf("want the derived handler to be reused while the root handler is unchanged")
+	}
+
+	if err := Setup(config.LoggingConfig{Format: "json"}); err != nil {
+		t.Fatalf("could not set up logging: %v", err)
+	}
+
+	if handler.handler() == first {
+		t.Errorf("want the derived handler to be rebuilt after the root handler was replaced")
+	}
+}
+
+func TestSetup_InvalidLevel(t *testing.T) {
+	if err := Setup(config.LoggingConfig{Level: "verbose"}); err == nil {
+		t.Errorf("want an error for an invalid level")
+	}
+}
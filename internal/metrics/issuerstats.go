@@ -0,0 +1,303 @@
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// Issuers counts the certificates per issuer, log operator and signature algorithm. It's nil if disabled.
+var Issuers *IssuerStats
+
+// Windows over which the certificates are counted.
+const (
+	WindowMinute = "1m"
+	WindowHour   = "1h"
+	WindowDay    = "24h"
+)
+
+var windows = []string{WindowMinute, WindowHour, WindowDay}
+
+// pruneInterval is the minimum time between two attempts to remove inactive issuers.
+const pruneInterval = time.Minute
+
+// bucket counts the certificates of a single time slot. epoch is the number of the slot since the unix epoch.
+type bucket struct {
+	epoch int64
+	count uint64
+}
+
+// windowCounter counts certificates in rolling windows of one minute, one hour and one day. Each window is split into
+// buckets, so the counts cover the current, partial bucket and the preceding full buckets.
+type windowCounter struct {
+	seconds [60]bucket
+	minutes [60]bucket
+	hours   [24]bucket
+}
+
+// add counts a certificate at the given unix time.
+func (c *windowCounter) add(now int64) {
+	addToBuckets(c.seconds[:], now)
+	addToBuckets(c.minutes[:], now/60)
+	addToBuckets(c.hours[:], now/3600)
+}
+
+// counts returns the number of certificates per window at the given unix time.
+func (c *windowCounter) counts(now int64) WindowCounts {
+	return WindowCounts{
+		Minute: sumBuckets(c.seconds[:], now),
+		Hour:   sumBuckets(c.minutes[:], now/60),
+		Day:    sumBuckets(c.hours[:], now/3600),
+	}
+}
+
+func addToBuckets(buckets []bucket, epoch int64) {
+	b := &buckets[epoch%int64(len(buckets))]
+	if b.epoch != epoch {
+		*b = bucket{epoch: epoch}
+	}
+
+	b.count++
+}
+
+func sumBuckets(buckets []bucket, epoch int64) uint64 {
+	var sum uint64
+
+	for _, b := range buckets {
+		if b.epoch <= epoch && epoch-b.epoch < int64(len(buckets)) {
+			sum += b.count
+		}
+	}
+
+	return sum
+}
+
+// WindowCounts contains the number of certificates per window.
+type WindowCounts struct {
+	Minute uint64 `json:"1m"`
+	Hour   uint64 `json:"1h"`
+	Day    uint64 `json:"24h"`
+}
+
+// get returns the count of the window, which must be one of WindowMinute, WindowHour and WindowDay.
+func (wc WindowCounts) get(window string) uint64 {
+	switch window {
+	case WindowMinute:
+		return wc.Minute
+	case WindowHour:
+		return wc.Hour
+	default:
+		return wc.Day
+	}
+}
+
+// NamedCounts are the counts of a single issuer, operator or signature algorithm.
+type NamedCounts struct {
+	Name   string       `json:"name"`
+	Counts WindowCounts `json:"counts"`
+}
+
+// IssuerStatsSnapshot contains the counts at a point in time, each ordered from highest to lowest count.
+type IssuerStatsSnapshot struct {
+	Issuers             []NamedCounts `json:"issuers"`
+	Operators           []NamedCounts `json:"operators"`
+	SignatureAlgorithms []NamedCounts `json:"signature_algorithms"`
+}
+
+// IssuerStats counts the certificates per issuer, log operator and signature algorithm in rolling windows, so that
+// spikes and outages of CAs can be spotted. The number of tracked issuers is bounded.
+type IssuerStats struct {
+	mu         sync.Mutex
+	spki       bool
+	topN       int
+	maxIssuers int
+	issuers    map[string]*windowCounter
+	operators  map[string]*windowCounter
+	algorithms map[string]*windowCounter
+	lastPrune  time.Time
+	// now returns the current time. It's replaced in tests.
+	now func() time.Time
+}
+
+// NewIssuerStats creates an empty IssuerStats for the config.
+func NewIssuerStats(conf config.IssuerStatsConfig) *IssuerStats {
+	return &IssuerStats{
+		spki:       conf.IssuerKey == "spki",
+		topN:       conf.TopN,
+		maxIssuers: conf.MaxIssuers,
+		issuers:    make(map[string]*windowCounter),
+		operators:  make(map[string]*windowCounter),
+		algorithms: make(map[string]*windowCounter),
+		now:        time.Now,
+	}
+}
+
+// Observe counts the certificate of the entry.
+func (s *IssuerStats) Observe(entry *models.Entry) {
+	issuer := issuerKey(entry, s.spki)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	unix := now.Unix()
+
+	if _, ok := s.issuers[issuer]; !ok && len(s.issuers) >= s.maxIssuers {
+		s.pruneIssuers(now)
+
+		if len(s.issuers) >= s.maxIssuers {
+			issuer = otherIssuers
+		}
+	}
+
+	counterFor(s.issuers, issuer).add(unix)
+	counterFor(s.operators, cmp.Or(entry.Data.Source.Operator, unknownName)).add(unix)
+	counterFor(s.algorithms, cmp.Or(entry.Data.LeafCert.SignatureAlgorithm, unknownName)).add(unix)
+}
+
+// IssuerKey returns the key identifying the issuer of the entry in all metrics, according to the issuer_key of the
+// issuer stats config.
+func IssuerKey(entry *models.Entry) string {
+	return issuerKey(entry, config.AppConfig.IssuerStats.IssuerKey == "spki")
+}
+
+// issuerKey returns the SPKI hash of the issuer certificate if spki is set, else the aggregated name of the issuer.
+func issuerKey(entry *models.Entry, spki bool) string {
+	if spki {
+		return issuerSPKI(entry)
+	}
+
+	return issuerName(&entry.Data.LeafCert.Issuer)
+}
+
+// issuerName returns the aggregated name of the issuer.
+func issuerName(issuer *models.Subject) string {
+	if issuer.Aggregated == nil || *issuer.Aggregated == "" {
+		return unknownName
+	}
+
+	return *issuer.Aggregated
+}
+
+// issuerSPKI returns the SPKI hash of the issuer certificate, which is the first certificate of the chain.
+func issuerSPKI(entry *models.Entry) string {
+	if len(entry.Data.Chain) == 0 || entry.Data.Chain[0].PublicKey == nil || entry.Data.Chain[0].PublicKey.SPKISHA256 == "" {
+		return unknownName
+	}
+
+	return entry.Data.Chain[0].PublicKey.SPKISHA256
+}
+
+// counterFor returns the counter of the name, creating it if necessary.
+func counterFor(counters map[string]*windowCounter, name string) *windowCounter {
+	counter, ok := counters[name]
+	if !ok {
+		counter = &windowCounter{}
+		counters[name] = counter
+	}
+
+	return counter
+}
+
+// pruneIssuers removes the issuers without certificates within the last day. Since this requires iterating over all
+// issuers, it's done at most once per pruneInterval.
+func (s *IssuerStats) pruneIssuers(now time.Time) {
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return
+	}
+
+	s.lastPrune = now
+
+	for name, counter := range s.issuers {
+		if name != otherIssuers && counter.counts(now.Unix()).Day == 0 {
+			delete(s.issuers, name)
+		}
+	}
+}
+
+// Snapshot returns the current counts ordered by the count of the given window. The issuers are limited to the
+// given number, with 0 meaning no limit.
+func (s *IssuerStats) Snapshot(sortWindow string, limit int) IssuerStatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().Unix()
+
+	issuers := sortedCounts(s.issuers, now, sortWindow)
+	if limit > 0 && len(issuers) > limit {
+		issuers = issuers[:limit]
+	}
+
+	return IssuerStatsSnapshot{
+		Issuers:             issuers,
+		Operators:           sortedCounts(s.operators, now, sortWindow),
+		SignatureAlgorithms: sortedCounts(s.algorithms, now, sortWindow),
+	}
+}
+
+// sortedCounts returns the counts of all names with certificates within the last day, ordered from highest to lowest
+// count of the window and by name.
+func sortedCounts(counters map[string]*windowCounter, now int64, window string) []NamedCounts {
+	result := make([]NamedCounts, 0, len(counters))
+
+	for name, counter := range counters {
+		if counts := counter.counts(now); counts.Day > 0 {
+			result = append(result, NamedCounts{Name: name, Counts: counts})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b NamedCounts) int {
+		return cmp.Or(cmp.Compare(b.Counts.get(window), a.Counts.get(window)), cmp.Compare(a.Name, b.Name))
+	})
+
+	return result
+}
+
+// writePrometheus writes the counts of the top issuers by daily count, the operators and the signature algorithms
+// as gauges per window. The remaining issuers are summed up as "other" to bound the number of series.
+func (s *IssuerStats) writePrometheus(w io.Writer) {
+	snapshot := s.Snapshot(WindowDay, 0)
+
+	var other WindowCounts
+
+	exported := 0
+
+	for _, issuer := range snapshot.Issuers {
+		if exported < s.topN && issuer.Name != otherIssuers {
+			writeWindowGauges(w, "certstreamservergo_issuer_certificates", "issuer", issuer)
+			exported++
+
+			continue
+		}
+
+		other.Minute += issuer.Counts.Minute
+		other.Hour += issuer.Counts.Hour
+		other.Day += issuer.Counts.Day
+	}
+
+	writeWindowGauges(w, "certstreamservergo_issuer_certificates", "issuer", NamedCounts{Name: otherIssuers, Counts: other})
+
+	for _, operator := range snapshot.Operators {
+		writeWindowGauges(w, "certstreamservergo_operator_certificates", "operator", operator)
+	}
+
+	for _, algorithm := range snapshot.SignatureAlgorithms {
+		writeWindowGauges(w, "certstreamservergo_signature_algorithm_certificates", "algorithm", algorithm)
+	}
+}
+
+// writeWindowGauges writes a gauge per window with the name as value of the given label.
+func writeWindowGauges(w io.Writer, metricName, label string, counts NamedCounts) {
+	for _, window := range windows {
+		name := fmt.Sprintf("%s{%s=\"%s\",window=\"%s\"}", metricName, label, escapeLabelValue(counts.Name), window)
+		metrics.WriteGaugeUint64(w, name, counts.Counts.get(window))
+	}
+}
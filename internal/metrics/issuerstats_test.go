@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+func newTestIssuerStats(conf config.IssuerStatsConfig) (*IssuerStats, *time.Time) {
+	stats := NewIssuerStats(conf)
+
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	stats.now = func() time.Time { return now }
+
+	return stats, &now
+}
+
+func issuerEntry(issuer, operator, algorithm string) *models.Entry {
+	entry := &models.Entry{}
+	entry.Data.LeafCert.Issuer.Aggregated = &issuer
+	entry.Data.Source.Operator = operator
+	entry.Data.LeafCert.SignatureAlgorithm = algorithm
+
+	return entry
+}
+
+func TestIssuerStats_Windows(t *testing.T) {
+	stats, now := newTestIssuerStats(config.IssuerStatsConfig{TopN: 10, MaxIssuers: 100})
+
+	stats.Observe(issuerEntry("/CN=R10", "Google", "sha256, rsa"))
+
+	*now = now.Add(30 * time.Minute)
+	stats.Observe(issuerEntry("/CN=R10", "Google", "sha256, rsa"))
+
+	*now = now.Add(30 * time.Second)
+	stats.Observe(issuerEntry("/CN=R10", "Cloudflare", "ecdsa, sha384"))
+
+	snapshot := stats.Snapshot(WindowDay, 0)
+	if len(snapshot.Issuers) != 1 || snapshot.Issuers[0].Counts != (WindowCounts{Minute: 2, Hour: 3, Day: 3}) {
+		t.Errorf("unexpected issuer counts: %+v", snapshot.Issuers)
+	}
+
+	if len(snapshot.Operators) != 2 || snapshot.Operators[0].Name != "Google" || snapshot.Operators[0].Counts.Day != 2 {
+		t.Errorf("unexpected operator counts: %+v", snapshot.Operators)
+	}
+
+	if len(snapshot.SignatureAlgorithms) != 2 {
+		t.Errorf("unexpected signature algorithm counts: %+v", snapshot.SignatureAlgorithms)
+	}
+
+	*now = now.Add(time.Hour)
+
+	snapshot = stats.Snapshot(WindowDay, 0)
+	if snapshot.Issuers[0].Counts != (WindowCounts{Day: 3}) {
+		t.Errorf("want only the daily count after an hour, got %+v", snapshot.Issuers[0].Counts)
+	}
+
+	*now = now.Add(24 * time.Hour)
+
+	if snapshot = stats.Snapshot(WindowDay, 0); len(snapshot.Issuers) != 0 {
+		t.Errorf("want no issuers after a day, got %+v", snapshot.Issuers)
+	}
+}
+
+func TestIssuerStats_SPKI(t *testing.T) {
+	stats, _ := newTestIssuerStats(config.IssuerStatsConfig{IssuerKey: "spki", TopN: 10, MaxIssuers: 100})
+
+	entry := issuerEntry("/CN=R10", "Google", "sha256, rsa")
+	entry.Data.Chain = []models.LeafCert{{PublicKey: &models.PublicKey{SPKISHA256: "c2BpLy0z"}}}
+
+	stats.Observe(entry)
+	stats.Observe(issuerEntry("/CN=R10", "Google", "sha256, rsa"))
+
+	snapshot := stats.Snapshot(WindowDay, 0)
+	if len(snapshot.Issuers) != 2 || snapshot.Issuers[0].Name != "c2BpLy0z" || snapshot.Issuers[1].Name != unknownName {
+		t.Errorf("want issuers keyed by the SPKI of the issuer certificate, got %+v", snapshot.Issuers)
+	}
+}
+
+func TestIssuerKey(t *testing.T) {
+	previous := config.AppConfig.IssuerStats
+	t.Cleanup(func() { config.AppConfig.IssuerStats = previous })
+
+	entry := issuerEntry("/C=US/O=Let's Encrypt/CN=R10", "Google", "sha256, rsa")
+	entry.Data.Chain = []models.LeafCert{{PublicKey: &models.PublicKey{SPKISHA256: "c2BpLy0z"}}}
+
+	config.AppConfig.IssuerStats.IssuerKey = "name"
+	if got := IssuerKey(entry); got != "/C=US/O=Let's Encrypt/CN=R10" {
+		t.Errorf("want aggregated issuer name, got '%s'", got)
+	}
+
+	config.AppConfig.IssuerStats.IssuerKey = "spki"
+	if got := IssuerKey(entry); got != "c2BpLy0z" {
+		t.Errorf("want SPKI of the issuer certificate, got '%s'", got)
+	}
+}
+
+func TestIssuerStats_Sorting(t *testing.T) {
+	stats, _ := newTestIssuerStats(config.IssuerStatsConfig{TopN: 10, MaxIssuers: 100})
+
+	for _, issuer := range []string{"B", "A", "C", "C", "B", "C"} {
+		stats.Observe(issuerEntry(issuer, "Google", "sha256, rsa"))
+	}
+
+	snapshot := stats.Snapshot(WindowMinute, 2)
+	if len(snapshot.Issuers) != 2 || snapshot.Issuers[0].Name != "C" || snapshot.Issuers[1].Name != "B" {
+		t.Errorf("want the two most active issuers, got %+v", snapshot.Issuers)
+	}
+}
+
+func TestIssuerStats_MaxIssuers(t *testing.T) {
+	stats, now := newTestIssuerStats(config.IssuerStatsConfig{TopN: 1, MaxIssuers: 2})
+
+	for _, issuer := range []string{"A", "B", "C", "D"} {
+		stats.Observe(issuerEntry(issuer, "Google", "sha256, rsa"))
+	}
+
+	if len(stats.issuers) != 3 || stats.issuers[otherIssuers] == nil {
+		t.Errorf("want two issuers and other, got %d issuers", len(stats.issuers))
+	}
+
+	// Inactive issuers are removed to make room for new ones
+	*now = now.Add(25 * time.Hour)
+	stats.Observe(issuerEntry("E", "Google", "sha256, rsa"))
+
+	if stats.issuers["E"] == nil {
+		t.Errorf("want new issuer to be tracked after the inactive ones were removed")
+	}
+}
+
+func TestIssuerStats_WritePrometheus(t *testing.T) {
+	stats, _ := newTestIssuerStats(config.IssuerStatsConfig{TopN: 1, MaxIssuers: 100})
+
+	for _, issuer := range []string{"A", "B", "B", `C "quoted"`} {
+		stats.Observe(issuerEntry(issuer, "Google", "sha256, rsa"))
+	}
+
+	var buf bytes.Buffer
+	stats.writePrometheus(&buf)
+
+	output := buf.String()
+
+	for _, want := range []string{
+		`certstreamservergo_issuer_certificates{issuer="B",window="1m"} 2`,
+		`certstreamservergo_issuer_certificates{issuer="other",window="24h"} 2`,
+		`certstreamservergo_operator_certificates{operator="Google",window="1h"} 4`,
+		`certstreamservergo_signature_algorithm_certificates{algorithm="sha256, rsa",window="24h"} 4`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("want '%s' in output:\n%s", want, output)
+		}
+	}
+
+	if strings.Contains(output, `issuer="A"`) {
+		t.Errorf("want issuers beyond the top N to be summed up as other:\n%s", output)
+	}
+}
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// LintResults counts the linted certificates and the findings per issuer. It's nil if linting is disabled.
+var LintResults *LintStats
+
+const (
+	// otherIssuers is the name under which the issuers beyond the tracked or exported ones are counted.
+	otherIssuers = "other"
+	// unknownName is used for certificates without issuer, operator or signature algorithm.
+	unknownName = "unknown"
+)
+
+// lintFindingKey identifies the counter of a check and severity.
+type lintFindingKey struct {
+	name     string
+	severity string
+}
+
+// lintCounts are the lint results of an issuer.
+type lintCounts struct {
+	pass     uint64
+	fail     uint64
+	findings map[lintFindingKey]uint64
+}
+
+func newLintCounts() *lintCounts {
+	return &lintCounts{findings: make(map[lintFindingKey]uint64)}
+}
+
+// add counts a linted certificate with its findings.
+func (c *lintCounts) add(findings []models.LintFinding) {
+	if len(findings) == 0 {
+		c.pass++
+		return
+	}
+
+	c.fail++
+
+	for _, finding := range findings {
+		c.findings[lintFindingKey{name: finding.Name, severity: finding.Severity}]++
+	}
+}
+
+// LintStats counts the lint results per issuer. The issuer names are taken from the certificates, so the number of
+// tracked issuers is bounded and only the issuers with the most linted certificates are exported as separate series.
+//
+// To keep the exported counters monotonic, an issuer stays exported once it was among the top issuers, and "other"
+// only counts the certificates of issuers that weren't exported at the time they were linted.
+type LintStats struct {
+	mu         sync.Mutex
+	topN       int
+	maxIssuers int
+	// issuers contains the counts of the tracked issuers since the start of the server.
+	issuers map[string]*lintCounts
+	// exported contains the issuers exported as separate series.
+	exported map[string]bool
+	other    *lintCounts
+}
+
+// NewLintStats creates an empty LintStats for the config.
+func NewLintStats(conf config.LintConfig) *LintStats {
+	return &LintStats{
+		topN:       conf.TopIssuers,
+		maxIssuers: conf.MaxIssuers,
+		issuers:    make(map[string]*lintCounts),
+		exported:   make(map[string]bool),
+		other:      newLintCounts(),
+	}
+}
+
+// Observe counts a linted certificate of the issuer with its findings. Issuers beyond the maximum number of tracked
+// issuers are only counted as "other".
+func (s *LintStats) Observe(issuer string, findings []models.LintFinding) {
+	issuer = cmp.Or(issuer, unknownName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts, ok := s.issuers[issuer]
+	if !ok && len(s.issuers) < s.maxIssuers {
+		counts = newLintCounts()
+		s.issuers[issuer] = counts
+	}
+
+	if counts != nil {
+		counts.add(findings)
+	}
+
+	if !s.exported[issuer] {
+		s.other.add(findings)
+	}
+}
+
+// promoteTopIssuers exports the current top issuers by number of linted certificates that aren't exported yet.
+func (s *LintStats) promoteTopIssuers() {
+	names := make([]string, 0, len(s.issuers))
+	for name := range s.issuers {
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		countA, countB := s.issuers[a], s.issuers[b]
+		return cmp.Or(cmp.Compare(countB.pass+countB.fail, countA.pass+countA.fail), cmp.Compare(a, b))
+	})
+
+	for _, name := range names[:min(s.topN, len(names))] {
+		s.exported[name] = true
+	}
+}
+
+// writePrometheus writes the counters of the exported issuers and of "other".
+func (s *LintStats) writePrometheus(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.promoteTopIssuers()
+
+	names := make([]string, 0, len(s.exported))
+	for name := range s.exported {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		writeLintCounters(w, name, s.issuers[name])
+	}
+
+	writeLintCounters(w, otherIssuers, s.other)
+}
+
+// writeLintCounters writes the certificate and finding counters of the issuer.
+func writeLintCounters(w io.Writer, issuer string, counts *lintCounts) {
+	issuer = escapeLabelValue(issuer)
+
+	metrics.WriteCounterUint64(w, fmt.Sprintf("certstreamservergo_lint_certificates_total{issuer=\"%s\",result=\"pass\"}", issuer), counts.pass)
+	metrics.WriteCounterUint64(w, fmt.Sprintf("certstreamservergo_lint_certificates_total{issuer=\"%s\",result=\"fail\"}", issuer), counts.fail)
+
+	keys := make([]lintFindingKey, 0, len(counts.findings))
+	for key := range counts.findings {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b lintFindingKey) int {
+		return cmp.Or(cmp.Compare(a.name, b.name), cmp.Compare(a.severity, b.severity))
+	})
+
+	for _, key := range keys {
+		name := fmt.Sprintf("certstreamservergo_lint_findings_total{issuer=\"%s\",lint=\"%s\",severity=\"%s\"}", issuer, key.name, key.severity)
+		metrics.WriteCounterUint64(w, name, counts.findings[key])
+	}
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+func writeLintStats(stats *LintStats) string {
+	var buf bytes.Buffer
+	stats.writePrometheus(&buf)
+
+	return buf.String()
+}
+
+func TestLintStats_WritePrometheus(t *testing.T) {
+	stats := NewLintStats(config.LintConfig{TopIssuers: 1, MaxIssuers: 3})
+
+	finding := models.LintFinding{Name: "e_cab_validity_too_long", Severity: "error"}
+
+	stats.Observe("A", nil)
+	stats.Observe("B", []models.LintFinding{finding})
+	stats.Observe("B", nil)
+	stats.Observe(`C "quoted"`, []models.LintFinding{finding})
+	// Issuers beyond the maximum are only counted as other
+	stats.Observe("D", nil)
+
+	if len(stats.issuers) != 3 || stats.issuers["D"] != nil {
+		t.Errorf("want three tracked issuers, got %d issuers", len(stats.issuers))
+	}
+
+	output := writeLintStats(stats)
+
+	for _, want := range []string{
+		`certstreamservergo_lint_certificates_total{issuer="B",result="pass"} 1`,
+		`certstreamservergo_lint_certificates_total{issuer="B",result="fail"} 1`,
+		`certstreamservergo_lint_findings_total{issuer="B",lint="e_cab_validity_too_long",severity="error"} 1`,
+		`certstreamservergo_lint_certificates_total{issuer="other",result="pass"} 3`,
+		`certstreamservergo_lint_certificates_total{issuer="other",result="fail"} 2`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("want '%s' in output:\n%s", want, output)
+		}
+	}
+
+	if strings.Contains(output, `issuer="A"`) || strings.Contains(output, `issuer="C`) {
+		t.Errorf("want issuers beyond the top issuers to be summed up as other:\n%s", output)
+	}
+}
+
+func TestLintStats_CountersNeverDecrease(t *testing.T) {
+	stats := NewLintStats(config.LintConfig{TopIssuers: 1, MaxIssuers: 10})
+
+	stats.Observe("A", nil)
+	writeLintStats(stats)
+
+	// B overtakes A, but A stays exported. Other contains the certificates linted before the issuers were exported
+	for range 3 {
+		stats.Observe("B", nil)
+	}
+
+	output := writeLintStats(stats)
+
+	for _, want := range []string{
+		`certstreamservergo_lint_certificates_total{issuer="A",result="pass"} 1`,
+		`certstreamservergo_lint_certificates_total{issuer="B",result="pass"} 3`,
+		`certstreamservergo_lint_certificates_total{issuer="other",result="pass"} 4`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("want '%s' in output:\n%s", want, output)
+		}
+	}
+
+	// Certificates of exported issuers aren't counted as other anymore
+	stats.Observe("B", nil)
+
+	if output := writeLintStats(stats); !strings.Contains(output, `certstreamservergo_lint_certificates_total{issuer="other",result="pass"} 4`) {
+		t.Errorf("want other to stay unchanged:\n%s", output)
+	}
+}
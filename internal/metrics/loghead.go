@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"time"
+)
+
+// LogHead contains the latest known tree head of a CT log and the time of the last successful fetch from it.
+type LogHead struct {
+	// TreeSize is the number of entries in the log according to the latest STH or checkpoint.
+	TreeSize uint64
+	// Timestamp is the time of the latest STH. Checkpoints of tiled logs have no timestamp, so the time
+	// the checkpoint was fetched is used instead.
+	Timestamp time.Time
+	// LastFetch is the time of the last successful request for the tree head or entries.
+	LastFetch time.Time
+}
+
+// SetTreeHead stores the tree size and timestamp of the latest tree head of the given CT url.
+func (m *LogMetrics) SetTreeHead(url string, treeSize uint64, timestamp time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.heads == nil {
+		m.heads = make(map[string]LogHead)
+	}
+
+	m.heads[url] = LogHead{TreeSize: treeSize, Timestamp: timestamp, LastFetch: time.Now()}
+}
+
+// SetLastFetch records a successful fetch from the given CT url.
+func (m *LogMetrics) SetLastFetch(url string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.heads == nil {
+		m.heads = make(map[string]LogHead)
+	}
+
+	head := m.heads[url]
+	head.LastFetch = time.Now()
+	m.heads[url] = head
+}
+
+// GetLogHead returns the latest tree head of the given CT url. It's the zero value if no tree head was fetched yet.
+func (m *LogMetrics) GetLogHead(url string) LogHead {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.heads[url]
+}
+
+// GetLag returns the number of entries of the given CT url that were not processed yet.
+// It's 0 as long as the tree size or the processed index is unknown.
+func (m *LogMetrics) GetLag(url string) uint64 {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	treeSize, index := m.heads[url].TreeSize, m.index[url]
+	if index == 0 || treeSize <= index+1 {
+		return 0
+	}
+
+	return treeSize - index - 1
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogMetrics_GetLag(t *testing.T) {
+	m := LogMetrics{metrics: make(CTMetrics), index: make(CTCertIndex)}
+	url := "ct.example.com/log"
+
+	if lag := m.GetLag(url); lag != 0 {
+		t.Errorf("want lag 0 for unknown log, got %d", lag)
+	}
+
+	m.SetTreeHead(url, 1000, time.Now())
+
+	if lag := m.GetLag(url); lag != 0 {
+		t.Errorf("want lag 0 while the processed index is unknown, got %d", lag)
+	}
+
+	m.Inc("operator", url, 899)
+
+	if lag := m.GetLag(url); lag != 100 {
+		t.Errorf("want lag 100, got %d", lag)
+	}
+
+	m.Inc("operator", url, 999)
+
+	if lag := m.GetLag(url); lag != 0 {
+		t.Errorf("want lag 0 after processing the last entry, got %d", lag)
+	}
+
+	if head := m.GetLogHead(url); head.TreeSize != 1000 || head.LastFetch.IsZero() {
+		t.Errorf("unexpected log head: %+v", head)
+	}
+}
@@ -3,13 +3,17 @@ package metrics
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"maps"
 	"os"
 	"sync"
 	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
 )
 
+// logger is used for the metrics and the CT index file.
+var logger = logging.Logger(logging.SubsystemMetrics)
+
 type (
 	// OperatorLogs is a map of operator names to a list of CT log urls, operated by said operator.
 	OperatorLogs map[string][]string
@@ -24,7 +28,7 @@ type (
 var (
 	ProcessedCerts    int64
 	ProcessedPrecerts int64
-	Metrics           = LogMetrics{metrics: make(CTMetrics), index: make(CTCertIndex)}
+	Metrics           = LogMetrics{metrics: make(CTMetrics), index: make(CTCertIndex), heads: make(map[string]LogHead)}
 )
 
 // LogMetrics is a struct that holds a map of metrics for each CT log grouped by operator.
@@ -33,6 +37,7 @@ type LogMetrics struct {
 	mutex   sync.RWMutex
 	metrics CTMetrics
 	index   CTCertIndex
+	heads   map[string]LogHead
 }
 
 // GetCTMetrics returns a copy of the internal metrics map.
@@ -169,13 +174,13 @@ func (m *LogMetrics) SetCTIndex(url string, index uint64) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	log.Printf("Setting CT index for %s to %d\n", url, index)
+	logger.Debug("Setting CT index", logging.KeyLogURL, url, "index", index)
 	m.index[url] = index
 }
 
 // LoadCTIndex loads the last cert index processed for each CT url if it exists.
 func (m *LogMetrics) LoadCTIndex(ctIndexFilePath string) {
-	log.Println("Loading CT indexes from file: ", ctIndexFilePath)
+	logger.Info("Loading CT indexes from file", "file", ctIndexFilePath)
 
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -186,34 +191,34 @@ func (m *LogMetrics) LoadCTIndex(ctIndexFilePath string) {
 		if os.IsNotExist(readErr) {
 			err := m.createCTIndexFile(ctIndexFilePath)
 			if err != nil {
-				log.Printf("Error creating CT index file: '%s'\n", ctIndexFilePath)
-				log.Panicln(err)
+				logger.Error("Error creating CT index file", "file", ctIndexFilePath, logging.KeyError, err)
+				panic(err)
 			}
 
 			bytes = []byte("{}")
 		} else {
 			// If the file exists, but we can't read it, log the error and panic
-			log.Panicln(readErr)
+			logger.Error("Error reading CT index file", "file", ctIndexFilePath, logging.KeyError, readErr)
+			panic(readErr)
 		}
 	}
 
 	jerr := json.Unmarshal(bytes, &m.index)
 	if jerr != nil {
-		log.Printf("Error unmarshalling CT index file: '%s'\n", ctIndexFilePath)
-		log.Panicln(jerr)
+		logger.Error("Error unmarshalling CT index file", "file", ctIndexFilePath, logging.KeyError, jerr)
+		panic(jerr)
 	}
 
-	log.Println("Successfully loaded saved CT indexes")
+	logger.Info("Successfully loaded saved CT indexes")
 }
 
 func (m *LogMetrics) createCTIndexFile(ctIndexFilePath string) error {
-	log.Printf("Specified CT index file does not exist: '%s'\n", ctIndexFilePath)
-	log.Println("Creating CT index file now!")
+	logger.Info("Specified CT index file does not exist. Creating CT index file now!", "file", ctIndexFilePath)
 
 	file, createErr := os.Create(ctIndexFilePath)
 	if createErr != nil {
-		log.Printf("Error creating CT index file: '%s'\n", ctIndexFilePath)
-		log.Panicln(createErr)
+		logger.Error("Error creating CT index file", "file", ctIndexFilePath, logging.KeyError, createErr)
+		panic(createErr)
 	}
 	defer file.Close()
 
@@ -228,8 +233,8 @@ func (m *LogMetrics) createCTIndexFile(ctIndexFilePath string) error {
 
 	_, writeErr := file.Write(bytes)
 	if writeErr != nil {
-		log.Printf("Error writing to CT index file: '%s'\n", ctIndexFilePath)
-		log.Panicln(writeErr)
+		logger.Error("Error writing to CT index file", "file", ctIndexFilePath, logging.KeyError, writeErr)
+		panic(writeErr)
 	}
 
 	return nil
@@ -245,7 +250,7 @@ func (m *LogMetrics) SaveCertIndexesAtInterval(interval time.Duration, ctIndexFi
 
 	for range ticker.C {
 		if err := m.SaveCertIndexes(ctIndexFilePath); err != nil {
-			log.Printf("Error saving CT indexes at '%s': %s\n", ctIndexFilePath, err)
+			logger.Error("Error saving CT indexes", "file", ctIndexFilePath, logging.KeyError, err)
 		}
 	}
 }
@@ -259,7 +264,7 @@ func (m *LogMetrics) SaveCertIndexes(ctIndexFilePath string) error {
 
 	bytes, cerr := json.MarshalIndent(ctIndex, "", " ")
 	if cerr != nil {
-		log.Panic(cerr)
+		panic(cerr)
 	}
 
 	// Store index data in a temp file
@@ -3,11 +3,13 @@ package metrics
 import (
 	"fmt"
 	"io"
-	"log"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
 )
 
 var Prometheus = NewPrometheusExporter()
@@ -27,11 +29,16 @@ type PrometheusExporter struct {
 	tempCertMetricsMutex         sync.RWMutex
 
 	skippedCertsCallback func() map[string]int64
+
+	// entryAge is the time between the inclusion of an entry in the CT log and its broadcast.
+	entryAge *metrics.PrometheusHistogram
 }
 
 // NewPrometheusExporter creates a new PrometheusExporter and registers the default metrics for the number of processed certificates.
 func NewPrometheusExporter() *PrometheusExporter {
-	exporter := &PrometheusExporter{}
+	exporter := &PrometheusExporter{
+		entryAge: metrics.GetOrCreatePrometheusHistogramExt("certstreamservergo_entry_age_seconds", metrics.ExponentialBuckets(1, 2, 14)),
+	}
 	// Register metrics for the total number of certificates processed by the CT watcher.
 	metrics.GetOrCreateGauge("certstreamservergo_certificates_total{type=\"regular\"}", func() float64 {
 		return float64(GetProcessedCerts())
@@ -46,6 +53,14 @@ func NewPrometheusExporter() *PrometheusExporter {
 // Write is a callback function that is called by a webserver in order to write metrics data to the http response.
 func (pm *PrometheusExporter) Write(w io.Writer, exposeProcessMetrics bool) {
 	metrics.WritePrometheus(w, exposeProcessMetrics)
+
+	if Issuers != nil {
+		Issuers.writePrometheus(w)
+	}
+
+	if LintResults != nil {
+		LintResults.writePrometheus(w)
+	}
 }
 
 // RegisterGaugeMetric is a helper function that registers a new gauge metric with a float64 callback function.
@@ -70,14 +85,88 @@ func (pm *PrometheusExporter) UnregisterClient(name string) {
 	metrics.UnregisterMetric(label)
 }
 
+// IncRejectedConnection increments the number of rejected websocket connections for the given reason.
+func (pm *PrometheusExporter) IncRejectedConnection(reason string) {
+	label := fmt.Sprintf("certstreamservergo_rejected_connections_total{reason=\"%s\"}", reason)
+	metrics.GetOrCreateCounter(label).Inc()
+}
+
+// IncSearchDroppedEntries increments the number of entries that weren't written to the search index because the
+// writer couldn't keep up.
+func (pm *PrometheusExporter) IncSearchDroppedEntries() {
+	metrics.GetOrCreateCounter("certstreamservergo_search_dropped_entries_total").Inc()
+}
+
+// IncLogError increments the number of errors of the given type, e.g. "http_5xx" or "parse_x509", for the given CT log.
+func (pm *PrometheusExporter) IncLogError(operatorName, url, errorType string) {
+	label := fmt.Sprintf("certstreamservergo_log_errors_total{url=\"%s\",operator=\"%s\",type=\"%s\"}", url, operatorName, errorType)
+	metrics.GetOrCreateCounter(label).Inc()
+}
+
+// IncWorkerRestart increments the number of restarts of the worker for the given CT log.
+func (pm *PrometheusExporter) IncWorkerRestart(operatorName, url string) {
+	label := fmt.Sprintf("certstreamservergo_worker_restarts_total{url=\"%s\",operator=\"%s\"}", url, operatorName)
+	metrics.GetOrCreateCounter(label).Inc()
+}
+
+// escapeLabelValue escapes backslashes, quotes and newlines in a label value, which may come from a certificate.
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
 // RegisterLog registers a new gauge metric for the given CT log.
 // The metric will be named "certstreamservergo_certs_by_log_total{url=\"<url>\",operator=\"<operatorName>\"}" and
 // will call the given callback function to get the current value of the metric.
+// Additionally, the gauges for the log's tree head and lag are registered.
 func (pm *PrometheusExporter) RegisterLog(operatorName, url string) {
 	label := fmt.Sprintf("certstreamservergo_certs_by_log_total{url=\"%s\",operator=\"%s\"}", url, operatorName)
 	metrics.GetOrCreateGauge(label, func() float64 {
 		return float64(pm.getCertCountForLog(operatorName, url))
 	})
+
+	pm.registerLogHead(operatorName, url)
+}
+
+// registerLogHead registers the gauges for the tree size, processed index, lag and time since the last fetch of the given CT log.
+func (pm *PrometheusExporter) registerLogHead(operatorName, url string) {
+	labels := fmt.Sprintf("{url=\"%s\",operator=\"%s\"}", url, operatorName)
+
+	metrics.GetOrCreateGauge("certstreamservergo_log_tree_size"+labels, func() float64 {
+		return float64(Metrics.GetLogHead(url).TreeSize)
+	})
+	metrics.GetOrCreateGauge("certstreamservergo_log_tree_head_timestamp_seconds"+labels, func() float64 {
+		return unixSeconds(Metrics.GetLogHead(url).Timestamp)
+	})
+	metrics.GetOrCreateGauge("certstreamservergo_log_processed_index"+labels, func() float64 {
+		return float64(Metrics.GetCTIndex(url))
+	})
+	metrics.GetOrCreateGauge("certstreamservergo_log_lag_entries"+labels, func() float64 {
+		return float64(Metrics.GetLag(url))
+	})
+	metrics.GetOrCreateGauge("certstreamservergo_log_seconds_since_last_fetch"+labels, func() float64 {
+		lastFetch := Metrics.GetLogHead(url).LastFetch
+		if lastFetch.IsZero() {
+			return 0
+		}
+
+		return time.Since(lastFetch).Seconds()
+	})
+}
+
+// ObserveEntryAge records the age of an entry, i.e. the time between its inclusion in the CT log and its broadcast.
+func (pm *PrometheusExporter) ObserveEntryAge(age time.Duration) {
+	pm.entryAge.Update(age.Seconds())
+}
+
+// unixSeconds returns the unix timestamp of t in seconds or 0 for the zero time.
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return float64(t.UnixMilli()) / 1_000
 }
 
 // UnregisterMetric unregisters a metric with a given label.
@@ -99,13 +188,13 @@ func (pm *PrometheusExporter) getCertCountForLog(operatorName, logname string) i
 
 	operatorMetrics, ok := pm.tempCertMetrics[operatorName]
 	if !ok {
-		log.Printf("No metrics for operator \"%s\"", operatorName)
+		logger.Debug("No metrics for operator", logging.KeyOperator, operatorName)
 		return 0
 	}
 
 	count, ok := operatorMetrics[logname]
 	if !ok {
-		log.Printf("No metrics for log \"%s\" of operator \"%s\"", logname, operatorName)
+		logger.Debug("No metrics for log", logging.KeyLogURL, logname, logging.KeyOperator, operatorName)
 		return 0
 	}
 
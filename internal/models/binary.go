@@ -0,0 +1,485 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+)
+
+// logger is used for errors while encoding messages for the clients.
+var logger = logging.Logger(logging.SubsystemWeb)
+
+// The types in this file define the CBOR representation of the messages sent to clients that negotiated the
+// binary wire format. Integer keys are used instead of field names and DER data is carried as raw bytes instead of
+// base64. Optional fields and fields that are null in the JSON representation are omitted.
+// The message type is always stored under key 2, so clients can tell the messages apart.
+// See docs/binary-format.md for the full schema.
+
+var cborEncMode = mustCBOREncMode()
+
+// mustCBOREncMode creates the CBOR encoding mode used for all binary messages.
+func mustCBOREncMode() cbor.EncMode {
+	encMode, err := cbor.EncOptions{ShortestFloat: cbor.ShortestFloat16}.EncMode()
+	if err != nil {
+		panic(fmt.Sprintf("error creating CBOR encoder: %s", err))
+	}
+
+	return encMode
+}
+
+type cborEntry struct {
+	Data        cborData `cbor:"1,keyasint"`
+	MessageType string   `cbor:"2,keyasint"`
+	Seq         uint64   `cbor:"3,keyasint,omitempty"`
+}
+
+type cborData struct {
+	CertIndex  uint64         `cbor:"1,keyasint"`
+	CertLink   string         `cbor:"2,keyasint"`
+	Chain      []cborLeafCert `cbor:"3,keyasint,omitempty"`
+	LeafCert   cborLeafCert   `cbor:"4,keyasint"`
+	Seen       float64        `cbor:"5,keyasint"`
+	Source     cborSource     `cbor:"6,keyasint"`
+	UpdateType string         `cbor:"7,keyasint"`
+	Lint       *cborLint      `cbor:"8,keyasint,omitempty"`
+	Score      *cborScore     `cbor:"9,keyasint,omitempty"`
+}
+
+type cborLint struct {
+	Findings []cborLintFinding `cbor:"1,keyasint"`
+}
+
+type cborLintFinding struct {
+	Name     string `cbor:"1,keyasint"`
+	Set      string `cbor:"2,keyasint"`
+	Severity string `cbor:"3,keyasint"`
+	Details  string `cbor:"4,keyasint,omitempty"`
+}
+
+type cborScore struct {
+	Value   int               `cbor:"1,keyasint"`
+	Reasons []cborScoreReason `cbor:"2,keyasint"`
+}
+
+type cborScoreReason struct {
+	Rule    string `cbor:"1,keyasint"`
+	Domain  string `cbor:"2,keyasint"`
+	Details string `cbor:"3,keyasint,omitempty"`
+	Points  int    `cbor:"4,keyasint"`
+}
+
+type cborSource struct {
+	Name      string  `cbor:"1,keyasint"`
+	URL       string  `cbor:"2,keyasint"`
+	Timestamp float64 `cbor:"3,keyasint"`
+	Type      string  `cbor:"4,keyasint"`
+}
+
+type cborLeafCert struct {
+	AllDomains         []string       `cbor:"1,keyasint"`
+	DER                []byte         `cbor:"2,keyasint,omitempty"`
+	Extensions         cborExtensions `cbor:"3,keyasint"`
+	Fingerprint        string         `cbor:"4,keyasint"`
+	SHA1               string         `cbor:"5,keyasint"`
+	SHA256             string         `cbor:"6,keyasint"`
+	NotAfter           int64          `cbor:"7,keyasint"`
+	NotBefore          int64          `cbor:"8,keyasint"`
+	SerialNumber       string         `cbor:"9,keyasint"`
+	SignatureAlgorithm string         `cbor:"10,keyasint"`
+	Subject            cborSubject    `cbor:"11,keyasint"`
+	Issuer             cborSubject    `cbor:"12,keyasint"`
+	IsCA               bool           `cbor:"13,keyasint"`
+	Domains            []cborDomain   `cbor:"25,keyasint,omitempty"`
+
+	PublicKey              *cborPublicKey       `cbor:"14,keyasint,omitempty"`
+	IPAddresses            []string             `cbor:"15,keyasint,omitempty"`
+	EmailAddresses         []string             `cbor:"16,keyasint,omitempty"`
+	NameConstraints        *cborNameConstraints `cbor:"17,keyasint,omitempty"`
+	PolicyOIDs             []string             `cbor:"18,keyasint,omitempty"`
+	CRLDistributionPoints  []string             `cbor:"19,keyasint,omitempty"`
+	OCSPServers            []string             `cbor:"20,keyasint,omitempty"`
+	IssuingCertificateURLs []string             `cbor:"21,keyasint,omitempty"`
+	ValidationLevel        string               `cbor:"22,keyasint,omitempty"`
+	ValidityDays           int64                `cbor:"23,keyasint,omitempty"`
+	SCTs                   []cborSCT            `cbor:"24,keyasint,omitempty"`
+}
+
+type cborDomain struct {
+	Name              string `cbor:"1,keyasint"`
+	Unicode           string `cbor:"2,keyasint,omitempty"`
+	RegistrableDomain string `cbor:"3,keyasint,omitempty"`
+	Wildcard          bool   `cbor:"4,keyasint,omitempty"`
+}
+
+type cborSCT struct {
+	LogID              string  `cbor:"1,keyasint"`
+	LogName            string  `cbor:"2,keyasint,omitempty"`
+	LogOperator        string  `cbor:"3,keyasint,omitempty"`
+	Timestamp          float64 `cbor:"4,keyasint"`
+	SignatureAlgorithm string  `cbor:"5,keyasint"`
+	Verified           *bool   `cbor:"6,keyasint,omitempty"`
+}
+
+type cborPublicKey struct {
+	Algorithm  string `cbor:"1,keyasint"`
+	Size       int    `cbor:"2,keyasint,omitempty"`
+	Curve      string `cbor:"3,keyasint,omitempty"`
+	SPKISHA256 string `cbor:"4,keyasint"`
+}
+
+type cborNameConstraints struct {
+	Critical                bool     `cbor:"1,keyasint"`
+	PermittedDNSDomains     []string `cbor:"2,keyasint,omitempty"`
+	ExcludedDNSDomains      []string `cbor:"3,keyasint,omitempty"`
+	PermittedIPRanges       []string `cbor:"4,keyasint,omitempty"`
+	ExcludedIPRanges        []string `cbor:"5,keyasint,omitempty"`
+	PermittedEmailAddresses []string `cbor:"6,keyasint,omitempty"`
+	ExcludedEmailAddresses  []string `cbor:"7,keyasint,omitempty"`
+	PermittedURIDomains     []string `cbor:"8,keyasint,omitempty"`
+	ExcludedURIDomains      []string `cbor:"9,keyasint,omitempty"`
+}
+
+type cborSubject struct {
+	C            *string `cbor:"1,keyasint,omitempty"`
+	CN           *string `cbor:"2,keyasint,omitempty"`
+	L            *string `cbor:"3,keyasint,omitempty"`
+	O            *string `cbor:"4,keyasint,omitempty"`
+	OU           *string `cbor:"5,keyasint,omitempty"`
+	ST           *string `cbor:"6,keyasint,omitempty"`
+	Aggregated   *string `cbor:"7,keyasint,omitempty"`
+	EmailAddress *string `cbor:"8,keyasint,omitempty"`
+}
+
+type cborExtensions struct {
+	AuthorityInfoAccess           *string `cbor:"1,keyasint,omitempty"`
+	AuthorityKeyIdentifier        *string `cbor:"2,keyasint,omitempty"`
+	BasicConstraints              *string `cbor:"3,keyasint,omitempty"`
+	CertificatePolicies           *string `cbor:"4,keyasint,omitempty"`
+	CtlSignedCertificateTimestamp *string `cbor:"5,keyasint,omitempty"`
+	ExtendedKeyUsage              *string `cbor:"6,keyasint,omitempty"`
+	KeyUsage                      *string `cbor:"7,keyasint,omitempty"`
+	SubjectAltName                *string `cbor:"8,keyasint,omitempty"`
+	SubjectKeyIdentifier          *string `cbor:"9,keyasint,omitempty"`
+	CTLPoisonByte                 bool    `cbor:"10,keyasint,omitempty"`
+}
+
+type cborDomainsEntry struct {
+	Data        []string `cbor:"1,keyasint"`
+	MessageType string   `cbor:"2,keyasint"`
+	Seq         uint64   `cbor:"3,keyasint,omitempty"`
+}
+
+type cborGroupedDomainsEntry struct {
+	Data        []cborDomainGroup `cbor:"1,keyasint"`
+	MessageType string            `cbor:"2,keyasint"`
+	Seq         uint64            `cbor:"3,keyasint,omitempty"`
+}
+
+type cborDomainGroup struct {
+	RegistrableDomain string   `cbor:"1,keyasint"`
+	Domains           []string `cbor:"2,keyasint"`
+}
+
+type cborGapEntry struct {
+	MessageType string `cbor:"2,keyasint"`
+	Skipped     uint64 `cbor:"4,keyasint"`
+}
+
+type cborHeartbeatEntry struct {
+	MessageType string             `cbor:"2,keyasint"`
+	Timestamp   float64            `cbor:"5,keyasint"`
+	Stats       cborHeartbeatStats `cbor:"6,keyasint"`
+}
+
+type cborHeartbeatStats struct {
+	ProcessedCertificates    int64  `cbor:"1,keyasint"`
+	ProcessedPrecertificates int64  `cbor:"2,keyasint"`
+	Skipped                  uint64 `cbor:"3,keyasint"`
+}
+
+// CBOR returns the CBOR encoded Entry as byte slice and caches it for later access.
+func (e *Entry) CBOR() []byte {
+	if len(e.cachedCBOR) > 0 {
+		return e.cachedCBOR
+	}
+
+	e.cachedCBOR = e.entryToCBORBytes(false)
+
+	return e.cachedCBOR
+}
+
+// CBORLite does the same as CBOR() but removes the chain and cert's DER representation.
+func (e *Entry) CBORLite() []byte {
+	if len(e.cachedCBORLite) > 0 {
+		return e.cachedCBORLite
+	}
+
+	e.cachedCBORLite = e.entryToCBORBytes(true)
+
+	return e.cachedCBORLite
+}
+
+// CBORDomains returns the CBOR encoded domains as byte slice.
+func (e *Entry) CBORDomains() []byte {
+	return marshalCBOR(cborDomainsEntry{
+		Data:        validUTF8Strings(e.Data.LeafCert.AllDomains),
+		MessageType: "dns_entries",
+		Seq:         e.Seq,
+	})
+}
+
+// CBORDomainsGrouped returns the CBOR encoded domains grouped by registrable domain as byte slice.
+func (e *Entry) CBORDomainsGrouped() []byte {
+	groups := e.Data.LeafCert.DomainGroups()
+
+	data := make([]cborDomainGroup, len(groups))
+	for i, group := range groups {
+		data[i] = cborDomainGroup{RegistrableDomain: validUTF8(group.RegistrableDomain), Domains: validUTF8Strings(group.Domains)}
+	}
+
+	return marshalCBOR(cborGroupedDomainsEntry{Data: data, MessageType: "dns_entries_grouped", Seq: e.Seq})
+}
+
+// CBORNewDomains returns the CBOR encoded new domains as byte slice.
+func (e *Entry) CBORNewDomains() []byte {
+	return marshalCBOR(cborDomainsEntry{
+		Data:        validUTF8Strings(e.NewDomains),
+		MessageType: "new_domains",
+		Seq:         e.Seq,
+	})
+}
+
+// CBOR returns the CBOR encoded GapEntry as byte slice.
+func (g GapEntry) CBOR() []byte {
+	return marshalCBOR(cborGapEntry(g))
+}
+
+// CBOR returns the CBOR encoded HeartbeatEntry as byte slice.
+func (h HeartbeatEntry) CBOR() []byte {
+	return marshalCBOR(cborHeartbeatEntry{
+		MessageType: h.MessageType,
+		Timestamp:   h.Timestamp,
+		Stats:       cborHeartbeatStats(h.Stats),
+	})
+}
+
+// entryToCBORBytes encodes an Entry to a CBOR byte slice. If lite is set, the chain and DER data are omitted.
+func (e *Entry) entryToCBORBytes(lite bool) []byte {
+	data := cborData{
+		CertIndex: e.Data.CertIndex,
+		CertLink:  validUTF8(e.Data.CertLink),
+		LeafCert:  toCBORLeafCert(&e.Data.LeafCert, lite),
+		Seen:      e.Data.Seen,
+		Source: cborSource{
+			Name:      validUTF8(e.Data.Source.Name),
+			URL:       validUTF8(e.Data.Source.URL),
+			Timestamp: e.Data.Source.Timestamp,
+			Type:      e.Data.Source.Type,
+		},
+		UpdateType: e.Data.UpdateType,
+	}
+
+	if !lite && len(e.Data.Chain) > 0 {
+		data.Chain = make([]cborLeafCert, len(e.Data.Chain))
+		for i := range e.Data.Chain {
+			data.Chain[i] = toCBORLeafCert(&e.Data.Chain[i], false)
+		}
+	}
+
+	if e.Data.Lint != nil {
+		data.Lint = &cborLint{Findings: make([]cborLintFinding, len(e.Data.Lint.Findings))}
+		for i, finding := range e.Data.Lint.Findings {
+			data.Lint.Findings[i] = cborLintFinding{
+				Name:     finding.Name,
+				Set:      finding.Set,
+				Severity: finding.Severity,
+				Details:  validUTF8(finding.Details),
+			}
+		}
+	}
+
+	if e.Data.Score != nil {
+		data.Score = &cborScore{Value: e.Data.Score.Value, Reasons: make([]cborScoreReason, len(e.Data.Score.Reasons))}
+		for i, reason := range e.Data.Score.Reasons {
+			data.Score.Reasons[i] = cborScoreReason{
+				Rule:    reason.Rule,
+				Domain:  validUTF8(reason.Domain),
+				Details: validUTF8(reason.Details),
+				Points:  reason.Points,
+			}
+		}
+	}
+
+	return marshalCBOR(cborEntry{Data: data, MessageType: validUTF8(e.MessageType), Seq: e.Seq})
+}
+
+// toCBORLeafCert converts a LeafCert to its CBOR representation. The base64 encoded DER data is decoded to raw bytes.
+func toCBORLeafCert(lc *LeafCert, lite bool) cborLeafCert {
+	leafCert := cborLeafCert{
+		AllDomains: validUTF8Strings(lc.AllDomains),
+		Extensions: cborExtensions{
+			AuthorityInfoAccess:           validUTF8Ptr(lc.Extensions.AuthorityInfoAccess),
+			AuthorityKeyIdentifier:        validUTF8Ptr(lc.Extensions.AuthorityKeyIdentifier),
+			BasicConstraints:              validUTF8Ptr(lc.Extensions.BasicConstraints),
+			CertificatePolicies:           validUTF8Ptr(lc.Extensions.CertificatePolicies),
+			CtlSignedCertificateTimestamp: validUTF8Ptr(lc.Extensions.CtlSignedCertificateTimestamp),
+			ExtendedKeyUsage:              validUTF8Ptr(lc.Extensions.ExtendedKeyUsage),
+			KeyUsage:                      validUTF8Ptr(lc.Extensions.KeyUsage),
+			SubjectAltName:                validUTF8Ptr(lc.Extensions.SubjectAltName),
+			SubjectKeyIdentifier:          validUTF8Ptr(lc.Extensions.SubjectKeyIdentifier),
+			CTLPoisonByte:                 lc.Extensions.CTLPoisonByte,
+		},
+		Fingerprint:        lc.Fingerprint,
+		SHA1:               lc.SHA1,
+		SHA256:             lc.SHA256,
+		NotAfter:           lc.NotAfter,
+		NotBefore:          lc.NotBefore,
+		SerialNumber:       lc.SerialNumber,
+		SignatureAlgorithm: lc.SignatureAlgorithm,
+		Subject:            toCBORSubject(&lc.Subject),
+		Issuer:             toCBORSubject(&lc.Issuer),
+		IsCA:               lc.IsCA,
+	}
+
+	if len(lc.Domains) > 0 {
+		leafCert.Domains = make([]cborDomain, len(lc.Domains))
+		for i, domain := range lc.Domains {
+			leafCert.Domains[i] = cborDomain{
+				Name:              validUTF8(domain.Name),
+				Unicode:           validUTF8(domain.Unicode),
+				RegistrableDomain: validUTF8(domain.RegistrableDomain),
+				Wildcard:          domain.Wildcard,
+			}
+		}
+	}
+
+	if lite {
+		return leafCert
+	}
+
+	if lc.AsDER != "" {
+		der, err := base64.StdEncoding.DecodeString(lc.AsDER)
+		if err != nil {
+			logger.Error("Error decoding DER data for CBOR encoding", logging.KeyError, err)
+		}
+
+		leafCert.DER = der
+	}
+
+	if lc.PublicKey != nil {
+		leafCert.PublicKey = &cborPublicKey{
+			Algorithm:  lc.PublicKey.Algorithm,
+			Size:       lc.PublicKey.Size,
+			Curve:      lc.PublicKey.Curve,
+			SPKISHA256: lc.PublicKey.SPKISHA256,
+		}
+	}
+
+	if nc := lc.NameConstraints; nc != nil {
+		leafCert.NameConstraints = &cborNameConstraints{
+			Critical:                nc.Critical,
+			PermittedDNSDomains:     validUTF8Strings(nc.PermittedDNSDomains),
+			ExcludedDNSDomains:      validUTF8Strings(nc.ExcludedDNSDomains),
+			PermittedIPRanges:       nc.PermittedIPRanges,
+			ExcludedIPRanges:        nc.ExcludedIPRanges,
+			PermittedEmailAddresses: validUTF8Strings(nc.PermittedEmailAddresses),
+			ExcludedEmailAddresses:  validUTF8Strings(nc.ExcludedEmailAddresses),
+			PermittedURIDomains:     validUTF8Strings(nc.PermittedURIDomains),
+			ExcludedURIDomains:      validUTF8Strings(nc.ExcludedURIDomains),
+		}
+	}
+
+	leafCert.IPAddresses = lc.IPAddresses
+	leafCert.EmailAddresses = validUTF8Strings(lc.EmailAddresses)
+	leafCert.PolicyOIDs = lc.PolicyOIDs
+	leafCert.CRLDistributionPoints = validUTF8Strings(lc.CRLDistributionPoints)
+	leafCert.OCSPServers = validUTF8Strings(lc.OCSPServers)
+	leafCert.IssuingCertificateURLs = validUTF8Strings(lc.IssuingCertificateURLs)
+	leafCert.ValidationLevel = lc.ValidationLevel
+	leafCert.ValidityDays = lc.ValidityDays
+
+	if len(lc.SCTs) > 0 {
+		leafCert.SCTs = make([]cborSCT, len(lc.SCTs))
+		for i, sct := range lc.SCTs {
+			leafCert.SCTs[i] = cborSCT{
+				LogID:              sct.LogID,
+				LogName:            validUTF8(sct.LogName),
+				LogOperator:        validUTF8(sct.LogOperator),
+				Timestamp:          sct.Timestamp,
+				SignatureAlgorithm: sct.SignatureAlgorithm,
+				Verified:           sct.Verified,
+			}
+		}
+	}
+
+	return leafCert
+}
+
+// toCBORSubject converts a Subject to its CBOR representation.
+func toCBORSubject(subject *Subject) cborSubject {
+	return cborSubject{
+		C:            validUTF8Ptr(subject.C),
+		CN:           validUTF8Ptr(subject.CN),
+		L:            validUTF8Ptr(subject.L),
+		O:            validUTF8Ptr(subject.O),
+		OU:           validUTF8Ptr(subject.OU),
+		ST:           validUTF8Ptr(subject.ST),
+		Aggregated:   validUTF8Ptr(subject.Aggregated),
+		EmailAddress: validUTF8Ptr(subject.EmailAddress),
+	}
+}
+
+// validUTF8 replaces invalid UTF-8 sequences with the replacement character, as CBOR text strings must be valid UTF-8.
+// The JSON encoder does the same.
+func validUTF8(value string) string {
+	if utf8.ValidString(value) {
+		return value
+	}
+
+	return strings.ToValidUTF8(value, "\ufffd")
+}
+
+func validUTF8Ptr(value *string) *string {
+	if value == nil || utf8.ValidString(*value) {
+		return value
+	}
+
+	valid := validUTF8(*value)
+
+	return &valid
+}
+
+func validUTF8Strings(values []string) []string {
+	for i, value := range values {
+		if utf8.ValidString(value) {
+			continue
+		}
+
+		// Copy the slice before modifying it, since it's shared with the entry
+		valid := make([]string, len(values))
+		copy(valid, values)
+
+		for j := i; j < len(valid); j++ {
+			valid[j] = validUTF8(valid[j])
+		}
+
+		return valid
+	}
+
+	return values
+}
+
+// marshalCBOR encodes the given value to CBOR and logs errors.
+func marshalCBOR(v any) []byte {
+	data, err := cborEncMode.Marshal(v)
+	if err != nil {
+		logger.Error("Error encoding CBOR message", logging.KeyError, err)
+	}
+
+	return data
+}
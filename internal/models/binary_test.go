@@ -0,0 +1,56 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+func TestEntryCBOR(t *testing.T) {
+	der := []byte{0x30, 0x82, 0x01, 0x0a}
+
+	entry := testEntry()
+	entry.Data.LeafCert.AsDER = base64.StdEncoding.EncodeToString(der)
+
+	var decoded map[int]any
+	if err := cbor.Unmarshal(entry.CBOR(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded[2] != "certificate_update" || decoded[3] != uint64(42) {
+		t.Errorf("unexpected message type or seq: %v, %v", decoded[2], decoded[3])
+	}
+
+	data, _ := decoded[1].(map[any]any)
+	leafCert, _ := data[uint64(4)].(map[any]any)
+
+	if got, _ := leafCert[uint64(2)].([]byte); !bytes.Equal(got, der) {
+		t.Errorf("want raw DER bytes %x, got %x", der, got)
+	}
+
+	if _, ok := data[uint64(3)]; !ok {
+		t.Errorf("want chain in full CBOR entry")
+	}
+
+	var lite map[int]any
+	if err := cbor.Unmarshal(entry.CBORLite(), &lite); err != nil {
+		t.Fatal(err)
+	}
+
+	liteData, _ := lite[1].(map[any]any)
+	liteLeafCert, _ := liteData[uint64(4)].(map[any]any)
+
+	if _, ok := liteData[uint64(3)]; ok {
+		t.Errorf("want no chain in lite CBOR entry")
+	}
+
+	if _, ok := liteLeafCert[uint64(2)]; ok {
+		t.Errorf("want no DER in lite CBOR entry")
+	}
+
+	if len(entry.CBOR()) >= len(entry.JSON()) {
+		t.Errorf("want CBOR (%d bytes) to be smaller than JSON (%d bytes)", len(entry.CBOR()), len(entry.JSON()))
+	}
+}
@@ -1,16 +1,18 @@
 package models
 
-import (
-	"bytes"
-	"encoding/json"
-	"log"
-)
-
+// Entry is a single certificate update as sent to the clients.
+// Seq is a server-wide, monotonically increasing sequence number which clients can use to resume the stream after reconnecting.
 type Entry struct {
-	Data           Data   `json:"data"`
-	MessageType    string `json:"message_type"`
+	Data        Data   `json:"data"`
+	MessageType string `json:"message_type"`
+	Seq         uint64 `json:"seq,omitempty"`
+	// NewDomains are the domains of the certificate that weren't seen within the configured window. They are only
+	// sent on the new domains stream and are nil if the new domains stream is disabled.
+	NewDomains     []string `json:"-"`
 	cachedJSON     []byte
 	cachedJSONLite []byte
+	cachedCBOR     []byte
+	cachedCBORLite []byte
 }
 
 // Clone returns a new copy of the Entry.
@@ -18,8 +20,12 @@ func (e *Entry) Clone() Entry {
 	return Entry{
 		Data:           e.Data,
 		MessageType:    e.MessageType,
+		Seq:            e.Seq,
+		NewDomains:     e.NewDomains,
 		cachedJSON:     e.cachedJSON,
 		cachedJSONLite: e.cachedJSONLite,
+		cachedCBOR:     e.cachedCBOR,
+		cachedCBORLite: e.cachedCBORLite,
 	}
 }
 
@@ -51,12 +57,9 @@ func (e *Entry) JSONLite() []byte {
 }
 
 // JSONLiteNoCache does the same as JSONNoCache() but removes the chain and cert's DER representation.
+// The lite form is written directly by the encoder, so the entry doesn't need to be copied.
 func (e *Entry) JSONLiteNoCache() []byte {
-	newEntry := e.Clone()
-	newEntry.Data.Chain = nil
-	newEntry.Data.LeafCert.AsDER = ""
-
-	return newEntry.entryToJSONBytes()
+	return encodeWithPool(func(buf []byte) []byte { return appendEntry(buf, e, true) })
 }
 
 // JSONDomains returns the JSON encoded domains (DomainsEntry) as byte slice.
@@ -64,28 +67,37 @@ func (e *Entry) JSONDomains() []byte {
 	domainsEntry := DomainsEntry{
 		Data:        e.Data.LeafCert.AllDomains,
 		MessageType: "dns_entries",
+		Seq:         e.Seq,
 	}
 
-	domainsEntryBytes, err := json.Marshal(domainsEntry)
-	if err != nil {
-		log.Println(err)
+	return encodeWithPool(func(buf []byte) []byte { return appendDomainsEntry(buf, &domainsEntry) })
+}
+
+// JSONDomainsGrouped returns the JSON encoded domains grouped by registrable domain (GroupedDomainsEntry) as byte slice.
+func (e *Entry) JSONDomainsGrouped() []byte {
+	groupedEntry := GroupedDomainsEntry{
+		Data:        e.Data.LeafCert.DomainGroups(),
+		MessageType: "dns_entries_grouped",
+		Seq:         e.Seq,
 	}
 
-	return domainsEntryBytes
+	return encodeWithPool(func(buf []byte) []byte { return appendGroupedDomainsEntry(buf, &groupedEntry) })
 }
 
-// entryToJSONBytes encodes an Entry to a JSON byte slice.
-func (e *Entry) entryToJSONBytes() []byte {
-	buf := bytes.Buffer{}
-	enc := json.NewEncoder(&buf)
-	enc.SetEscapeHTML(false)
-
-	err := enc.Encode(e)
-	if err != nil {
-		log.Println(err)
+// JSONNewDomains returns the JSON encoded new domains (DomainsEntry) as byte slice.
+func (e *Entry) JSONNewDomains() []byte {
+	domainsEntry := DomainsEntry{
+		Data:        e.NewDomains,
+		MessageType: "new_domains",
+		Seq:         e.Seq,
 	}
 
-	return buf.Bytes()
+	return encodeWithPool(func(buf []byte) []byte { return appendDomainsEntry(buf, &domainsEntry) })
+}
+
+// entryToJSONBytes encodes an Entry to a JSON byte slice.
+func (e *Entry) entryToJSONBytes() []byte {
+	return encodeWithPool(func(buf []byte) []byte { return appendEntry(buf, e, false) })
 }
 
 type Data struct {
@@ -96,6 +108,45 @@ type Data struct {
 	Seen       float64    `json:"seen"`
 	Source     Source     `json:"source"`
 	UpdateType string     `json:"update_type"`
+	// Lint contains the results of the certificate linter. It's nil if linting is disabled.
+	Lint *Lint `json:"lint,omitempty"`
+	// Score rates how suspicious the domains of the certificate are. It's nil if scoring is disabled.
+	Score *Score `json:"score,omitempty"`
+}
+
+// Lint lists the checks the leaf certificate failed. An empty list means that the certificate passed all checks.
+type Lint struct {
+	Findings []LintFinding `json:"findings"`
+}
+
+// LintFinding is a single failed check of the certificate linter.
+type LintFinding struct {
+	// Name is the name of the check, e.g. "e_cab_validity_too_long". The prefix indicates the severity.
+	Name string `json:"name"`
+	// Set is the lint set the check belongs to, e.g. "cabf_br".
+	Set string `json:"set"`
+	// Severity is "notice", "warn" or "error".
+	Severity string `json:"severity"`
+	// Details describes why the check failed, e.g. the offending domain.
+	Details string `json:"details,omitempty"`
+}
+
+// Score is the phishing score of the certificate, which is the highest score of all its domains.
+type Score struct {
+	Value int `json:"value"`
+	// Reasons lists the rules that matched any of the domains.
+	Reasons []ScoreReason `json:"reasons"`
+}
+
+// ScoreReason is a single rule that matched a domain of the certificate.
+type ScoreReason struct {
+	// Rule is the name of the rule, e.g. "keyword" or "homoglyph".
+	Rule string `json:"rule"`
+	// Domain is the domain that matched the rule.
+	Domain string `json:"domain"`
+	// Details describes the match, e.g. the keyword or the imitated brand.
+	Details string `json:"details,omitempty"`
+	Points  int    `json:"points"`
 }
 
 const (
@@ -126,6 +177,65 @@ type LeafCert struct {
 	Subject            Subject    `json:"subject"`
 	Issuer             Subject    `json:"issuer"`
 	IsCA               bool       `json:"is_ca"`
+
+	// Domains are the normalized, de-duplicated domains of AllDomains. They are sent on the lite stream as well.
+	Domains []Domain `json:"domains,omitempty"`
+
+	// The following fields are only sent on the full stream. They are appended after the fields known from the
+	// original certstream server and omitted if empty, so existing clients are not affected.
+	PublicKey              *PublicKey       `json:"public_key,omitempty"`
+	IPAddresses            []string         `json:"ip_addresses,omitempty"`
+	EmailAddresses         []string         `json:"email_addresses,omitempty"`
+	NameConstraints        *NameConstraints `json:"name_constraints,omitempty"`
+	PolicyOIDs             []string         `json:"policy_oids,omitempty"`
+	CRLDistributionPoints  []string         `json:"crl_distribution_points,omitempty"`
+	OCSPServers            []string         `json:"ocsp_urls,omitempty"`
+	IssuingCertificateURLs []string         `json:"ca_issuer_urls,omitempty"`
+	// ValidationLevel is "DV", "OV", "IV" or "EV" as indicated by the CA/Browser Forum policy OIDs, if present.
+	ValidationLevel string `json:"validation_level,omitempty"`
+	// ValidityDays is the validity period of the certificate in days, rounded up.
+	ValidityDays int64 `json:"validity_days,omitempty"`
+	// SCTs are the signed certificate timestamps embedded in the certificate.
+	SCTs []SCT `json:"scts,omitempty"`
+}
+
+// SCT is a signed certificate timestamp, the promise of a CT log to include the certificate.
+type SCT struct {
+	// LogID is the base64 encoded ID of the log, as found in the log list.
+	LogID string `json:"log_id"`
+	// LogName and LogOperator are only set if the log is listed in the log list.
+	LogName     string `json:"log_name,omitempty"`
+	LogOperator string `json:"log_operator,omitempty"`
+	// Timestamp is the time at which the log issued the SCT.
+	Timestamp          float64 `json:"timestamp"`
+	SignatureAlgorithm string  `json:"signature_algorithm"`
+	// Verified is the result of the signature verification. It's nil if the signature was not verified.
+	Verified *bool `json:"verified,omitempty"`
+}
+
+// PublicKey describes the subject public key of a certificate.
+type PublicKey struct {
+	// Algorithm is the public key algorithm, e.g. "rsa", "ecdsa" or "ed25519".
+	Algorithm string `json:"algorithm"`
+	// Size is the key size in bits.
+	Size int `json:"size,omitempty"`
+	// Curve is the name of the elliptic curve for ECDSA keys, e.g. "P-256".
+	Curve string `json:"curve,omitempty"`
+	// SPKISHA256 is the base64 encoded SHA256 hash of the DER encoded SubjectPublicKeyInfo as used for key pinning.
+	SPKISHA256 string `json:"spki_sha256"`
+}
+
+// NameConstraints contains the permitted and excluded subtrees of a CA certificate's name constraints extension.
+type NameConstraints struct {
+	Critical                bool     `json:"critical"`
+	PermittedDNSDomains     []string `json:"permitted_dns_domains,omitempty"`
+	ExcludedDNSDomains      []string `json:"excluded_dns_domains,omitempty"`
+	PermittedIPRanges       []string `json:"permitted_ip_ranges,omitempty"`
+	ExcludedIPRanges        []string `json:"excluded_ip_ranges,omitempty"`
+	PermittedEmailAddresses []string `json:"permitted_email_addresses,omitempty"`
+	ExcludedEmailAddresses  []string `json:"excluded_email_addresses,omitempty"`
+	PermittedURIDomains     []string `json:"permitted_uri_domains,omitempty"`
+	ExcludedURIDomains      []string `json:"excluded_uri_domains,omitempty"`
 }
 
 type Subject struct {
@@ -152,7 +262,85 @@ type Extensions struct {
 	CTLPoisonByte                 bool    `json:"ctlPoisonByte,omitempty"`
 }
 
+// GapEntry notifies a client that entries were dropped because the client couldn't keep up.
+type GapEntry struct {
+	MessageType string `json:"message_type"`
+	Skipped     uint64 `json:"skipped"`
+}
+
+// HeartbeatEntry is sent periodically to clients, so they can detect dead streams. It's compatible with the heartbeat
+// of the original certstream server and additionally contains statistics about the server and the client.
+type HeartbeatEntry struct {
+	MessageType string         `json:"message_type"`
+	Timestamp   float64        `json:"timestamp"`
+	Stats       HeartbeatStats `json:"stats"`
+}
+
+type HeartbeatStats struct {
+	// ProcessedCertificates is the number of certificates processed by the server since it was started.
+	ProcessedCertificates int64 `json:"processed_certificates"`
+	// ProcessedPrecertificates is the number of precertificates processed by the server since it was started.
+	ProcessedPrecertificates int64 `json:"processed_precertificates"`
+	// Skipped is the number of entries the client missed because it couldn't keep up.
+	Skipped uint64 `json:"skipped"`
+}
+
+// Domain is a normalized domain of a certificate.
+type Domain struct {
+	// Name is the lower case domain without the wildcard prefix.
+	Name string `json:"name"`
+	// Unicode is the domain with all punycode labels decoded. It's only set for internationalized domains.
+	Unicode string `json:"unicode,omitempty"`
+	// RegistrableDomain is the public suffix plus one label (eTLD+1), e.g. "example.co.uk".
+	// It's empty for IP addresses and names that aren't valid domains.
+	RegistrableDomain string `json:"registrable_domain,omitempty"`
+	Wildcard          bool   `json:"wildcard,omitempty"`
+}
+
+// String returns the domain as it appeared in the certificate, including the wildcard prefix, but in lower case.
+func (d Domain) String() string {
+	if d.Wildcard {
+		return "*." + d.Name
+	}
+
+	return d.Name
+}
+
+// DomainGroup contains the domains of a certificate that belong to the same registrable domain.
+type DomainGroup struct {
+	RegistrableDomain string   `json:"registrable_domain"`
+	Domains           []string `json:"domains"`
+}
+
+// GroupedDomainsEntry is the message of the domains-only stream grouped by registrable domain.
+type GroupedDomainsEntry struct {
+	Data        []DomainGroup `json:"data"`
+	MessageType string        `json:"message_type"`
+	Seq         uint64        `json:"seq,omitempty"`
+}
+
+// DomainGroups groups the normalized domains of the leaf certificate by registrable domain, in the order of their
+// first occurrence. Domains without a registrable domain are grouped under an empty registrable domain.
+func (lc *LeafCert) DomainGroups() []DomainGroup {
+	groups := make([]DomainGroup, 0, len(lc.Domains))
+	index := make(map[string]int, len(lc.Domains))
+
+	for _, domain := range lc.Domains {
+		i, ok := index[domain.RegistrableDomain]
+		if !ok {
+			i = len(groups)
+			index[domain.RegistrableDomain] = i
+			groups = append(groups, DomainGroup{RegistrableDomain: domain.RegistrableDomain})
+		}
+
+		groups[i].Domains = append(groups[i].Domains, domain.String())
+	}
+
+	return groups
+}
+
 type DomainsEntry struct {
 	Data        []string `json:"data"`
 	MessageType string   `json:"message_type"`
+	Seq         uint64   `json:"seq,omitempty"`
 }
@@ -0,0 +1,595 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// The encoder in this file writes entries as JSON without using reflection. Its output is identical to the output
+// of encoding/json, which was used previously, so clients don't notice any difference. Since an entry is encoded
+// for every certificate, buffers are taken from a pool to avoid growing a new buffer each time.
+
+const hexDigits = "0123456789abcdef"
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 8192)
+		return &buf
+	},
+}
+
+// encodeWithPool calls encode with a pooled buffer and returns a copy of the result.
+// The copy is necessary because the encoded entries are cached and shared between clients.
+func encodeWithPool(encode func(buf []byte) []byte) []byte {
+	bufPtr, _ := bufferPool.Get().(*[]byte)
+
+	buf := encode((*bufPtr)[:0])
+	result := make([]byte, len(buf))
+	copy(result, buf)
+
+	// Don't keep huge buffers (e.g. from entries with a large chain) in the pool
+	if cap(buf) <= 1<<20 {
+		*bufPtr = buf
+		bufferPool.Put(bufPtr)
+	}
+
+	return result
+}
+
+// appendEntry appends the JSON representation of the entry followed by a newline, as written by json.Encoder.
+// If lite is set, the chain and the leaf certificate's DER representation are omitted.
+func appendEntry(buf []byte, e *Entry, lite bool) []byte {
+	buf = append(buf, `{"data":`...)
+	buf = appendData(buf, &e.Data, lite)
+	buf = append(buf, `,"message_type":`...)
+	buf = appendString(buf, e.MessageType, false)
+
+	if e.Seq != 0 {
+		buf = append(buf, `,"seq":`...)
+		buf = strconv.AppendUint(buf, e.Seq, 10)
+	}
+
+	return append(buf, '}', '\n')
+}
+
+func appendData(buf []byte, d *Data, lite bool) []byte {
+	buf = append(buf, `{"cert_index":`...)
+	buf = strconv.AppendUint(buf, d.CertIndex, 10)
+	buf = append(buf, `,"cert_link":`...)
+	buf = appendString(buf, d.CertLink, false)
+
+	if !lite && len(d.Chain) > 0 {
+		buf = append(buf, `,"chain":[`...)
+
+		for i := range d.Chain {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+
+			buf = appendLeafCert(buf, &d.Chain[i], false)
+		}
+
+		buf = append(buf, ']')
+	}
+
+	buf = append(buf, `,"leaf_cert":`...)
+	buf = appendLeafCert(buf, &d.LeafCert, lite)
+	buf = append(buf, `,"seen":`...)
+	buf = appendFloat(buf, d.Seen)
+	buf = append(buf, `,"source":{"name":`...)
+	buf = appendString(buf, d.Source.Name, false)
+	buf = append(buf, `,"url":`...)
+	buf = appendString(buf, d.Source.URL, false)
+	buf = append(buf, `,"timestamp":`...)
+	buf = appendFloat(buf, d.Source.Timestamp)
+	buf = append(buf, `,"type":`...)
+	buf = appendString(buf, d.Source.Type, false)
+	buf = append(buf, `},"update_type":`...)
+	buf = appendString(buf, d.UpdateType, false)
+
+	if d.Lint != nil {
+		buf = append(buf, `,"lint":`...)
+		buf = appendLint(buf, d.Lint)
+	}
+
+	if d.Score != nil {
+		buf = append(buf, `,"score":`...)
+		buf = appendScore(buf, d.Score)
+	}
+
+	return append(buf, '}')
+}
+
+func appendLint(buf []byte, lint *Lint) []byte {
+	buf = append(buf, `{"findings":`...)
+
+	if lint.Findings == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+
+		for i, finding := range lint.Findings {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+
+			buf = append(buf, `{"name":`...)
+			buf = appendString(buf, finding.Name, false)
+			buf = append(buf, `,"set":`...)
+			buf = appendString(buf, finding.Set, false)
+			buf = append(buf, `,"severity":`...)
+			buf = appendString(buf, finding.Severity, false)
+
+			if finding.Details != "" {
+				buf = append(buf, `,"details":`...)
+				buf = appendString(buf, finding.Details, false)
+			}
+
+			buf = append(buf, '}')
+		}
+
+		buf = append(buf, ']')
+	}
+
+	return append(buf, '}')
+}
+
+func appendScore(buf []byte, score *Score) []byte {
+	buf = append(buf, `{"value":`...)
+	buf = strconv.AppendInt(buf, int64(score.Value), 10)
+	buf = append(buf, `,"reasons":`...)
+
+	if score.Reasons == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+
+		for i, reason := range score.Reasons {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+
+			buf = append(buf, `{"rule":`...)
+			buf = appendString(buf, reason.Rule, false)
+			buf = append(buf, `,"domain":`...)
+			buf = appendString(buf, reason.Domain, false)
+
+			if reason.Details != "" {
+				buf = append(buf, `,"details":`...)
+				buf = appendString(buf, reason.Details, false)
+			}
+
+			buf = append(buf, `,"points":`...)
+			buf = strconv.AppendInt(buf, int64(reason.Points), 10)
+			buf = append(buf, '}')
+		}
+
+		buf = append(buf, ']')
+	}
+
+	return append(buf, '}')
+}
+
+func appendLeafCert(buf []byte, lc *LeafCert, lite bool) []byte {
+	buf = append(buf, `{"all_domains":`...)
+	buf = appendStrings(buf, lc.AllDomains, false)
+
+	if !lite && lc.AsDER != "" {
+		buf = append(buf, `,"as_der":`...)
+		buf = appendString(buf, lc.AsDER, false)
+	}
+
+	buf = append(buf, `,"extensions":`...)
+	buf = appendExtensions(buf, &lc.Extensions)
+	buf = append(buf, `,"fingerprint":`...)
+	buf = appendString(buf, lc.Fingerprint, false)
+	buf = append(buf, `,"sha1":`...)
+	buf = appendString(buf, lc.SHA1, false)
+	buf = append(buf, `,"sha256":`...)
+	buf = appendString(buf, lc.SHA256, false)
+	buf = append(buf, `,"not_after":`...)
+	buf = strconv.AppendInt(buf, lc.NotAfter, 10)
+	buf = append(buf, `,"not_before":`...)
+	buf = strconv.AppendInt(buf, lc.NotBefore, 10)
+	buf = append(buf, `,"serial_number":`...)
+	buf = appendString(buf, lc.SerialNumber, false)
+	buf = append(buf, `,"signature_algorithm":`...)
+	buf = appendString(buf, lc.SignatureAlgorithm, false)
+	buf = append(buf, `,"subject":`...)
+	buf = appendSubject(buf, &lc.Subject)
+	buf = append(buf, `,"issuer":`...)
+	buf = appendSubject(buf, &lc.Issuer)
+	buf = append(buf, `,"is_ca":`...)
+	buf = strconv.AppendBool(buf, lc.IsCA)
+
+	if len(lc.Domains) > 0 {
+		buf = append(buf, `,"domains":[`...)
+
+		for i := range lc.Domains {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+
+			buf = appendDomain(buf, &lc.Domains[i])
+		}
+
+		buf = append(buf, ']')
+	}
+
+	if !lite {
+		buf = appendLeafCertDetails(buf, lc)
+	}
+
+	return append(buf, '}')
+}
+
+func appendDomain(buf []byte, d *Domain) []byte {
+	buf = append(buf, `{"name":`...)
+	buf = appendString(buf, d.Name, false)
+
+	if d.Unicode != "" {
+		buf = append(buf, `,"unicode":`...)
+		buf = appendString(buf, d.Unicode, false)
+	}
+
+	if d.RegistrableDomain != "" {
+		buf = append(buf, `,"registrable_domain":`...)
+		buf = appendString(buf, d.RegistrableDomain, false)
+	}
+
+	if d.Wildcard {
+		buf = append(buf, `,"wildcard":true`...)
+	}
+
+	return append(buf, '}')
+}
+
+// appendLeafCertDetails appends the optional fields of the leaf certificate, which are only sent on the full stream.
+func appendLeafCertDetails(buf []byte, lc *LeafCert) []byte {
+	if lc.PublicKey != nil {
+		buf = append(buf, `,"public_key":{"algorithm":`...)
+		buf = appendString(buf, lc.PublicKey.Algorithm, false)
+
+		if lc.PublicKey.Size != 0 {
+			buf = append(buf, `,"size":`...)
+			buf = strconv.AppendInt(buf, int64(lc.PublicKey.Size), 10)
+		}
+
+		if lc.PublicKey.Curve != "" {
+			buf = append(buf, `,"curve":`...)
+			buf = appendString(buf, lc.PublicKey.Curve, false)
+		}
+
+		buf = append(buf, `,"spki_sha256":`...)
+		buf = appendString(buf, lc.PublicKey.SPKISHA256, false)
+		buf = append(buf, '}')
+	}
+
+	buf = appendOptionalStrings(buf, "ip_addresses", lc.IPAddresses)
+	buf = appendOptionalStrings(buf, "email_addresses", lc.EmailAddresses)
+
+	if nc := lc.NameConstraints; nc != nil {
+		buf = append(buf, `,"name_constraints":{"critical":`...)
+		buf = strconv.AppendBool(buf, nc.Critical)
+		buf = appendOptionalStrings(buf, "permitted_dns_domains", nc.PermittedDNSDomains)
+		buf = appendOptionalStrings(buf, "excluded_dns_domains", nc.ExcludedDNSDomains)
+		buf = appendOptionalStrings(buf, "permitted_ip_ranges", nc.PermittedIPRanges)
+		buf = appendOptionalStrings(buf, "excluded_ip_ranges", nc.ExcludedIPRanges)
+		buf = appendOptionalStrings(buf, "permitted_email_addresses", nc.PermittedEmailAddresses)
+		buf = appendOptionalStrings(buf, "excluded_email_addresses", nc.ExcludedEmailAddresses)
+		buf = appendOptionalStrings(buf, "permitted_uri_domains", nc.PermittedURIDomains)
+		buf = appendOptionalStrings(buf, "excluded_uri_domains", nc.ExcludedURIDomains)
+		buf = append(buf, '}')
+	}
+
+	buf = appendOptionalStrings(buf, "policy_oids", lc.PolicyOIDs)
+	buf = appendOptionalStrings(buf, "crl_distribution_points", lc.CRLDistributionPoints)
+	buf = appendOptionalStrings(buf, "ocsp_urls", lc.OCSPServers)
+	buf = appendOptionalStrings(buf, "ca_issuer_urls", lc.IssuingCertificateURLs)
+
+	if lc.ValidationLevel != "" {
+		buf = append(buf, `,"validation_level":`...)
+		buf = appendString(buf, lc.ValidationLevel, false)
+	}
+
+	if lc.ValidityDays != 0 {
+		buf = append(buf, `,"validity_days":`...)
+		buf = strconv.AppendInt(buf, lc.ValidityDays, 10)
+	}
+
+	if len(lc.SCTs) > 0 {
+		buf = append(buf, `,"scts":[`...)
+
+		for i := range lc.SCTs {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+
+			buf = appendSCT(buf, &lc.SCTs[i])
+		}
+
+		buf = append(buf, ']')
+	}
+
+	return buf
+}
+
+func appendSCT(buf []byte, sct *SCT) []byte {
+	buf = append(buf, `{"log_id":`...)
+	buf = appendString(buf, sct.LogID, false)
+
+	if sct.LogName != "" {
+		buf = append(buf, `,"log_name":`...)
+		buf = appendString(buf, sct.LogName, false)
+	}
+
+	if sct.LogOperator != "" {
+		buf = append(buf, `,"log_operator":`...)
+		buf = appendString(buf, sct.LogOperator, false)
+	}
+
+	buf = append(buf, `,"timestamp":`...)
+	buf = appendFloat(buf, sct.Timestamp)
+	buf = append(buf, `,"signature_algorithm":`...)
+	buf = appendString(buf, sct.SignatureAlgorithm, false)
+
+	if sct.Verified != nil {
+		buf = append(buf, `,"verified":`...)
+		buf = strconv.AppendBool(buf, *sct.Verified)
+	}
+
+	return append(buf, '}')
+}
+
+func appendSubject(buf []byte, s *Subject) []byte {
+	buf = append(buf, `{"C":`...)
+	buf = appendStringPtr(buf, s.C)
+	buf = append(buf, `,"CN":`...)
+	buf = appendStringPtr(buf, s.CN)
+	buf = append(buf, `,"L":`...)
+	buf = appendStringPtr(buf, s.L)
+	buf = append(buf, `,"O":`...)
+	buf = appendStringPtr(buf, s.O)
+	buf = append(buf, `,"OU":`...)
+	buf = appendStringPtr(buf, s.OU)
+	buf = append(buf, `,"ST":`...)
+	buf = appendStringPtr(buf, s.ST)
+	buf = append(buf, `,"aggregated":`...)
+	buf = appendStringPtr(buf, s.Aggregated)
+	buf = append(buf, `,"email_address":`...)
+	buf = appendStringPtr(buf, s.EmailAddress)
+
+	return append(buf, '}')
+}
+
+func appendExtensions(buf []byte, ext *Extensions) []byte {
+	buf = append(buf, '{')
+
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"authorityInfoAccess", ext.AuthorityInfoAccess},
+		{"authorityKeyIdentifier", ext.AuthorityKeyIdentifier},
+		{"basicConstraints", ext.BasicConstraints},
+		{"certificatePolicies", ext.CertificatePolicies},
+		{"ctlSignedCertificateTimestamp", ext.CtlSignedCertificateTimestamp},
+		{"extendedKeyUsage", ext.ExtendedKeyUsage},
+		{"keyUsage", ext.KeyUsage},
+		{"subjectAltName", ext.SubjectAltName},
+		{"subjectKeyIdentifier", ext.SubjectKeyIdentifier},
+	}
+
+	first := true
+
+	for _, field := range fields {
+		if field.value == nil {
+			continue
+		}
+
+		if !first {
+			buf = append(buf, ',')
+		}
+
+		first = false
+		buf = append(buf, '"')
+		buf = append(buf, field.name...)
+		buf = append(buf, '"', ':')
+		buf = appendString(buf, *field.value, false)
+	}
+
+	if ext.CTLPoisonByte {
+		if !first {
+			buf = append(buf, ',')
+		}
+
+		buf = append(buf, `"ctlPoisonByte":true`...)
+	}
+
+	return append(buf, '}')
+}
+
+// appendDomainsEntry appends the JSON representation of a DomainsEntry as written by json.Marshal.
+func appendDomainsEntry(buf []byte, de *DomainsEntry) []byte {
+	buf = append(buf, `{"data":`...)
+	buf = appendStrings(buf, de.Data, true)
+	buf = append(buf, `,"message_type":`...)
+	buf = appendString(buf, de.MessageType, true)
+
+	if de.Seq != 0 {
+		buf = append(buf, `,"seq":`...)
+		buf = strconv.AppendUint(buf, de.Seq, 10)
+	}
+
+	return append(buf, '}')
+}
+
+// appendGroupedDomainsEntry appends the JSON representation of a GroupedDomainsEntry as written by json.Marshal.
+func appendGroupedDomainsEntry(buf []byte, ge *GroupedDomainsEntry) []byte {
+	buf = append(buf, `{"data":`...)
+
+	if ge.Data == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+
+		for i, group := range ge.Data {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+
+			buf = append(buf, `{"registrable_domain":`...)
+			buf = appendString(buf, group.RegistrableDomain, true)
+			buf = append(buf, `,"domains":`...)
+			buf = appendStrings(buf, group.Domains, true)
+			buf = append(buf, '}')
+		}
+
+		buf = append(buf, ']')
+	}
+
+	buf = append(buf, `,"message_type":`...)
+	buf = appendString(buf, ge.MessageType, true)
+
+	if ge.Seq != 0 {
+		buf = append(buf, `,"seq":`...)
+		buf = strconv.AppendUint(buf, ge.Seq, 10)
+	}
+
+	return append(buf, '}')
+}
+
+// appendStrings appends a list of strings. Like encoding/json, a nil slice is encoded as null.
+func appendStrings(buf []byte, values []string, escapeHTML bool) []byte {
+	if values == nil {
+		return append(buf, "null"...)
+	}
+
+	buf = append(buf, '[')
+
+	for i, value := range values {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		buf = appendString(buf, value, escapeHTML)
+	}
+
+	return append(buf, ']')
+}
+
+// appendOptionalStrings appends a list of strings as a field of an object which is not the first field.
+// Like the omitempty option of encoding/json, the field is left out if the list is empty.
+func appendOptionalStrings(buf []byte, name string, values []string) []byte {
+	if len(values) == 0 {
+		return buf
+	}
+
+	buf = append(buf, ',', '"')
+	buf = append(buf, name...)
+	buf = append(buf, '"', ':')
+
+	return appendStrings(buf, values, false)
+}
+
+func appendStringPtr(buf []byte, value *string) []byte {
+	if value == nil {
+		return append(buf, "null"...)
+	}
+
+	return appendString(buf, *value, false)
+}
+
+// appendString appends a quoted and escaped JSON string the same way encoding/json does.
+func appendString(buf []byte, value string, escapeHTML bool) []byte {
+	buf = append(buf, '"')
+	start := 0
+
+	for i := 0; i < len(value); {
+		if b := value[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && (!escapeHTML || (b != '<' && b != '>' && b != '&')) {
+				i++
+				continue
+			}
+
+			buf = append(buf, value[start:i]...)
+
+			switch b {
+			case '\\', '"':
+				buf = append(buf, '\\', b)
+			case '\b':
+				buf = append(buf, '\\', 'b')
+			case '\f':
+				buf = append(buf, '\\', 'f')
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+			}
+
+			i++
+			start = i
+
+			continue
+		}
+
+		// Invalid UTF-8 is replaced with the replacement character
+		r, size := utf8.DecodeRuneInString(value[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, value[start:i]...)
+			buf = append(buf, "\ufffd"...)
+			i += size
+			start = i
+
+			continue
+		}
+
+		// U+2028 and U+2029 are escaped by encoding/json, since they break JSONP
+		if r == '\u2028' || r == '\u2029' {
+			buf = append(buf, value[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += size
+			start = i
+
+			continue
+		}
+
+		i += size
+	}
+
+	buf = append(buf, value[start:]...)
+
+	return append(buf, '"')
+}
+
+// appendFloat appends a float64 the same way encoding/json does. NaN and infinity can't be represented in JSON,
+// so they are encoded as 0.
+func appendFloat(buf []byte, f float64) []byte {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return append(buf, '0')
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+
+	if format == 'e' {
+		// Clean up e-09 to e-9
+		n := len(buf)
+		if n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+
+	return buf
+}
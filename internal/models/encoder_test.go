@@ -0,0 +1,205 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// referenceJSON encodes the value with encoding/json the way the entries were encoded before.
+func referenceJSON(t *testing.T, v any) []byte {
+	t.Helper()
+
+	buf := bytes.Buffer{}
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func testEntry() Entry {
+	str := func(s string) *string { return &s }
+	verified := true
+
+	leaf := LeafCert{
+		AllDomains: []string{"example.com", "*.example.com", "xn--bcher-kva.example", "<script>&"},
+		AsDER:      "MIIB...",
+		Domains: []Domain{
+			{Name: "example.com", RegistrableDomain: "example.com"},
+			{Name: "example.com", RegistrableDomain: "example.com", Wildcard: true},
+			{Name: "xn--bcher-kva.example", Unicode: "bücher.example", RegistrableDomain: "xn--bcher-kva.example"},
+			{Name: "<script>&"},
+		},
+		Extensions: Extensions{
+			BasicConstraints: str("CA:FALSE"),
+			SubjectAltName:   str("DNS:example.com, DNS:*.example.com"),
+			CTLPoisonByte:    true,
+		},
+		Fingerprint:        "AB:CD",
+		NotAfter:           1700000000,
+		NotBefore:          -5,
+		SerialNumber:       "0A",
+		SignatureAlgorithm: "sha256, rsa",
+		Subject: Subject{
+			CN:         str("quote \" backslash \\ newline \n tab \t ctrl \x01 invalid \xff sep   ü"),
+			Aggregated: str("/CN=example.com"),
+		},
+		Issuer: Subject{O: str("Let's Encrypt")},
+		PublicKey: &PublicKey{
+			Algorithm:  "ecdsa",
+			Size:       256,
+			Curve:      "P-256",
+			SPKISHA256: "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+		},
+		IPAddresses:    []string{"192.0.2.1", "2001:db8::1"},
+		EmailAddresses: []string{"admin@example.com"},
+		NameConstraints: &NameConstraints{
+			Critical:            true,
+			PermittedDNSDomains: []string{".example.com"},
+			ExcludedIPRanges:    []string{"10.0.0.0/8"},
+		},
+		PolicyOIDs:             []string{"2.23.140.1.2.1"},
+		CRLDistributionPoints:  []string{"http://crl.example.com/ca.crl"},
+		OCSPServers:            []string{"http://ocsp.example.com"},
+		IssuingCertificateURLs: []string{"http://ca.example.com/ca.der"},
+		ValidationLevel:        "DV",
+		ValidityDays:           90,
+		SCTs: []SCT{
+			{LogID: "KXm+8J45OSHwVnOfY6V35b5XfZxgCvj5TV0mXCVdx4Q=", LogName: "Test log", Timestamp: 1700000000.123, SignatureAlgorithm: "sha256, ecdsa", Verified: &verified},
+			{LogID: "unknown", Timestamp: 1700000000, SignatureAlgorithm: "sha256, rsa"},
+		},
+	}
+
+	return Entry{
+		Data: Data{
+			CertIndex:  123456789,
+			CertLink:   "https://ct.example.com/ct/v1/get-entries?start=1&end=1",
+			Chain:      []LeafCert{{AllDomains: []string{}, IsCA: true}},
+			LeafCert:   leaf,
+			Seen:       1700000000.123456,
+			Source:     Source{Name: "Test log", URL: "https://ct.example.com/", Timestamp: 1e-7, Operator: "hidden", Type: SourceIsRFC6962},
+			UpdateType: "X509LogEntry",
+			Lint: &Lint{Findings: []LintFinding{
+				{Name: "e_cab_validity_too_long", Set: "cabf_br", Severity: "error", Details: "validity of 398 days exceeds 200 days"},
+				{Name: "w_rfc_aki_missing", Set: "rfc5280", Severity: "warn"},
+			}},
+			Score: &Score{Value: 80, Reasons: []ScoreReason{
+				{Rule: "suspicious_tld", Domain: "paypal.com.example.xyz", Details: "xyz", Points: 20},
+				{Rule: "brand", Domain: "paypal.com.example.xyz", Details: "paypal", Points: 60},
+			}},
+		},
+		MessageType: "certificate_update",
+		Seq:         42,
+	}
+}
+
+func TestEncoder_MatchesEncodingJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+	}{
+		{"full entry", testEntry()},
+		{"empty entry", Entry{}},
+		{"no findings", Entry{Data: Data{Lint: &Lint{Findings: []LintFinding{}}}}},
+		{"no score", Entry{Data: Data{Score: &Score{Reasons: []ScoreReason{}}}}},
+		{"large floats", Entry{Data: Data{Seen: 1e21, Source: Source{Timestamp: -3.5}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := tt.entry
+
+			if got, want := entry.JSONNoCache(), referenceJSON(t, &entry); !bytes.Equal(got, want) {
+				t.Errorf("full JSON differs:\ngot:  %s\nwant: %s", got, want)
+			}
+
+			liteEntry := entry.Clone()
+			liteEntry.Data.Chain = nil
+			liteEntry.Data.LeafCert = LeafCert{
+				AllDomains:         entry.Data.LeafCert.AllDomains,
+				Extensions:         entry.Data.LeafCert.Extensions,
+				Fingerprint:        entry.Data.LeafCert.Fingerprint,
+				SHA1:               entry.Data.LeafCert.SHA1,
+				SHA256:             entry.Data.LeafCert.SHA256,
+				NotAfter:           entry.Data.LeafCert.NotAfter,
+				NotBefore:          entry.Data.LeafCert.NotBefore,
+				SerialNumber:       entry.Data.LeafCert.SerialNumber,
+				SignatureAlgorithm: entry.Data.LeafCert.SignatureAlgorithm,
+				Subject:            entry.Data.LeafCert.Subject,
+				Issuer:             entry.Data.LeafCert.Issuer,
+				IsCA:               entry.Data.LeafCert.IsCA,
+				Domains:            entry.Data.LeafCert.Domains,
+			}
+
+			if got, want := entry.JSONLiteNoCache(), referenceJSON(t, &liteEntry); !bytes.Equal(got, want) {
+				t.Errorf("lite JSON differs:\ngot:  %s\nwant: %s", got, want)
+			}
+
+			domainsEntry := DomainsEntry{Data: entry.Data.LeafCert.AllDomains, MessageType: "dns_entries", Seq: entry.Seq}
+
+			want, err := json.Marshal(domainsEntry)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := entry.JSONDomains(); !bytes.Equal(got, want) {
+				t.Errorf("domains JSON differs:\ngot:  %s\nwant: %s", got, want)
+			}
+
+			groupedEntry := GroupedDomainsEntry{Data: entry.Data.LeafCert.DomainGroups(), MessageType: "dns_entries_grouped", Seq: entry.Seq}
+
+			want, err = json.Marshal(groupedEntry)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := entry.JSONDomainsGrouped(); !bytes.Equal(got, want) {
+				t.Errorf("grouped domains JSON differs:\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestLeafCert_DomainGroups(t *testing.T) {
+	leafCert := testEntry().Data.LeafCert
+
+	groups := leafCert.DomainGroups()
+	if len(groups) != 3 {
+		t.Fatalf("want 3 groups, got %+v", groups)
+	}
+
+	if groups[0].RegistrableDomain != "example.com" || len(groups[0].Domains) != 2 || groups[0].Domains[1] != "*.example.com" {
+		t.Errorf("unexpected first group: %+v", groups[0])
+	}
+
+	if groups[2].RegistrableDomain != "" || len(groups[2].Domains) != 1 || groups[2].Domains[0] != "<script>&" {
+		t.Errorf("unexpected group without registrable domain: %+v", groups[2])
+	}
+
+	if groups := (&LeafCert{}).DomainGroups(); groups == nil || len(groups) != 0 {
+		t.Errorf("want empty groups, got %+v", groups)
+	}
+}
+
+func BenchmarkEntryJSON(b *testing.B) {
+	entry := testEntry()
+
+	b.Run("encoder", func(b *testing.B) {
+		for b.Loop() {
+			entry.JSONNoCache()
+		}
+	})
+
+	b.Run("encoding/json", func(b *testing.B) {
+		for b.Loop() {
+			buf := bytes.Buffer{}
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			_ = enc.Encode(&entry)
+		}
+	})
+}
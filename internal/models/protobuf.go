@@ -0,0 +1,230 @@
+package models
+
+import (
+	"encoding/base64"
+
+	certstreamv1 "github.com/d-Rickyy-b/certstream-server-go/api/certstream/v1"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+)
+
+// ProtoMessage converts the entry to a protobuf message as sent by the gRPC service.
+// If lite is set, the chain and the leaf certificate's DER data are omitted.
+func (e *Entry) ProtoMessage(lite bool) *certstreamv1.Message {
+	data := &certstreamv1.Data{
+		CertIndex: e.Data.CertIndex,
+		CertLink:  validUTF8(e.Data.CertLink),
+		LeafCert:  toProtoLeafCert(&e.Data.LeafCert, !lite),
+		Seen:      e.Data.Seen,
+		Source: &certstreamv1.Source{
+			Name:      validUTF8(e.Data.Source.Name),
+			Url:       validUTF8(e.Data.Source.URL),
+			Timestamp: e.Data.Source.Timestamp,
+			Type:      e.Data.Source.Type,
+		},
+		UpdateType: e.Data.UpdateType,
+	}
+
+	if !lite {
+		data.Chain = make([]*certstreamv1.LeafCert, len(e.Data.Chain))
+		for i := range e.Data.Chain {
+			data.Chain[i] = toProtoLeafCert(&e.Data.Chain[i], true)
+		}
+	}
+
+	if e.Data.Lint != nil {
+		data.Lint = &certstreamv1.Lint{Findings: make([]*certstreamv1.LintFinding, len(e.Data.Lint.Findings))}
+		for i, finding := range e.Data.Lint.Findings {
+			data.Lint.Findings[i] = &certstreamv1.LintFinding{
+				Name:     finding.Name,
+				Set:      finding.Set,
+				Severity: finding.Severity,
+				Details:  validUTF8(finding.Details),
+			}
+		}
+	}
+
+	if e.Data.Score != nil {
+		data.Score = &certstreamv1.Score{Value: int32(e.Data.Score.Value), Reasons: make([]*certstreamv1.ScoreReason, len(e.Data.Score.Reasons))} //nolint:gosec
+		for i, reason := range e.Data.Score.Reasons {
+			data.Score.Reasons[i] = &certstreamv1.ScoreReason{
+				Rule:    reason.Rule,
+				Domain:  validUTF8(reason.Domain),
+				Details: validUTF8(reason.Details),
+				Points:  int32(reason.Points), //nolint:gosec
+			}
+		}
+	}
+
+	return &certstreamv1.Message{
+		Seq:     e.Seq,
+		Payload: &certstreamv1.Message_CertificateUpdate{CertificateUpdate: &certstreamv1.CertificateUpdate{Data: data}},
+	}
+}
+
+// ProtoDomainsMessage converts the domains of the entry to a protobuf message as sent by the gRPC service.
+func (e *Entry) ProtoDomainsMessage() *certstreamv1.Message {
+	return &certstreamv1.Message{
+		Seq: e.Seq,
+		Payload: &certstreamv1.Message_DomainsUpdate{
+			DomainsUpdate: &certstreamv1.DomainsUpdate{Domains: validUTF8Strings(e.Data.LeafCert.AllDomains)},
+		},
+	}
+}
+
+// ProtoNewDomainsMessage converts the new domains of the entry to a protobuf message as sent by the gRPC service.
+func (e *Entry) ProtoNewDomainsMessage() *certstreamv1.Message {
+	return &certstreamv1.Message{
+		Seq: e.Seq,
+		Payload: &certstreamv1.Message_DomainsUpdate{
+			DomainsUpdate: &certstreamv1.DomainsUpdate{Domains: validUTF8Strings(e.NewDomains)},
+		},
+	}
+}
+
+// ProtoDomainsGroupedMessage converts the domains of the entry, grouped by registrable domain, to a protobuf message
+// as sent by the gRPC service.
+func (e *Entry) ProtoDomainsGroupedMessage() *certstreamv1.Message {
+	groups := e.Data.LeafCert.DomainGroups()
+
+	update := &certstreamv1.DomainsUpdate{Groups: make([]*certstreamv1.DomainGroup, len(groups))}
+	for i, group := range groups {
+		update.Groups[i] = &certstreamv1.DomainGroup{
+			RegistrableDomain: validUTF8(group.RegistrableDomain),
+			Domains:           validUTF8Strings(group.Domains),
+		}
+	}
+
+	return &certstreamv1.Message{
+		Seq:     e.Seq,
+		Payload: &certstreamv1.Message_DomainsUpdate{DomainsUpdate: update},
+	}
+}
+
+// ProtoMessage converts the GapEntry to a protobuf message as sent by the gRPC service.
+func (g GapEntry) ProtoMessage() *certstreamv1.Message {
+	return &certstreamv1.Message{
+		Payload: &certstreamv1.Message_Gap{Gap: &certstreamv1.Gap{Skipped: g.Skipped}},
+	}
+}
+
+// toProtoLeafCert converts a LeafCert to its protobuf representation. The base64 encoded DER data is decoded
+// to raw bytes if withDER is set. Protobuf strings must be valid UTF-8, so invalid sequences are replaced.
+func toProtoLeafCert(lc *LeafCert, withDER bool) *certstreamv1.LeafCert {
+	leafCert := &certstreamv1.LeafCert{
+		AllDomains: validUTF8Strings(lc.AllDomains),
+		Extensions: &certstreamv1.Extensions{
+			AuthorityInfoAccess:           validUTF8Ptr(lc.Extensions.AuthorityInfoAccess),
+			AuthorityKeyIdentifier:        validUTF8Ptr(lc.Extensions.AuthorityKeyIdentifier),
+			BasicConstraints:              validUTF8Ptr(lc.Extensions.BasicConstraints),
+			CertificatePolicies:           validUTF8Ptr(lc.Extensions.CertificatePolicies),
+			CtlSignedCertificateTimestamp: validUTF8Ptr(lc.Extensions.CtlSignedCertificateTimestamp),
+			ExtendedKeyUsage:              validUTF8Ptr(lc.Extensions.ExtendedKeyUsage),
+			KeyUsage:                      validUTF8Ptr(lc.Extensions.KeyUsage),
+			SubjectAltName:                validUTF8Ptr(lc.Extensions.SubjectAltName),
+			SubjectKeyIdentifier:          validUTF8Ptr(lc.Extensions.SubjectKeyIdentifier),
+			CtlPoisonByte:                 lc.Extensions.CTLPoisonByte,
+		},
+		Fingerprint:        lc.Fingerprint,
+		Sha1:               lc.SHA1,
+		Sha256:             lc.SHA256,
+		NotAfter:           lc.NotAfter,
+		NotBefore:          lc.NotBefore,
+		SerialNumber:       lc.SerialNumber,
+		SignatureAlgorithm: lc.SignatureAlgorithm,
+		Subject:            toProtoSubject(&lc.Subject),
+		Issuer:             toProtoSubject(&lc.Issuer),
+		IsCa:               lc.IsCA,
+	}
+
+	for _, domain := range lc.Domains {
+		leafCert.Domains = append(leafCert.Domains, &certstreamv1.Domain{
+			Name:              validUTF8(domain.Name),
+			Unicode:           validUTF8(domain.Unicode),
+			RegistrableDomain: validUTF8(domain.RegistrableDomain),
+			Wildcard:          domain.Wildcard,
+		})
+	}
+
+	if !withDER {
+		return leafCert
+	}
+
+	if lc.AsDER != "" {
+		der, err := base64.StdEncoding.DecodeString(lc.AsDER)
+		if err != nil {
+			logger.Error("Error decoding DER data for protobuf encoding", logging.KeyError, err)
+		}
+
+		leafCert.Der = der
+	}
+
+	if lc.PublicKey != nil {
+		leafCert.PublicKey = &certstreamv1.PublicKey{
+			Algorithm:  lc.PublicKey.Algorithm,
+			Size:       int32(lc.PublicKey.Size), //nolint:gosec // key sizes are far below the int32 limit
+			Curve:      lc.PublicKey.Curve,
+			SpkiSha256: lc.PublicKey.SPKISHA256,
+		}
+	}
+
+	if nc := lc.NameConstraints; nc != nil {
+		leafCert.NameConstraints = &certstreamv1.NameConstraints{
+			Critical:                nc.Critical,
+			PermittedDnsDomains:     validUTF8Strings(nc.PermittedDNSDomains),
+			ExcludedDnsDomains:      validUTF8Strings(nc.ExcludedDNSDomains),
+			PermittedIpRanges:       nc.PermittedIPRanges,
+			ExcludedIpRanges:        nc.ExcludedIPRanges,
+			PermittedEmailAddresses: validUTF8Strings(nc.PermittedEmailAddresses),
+			ExcludedEmailAddresses:  validUTF8Strings(nc.ExcludedEmailAddresses),
+			PermittedUriDomains:     validUTF8Strings(nc.PermittedURIDomains),
+			ExcludedUriDomains:      validUTF8Strings(nc.ExcludedURIDomains),
+		}
+	}
+
+	leafCert.IpAddresses = lc.IPAddresses
+	leafCert.EmailAddresses = validUTF8Strings(lc.EmailAddresses)
+	leafCert.PolicyOids = lc.PolicyOIDs
+	leafCert.CrlDistributionPoints = validUTF8Strings(lc.CRLDistributionPoints)
+	leafCert.OcspUrls = validUTF8Strings(lc.OCSPServers)
+	leafCert.CaIssuerUrls = validUTF8Strings(lc.IssuingCertificateURLs)
+	leafCert.ValidationLevel = lc.ValidationLevel
+	leafCert.ValidityDays = lc.ValidityDays
+
+	for _, sct := range lc.SCTs {
+		leafCert.Scts = append(leafCert.Scts, &certstreamv1.SCT{
+			LogId:              sct.LogID,
+			LogName:            validUTF8(sct.LogName),
+			LogOperator:        validUTF8(sct.LogOperator),
+			Timestamp:          sct.Timestamp,
+			SignatureAlgorithm: sct.SignatureAlgorithm,
+			Verified:           sct.Verified,
+		})
+	}
+
+	return leafCert
+}
+
+func toProtoSubject(subject *Subject) *certstreamv1.Subject {
+	return &certstreamv1.Subject{
+		C:            validUTF8Ptr(subject.C),
+		Cn:           validUTF8Ptr(subject.CN),
+		L:            validUTF8Ptr(subject.L),
+		O:            validUTF8Ptr(subject.O),
+		Ou:           validUTF8Ptr(subject.OU),
+		St:           validUTF8Ptr(subject.ST),
+		Aggregated:   validUTF8Ptr(subject.Aggregated),
+		EmailAddress: validUTF8Ptr(subject.EmailAddress),
+	}
+}
+
+// ProtoMessage converts the HeartbeatEntry to a protobuf message as sent by the gRPC service.
+func (h HeartbeatEntry) ProtoMessage() *certstreamv1.Message {
+	return &certstreamv1.Message{
+		Payload: &certstreamv1.Message_Heartbeat{Heartbeat: &certstreamv1.Heartbeat{
+			Timestamp:                h.Timestamp,
+			ProcessedCertificates:    h.Stats.ProcessedCertificates,
+			ProcessedPrecertificates: h.Stats.ProcessedPrecertificates,
+			Skipped:                  h.Stats.Skipped,
+		}},
+	}
+}
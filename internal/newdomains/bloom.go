@@ -0,0 +1,66 @@
+package newdomains
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// bloomFilter is a Bloom filter with k hash functions derived from a single 128 bit FNV-1a hash via double hashing.
+// FNV is used instead of a seeded hash, so that the filter can be persisted and loaded again after a restart.
+type bloomFilter struct {
+	bits []uint64
+	k    uint32
+}
+
+// newBloomFilter creates a Bloom filter for the given number of items with the given false positive rate.
+func newBloomFilter(capacity int, falsePositiveRate float64) *bloomFilter {
+	m, k := bloomParameters(capacity, falsePositiveRate)
+
+	return &bloomFilter{bits: make([]uint64, m/64), k: k}
+}
+
+// bloomParameters returns the optimal number of bits, rounded up to a multiple of 64, and hash functions.
+func bloomParameters(capacity int, falsePositiveRate float64) (uint64, uint32) {
+	n := float64(max(capacity, 1))
+
+	m := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	words := uint64(math.Ceil(m / 64))
+	k := uint32(max(math.Round(float64(words*64)/n*math.Ln2), 1))
+
+	return words * 64, k
+}
+
+// hashKey returns the two hashes used for double hashing.
+func hashKey(key string) (uint64, uint64) {
+	h := fnv.New128a()
+	_, _ = h.Write([]byte(key))
+
+	sum := h.Sum(nil)
+
+	return binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:]) | 1
+}
+
+// add adds the key with the given hashes to the filter.
+func (f *bloomFilter) add(h1, h2 uint64) {
+	m := uint64(len(f.bits)) * 64
+
+	for i := range uint64(f.k) {
+		bit := (h1 + i*h2) % m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// contains returns true if the key with the given hashes was probably added to the filter.
+func (f *bloomFilter) contains(h1, h2 uint64) bool {
+	m := uint64(len(f.bits)) * 64
+
+	for i := range uint64(f.k) {
+		bit := (h1 + i*h2) % m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,270 @@
+package newdomains
+
+// The newdomains package detects domains that are seen for the first time within a time window. The domains seen
+// are stored in a sequence of Bloom filters, each covering a fraction of the window. A domain is new if none of the
+// filters contains it, and every sighting adds it to the current filter. Once a filter is older than the window,
+// it's dropped, so that the memory stays bounded and domains that weren't seen again are reported as new again.
+// Due to the nature of Bloom filters, a small fraction of new domains is not reported (false positives).
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+var (
+	ErrUnknownMode      = errors.New("unknown new domains mode")
+	ErrIncompatibleFile = errors.New("incompatible new domains file")
+)
+
+// Modes define which part of a domain is tracked.
+const (
+	ModeRegistrableDomain = "registrable_domain"
+	ModeFQDN              = "fqdn"
+)
+
+// generationsPerWindow is the number of filters the window is split into. The window is exact up to the length
+// of a single generation.
+const generationsPerWindow = 4
+
+// fileMagic identifies the files written by Save. The last byte is the version of the format.
+var fileMagic = [4]byte{'C', 'S', 'N', 2}
+
+// generation is a Bloom filter containing the domains seen since start.
+type generation struct {
+	start  time.Time
+	filter *bloomFilter
+}
+
+// Tracker reports the domains that weren't seen within the window.
+type Tracker struct {
+	mu                sync.Mutex
+	fqdn              bool
+	window            time.Duration
+	capacity          int
+	falsePositiveRate float64
+	// generations are ordered from newest to oldest.
+	generations []*generation
+	// now returns the current time. It's replaced in tests.
+	now func() time.Time
+}
+
+// New creates an empty tracker for the configured mode and window. Each generation is sized for the capacity and
+// a fraction of the false positive rate, since a domain is checked against all generations. So the false positive
+// rate holds as long as the number of distinct domains within the window doesn't exceed the capacity.
+func New(conf config.NewDomainsConfig) (*Tracker, error) {
+	if conf.Mode != ModeRegistrableDomain && conf.Mode != ModeFQDN {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownMode, conf.Mode)
+	}
+
+	return &Tracker{
+		fqdn:              conf.Mode == ModeFQDN,
+		window:            time.Duration(conf.Window) * time.Hour,
+		capacity:          conf.Capacity,
+		falsePositiveRate: conf.FalsePositiveRate,
+		now:               time.Now,
+	}, nil
+}
+
+// Observe records the domains and returns those that weren't seen within the window, in the order of the domains.
+// In registrable domain mode, domains without a registrable domain are ignored.
+func (t *Tracker) Observe(domains []models.Domain) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rotate(t.now())
+
+	var newDomains []string
+
+	for _, domain := range domains {
+		key := domain.RegistrableDomain
+		if t.fqdn {
+			key = domain.Name
+		}
+
+		if key == "" {
+			continue
+		}
+
+		h1, h2 := hashKey(key)
+		if !t.contains(h1, h2) {
+			newDomains = append(newDomains, key)
+		}
+
+		t.generations[0].filter.add(h1, h2)
+	}
+
+	return newDomains
+}
+
+// contains returns true if any of the generations contains the key with the given hashes.
+func (t *Tracker) contains(h1, h2 uint64) bool {
+	for _, gen := range t.generations {
+		if gen.filter.contains(h1, h2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generationFalsePositiveRate returns the false positive rate of a single generation. Up to generationsPerWindow+1
+// generations are checked for each domain, so their false positive rates add up to the configured one.
+func (t *Tracker) generationFalsePositiveRate() float64 {
+	return t.falsePositiveRate / (generationsPerWindow + 1)
+}
+
+// rotate starts a new generation if the current one is full and drops the generations that ended before the window.
+func (t *Tracker) rotate(now time.Time) {
+	generationLength := t.window / generationsPerWindow
+
+	if len(t.generations) == 0 || now.Sub(t.generations[0].start) >= generationLength {
+		current := &generation{start: now, filter: newBloomFilter(t.capacity, t.generationFalsePositiveRate())}
+		t.generations = append([]*generation{current}, t.generations...)
+	}
+
+	// Domains are only added to a generation within its length, since the next generation is started before adding
+	// domains afterward. This also holds if the tracker was stopped in the meantime.
+	windowStart := now.Add(-t.window)
+	for i := 1; i < len(t.generations); i++ {
+		if !t.generations[i].start.Add(generationLength).After(windowStart) {
+			t.generations = t.generations[:i]
+			break
+		}
+	}
+}
+
+// fileHeader is written at the start of the file. The filter is only loaded if it was created with the same parameters.
+type fileHeader struct {
+	Magic             [4]byte
+	FQDN              bool
+	Window            int64
+	Capacity          int64
+	FalsePositiveRate float64
+	Generations       uint32
+}
+
+func (t *Tracker) header() fileHeader {
+	return fileHeader{
+		Magic:             fileMagic,
+		FQDN:              t.fqdn,
+		Window:            int64(t.window),
+		Capacity:          int64(t.capacity),
+		FalsePositiveRate: t.falsePositiveRate,
+		Generations:       uint32(len(t.generations)), //nolint:gosec
+	}
+}
+
+// Save writes the filters to the file. Like the CT index, the data is first written to a temporary file, which is
+// then moved to the actual file, so that the last good file isn't clobbered if the program is killed while writing.
+func (t *Tracker) Save(filePath string) error {
+	// Copy the filters, so that the tracker isn't blocked while writing the file
+	t.mu.Lock()
+	header := t.header()
+	generations := make([]generation, len(t.generations))
+
+	for i, gen := range t.generations {
+		generations[i] = generation{start: gen.start, filter: &bloomFilter{bits: append([]uint64(nil), gen.filter.bits...), k: gen.filter.k}}
+	}
+	t.mu.Unlock()
+
+	tempFilePath := filePath + ".tmp"
+
+	file, err := os.Create(tempFilePath)
+	if err != nil {
+		return fmt.Errorf("could not create new domains temp file: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	writeErr := binary.Write(writer, binary.LittleEndian, header)
+
+	for _, gen := range generations {
+		if writeErr != nil {
+			break
+		}
+
+		writeErr = binary.Write(writer, binary.LittleEndian, gen.start.UnixNano())
+		if writeErr == nil {
+			writeErr = binary.Write(writer, binary.LittleEndian, gen.filter.bits)
+		}
+	}
+
+	if writeErr == nil {
+		writeErr = writer.Flush()
+	}
+
+	if closeErr := file.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+
+	if writeErr != nil {
+		return fmt.Errorf("could not write new domains temp file: %w", writeErr)
+	}
+
+	if err := os.Rename(tempFilePath, filePath); err != nil {
+		return fmt.Errorf("could not rename new domains temp file: %w", err)
+	}
+
+	return nil
+}
+
+// Load replaces the filters with those of the file. If the file was written with other parameters, it's not loaded
+// and ErrIncompatibleFile is returned. A missing file is returned as an error matching os.ErrNotExist.
+func (t *Tracker) Load(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var header fileHeader
+	if err := binary.Read(reader, binary.LittleEndian, &header); err != nil {
+		return fmt.Errorf("could not read new domains file header: %w", err)
+	}
+
+	expected := t.header()
+	expected.Generations = header.Generations
+
+	if header != expected || header.Generations > generationsPerWindow+1 {
+		return ErrIncompatibleFile
+	}
+
+	m, k := bloomParameters(t.capacity, t.generationFalsePositiveRate())
+	generations := make([]*generation, header.Generations)
+
+	for i := range generations {
+		var start int64
+		if err := binary.Read(reader, binary.LittleEndian, &start); err != nil {
+			return fmt.Errorf("could not read new domains generation: %w", err)
+		}
+
+		filter := &bloomFilter{bits: make([]uint64, m/64), k: k}
+		if err := binary.Read(reader, binary.LittleEndian, filter.bits); err != nil {
+			return fmt.Errorf("could not read new domains generation: %w", err)
+		}
+
+		generations[i] = &generation{start: time.Unix(0, start), filter: filter}
+	}
+
+	if _, err := reader.ReadByte(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: unexpected data at the end of the file", ErrIncompatibleFile)
+	}
+
+	t.generations = generations
+	t.rotate(t.now())
+
+	return nil
+}
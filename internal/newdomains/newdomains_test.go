@@ -0,0 +1,195 @@
+package newdomains
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// newTestTracker creates a tracker with a window of 4 hours, i.e. generations of one hour, and a controllable clock.
+func newTestTracker(t *testing.T, mode string) (*Tracker, *time.Time) {
+	t.Helper()
+
+	tracker, err := New(config.NewDomainsConfig{Mode: mode, Window: 4, Capacity: 1000, FalsePositiveRate: 0.001})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	return tracker, &now
+}
+
+func domains(names ...string) []models.Domain {
+	result := make([]models.Domain, len(names))
+	for i, name := range names {
+		result[i] = models.Domain{Name: name, RegistrableDomain: name}
+		if name == "www.example.com" || name == "mail.example.com" {
+			result[i].RegistrableDomain = "example.com"
+		}
+	}
+
+	return result
+}
+
+func TestNew_UnknownMode(t *testing.T) {
+	if _, err := New(config.NewDomainsConfig{Mode: "domain"}); !errors.Is(err, ErrUnknownMode) {
+		t.Errorf("want ErrUnknownMode, got %v", err)
+	}
+}
+
+func TestTracker_Observe(t *testing.T) {
+	tracker, _ := newTestTracker(t, ModeRegistrableDomain)
+
+	if got := tracker.Observe(domains("www.example.com", "mail.example.com", "example.org")); !slices.Equal(got, []string{"example.com", "example.org"}) {
+		t.Errorf("want both registrable domains to be new, got %v", got)
+	}
+
+	if got := tracker.Observe(domains("example.com", "example.net")); !slices.Equal(got, []string{"example.net"}) {
+		t.Errorf("want only example.net to be new, got %v", got)
+	}
+
+	if got := tracker.Observe([]models.Domain{{Name: "192.0.2.1"}}); got != nil {
+		t.Errorf("want domains without registrable domain to be ignored, got %v", got)
+	}
+}
+
+func TestTracker_ObserveFQDN(t *testing.T) {
+	tracker, _ := newTestTracker(t, ModeFQDN)
+
+	tracker.Observe(domains("www.example.com"))
+
+	if got := tracker.Observe(domains("www.example.com", "mail.example.com")); !slices.Equal(got, []string{"mail.example.com"}) {
+		t.Errorf("want only mail.example.com to be new, got %v", got)
+	}
+}
+
+func TestTracker_Window(t *testing.T) {
+	tracker, now := newTestTracker(t, ModeRegistrableDomain)
+
+	tracker.Observe(domains("example.com", "example.org"))
+
+	// example.com is seen again within the window, so it stays known for another window
+	*now = now.Add(3 * time.Hour)
+	if got := tracker.Observe(domains("example.com")); got != nil {
+		t.Errorf("want example.com to be known within the window, got %v", got)
+	}
+
+	*now = now.Add(2*time.Hour + time.Minute)
+	if got := tracker.Observe(domains("example.com", "example.org")); !slices.Equal(got, []string{"example.org"}) {
+		t.Errorf("want example.org to be new after the window, got %v", got)
+	}
+
+	if len(tracker.generations) > generationsPerWindow+1 {
+		t.Errorf("want at most %d generations, got %d", generationsPerWindow+1, len(tracker.generations))
+	}
+
+	// After a downtime longer than the window, all domains are new again
+	*now = now.Add(24 * time.Hour)
+	if got := tracker.Observe(domains("example.com")); !slices.Equal(got, []string{"example.com"}) {
+		t.Errorf("want example.com to be new after a downtime, got %v", got)
+	}
+
+	if len(tracker.generations) != 1 {
+		t.Errorf("want only the current generation after a downtime, got %d", len(tracker.generations))
+	}
+}
+
+func TestBloomFilter_FalsePositiveRate(t *testing.T) {
+	filter := newBloomFilter(1000, 0.001)
+
+	for i := range 1000 {
+		filter.add(hashKey(fmt.Sprintf("known-%d.example.com", i)))
+	}
+
+	falsePositives := 0
+
+	for i := range 10000 {
+		if filter.contains(hashKey(fmt.Sprintf("unknown-%d.example.com", i))) {
+			falsePositives++
+		}
+	}
+
+	// With a false positive rate of 0.1%, about 10 false positives are expected
+	if falsePositives > 50 {
+		t.Errorf("want a false positive rate around 0.1%%, got %d of 10000", falsePositives)
+	}
+}
+
+func TestTracker_FalsePositiveRate(t *testing.T) {
+	tracker, now := newTestTracker(t, ModeRegistrableDomain)
+
+	// Fill all generations of the window up to the capacity
+	for gen := range generationsPerWindow + 1 {
+		for i := range 1000 {
+			tracker.Observe(domains(fmt.Sprintf("known-%d-%d.example.com", gen, i)))
+		}
+
+		if gen < generationsPerWindow {
+			*now = now.Add(time.Hour)
+		}
+	}
+
+	if len(tracker.generations) != generationsPerWindow+1 {
+		t.Fatalf("want %d generations, got %d", generationsPerWindow+1, len(tracker.generations))
+	}
+
+	falsePositives := 0
+
+	for i := range 10000 {
+		if tracker.contains(hashKey(fmt.Sprintf("unknown-%d.example.com", i))) {
+			falsePositives++
+		}
+	}
+
+	// The rate of 0.1% applies to all generations together, so about 10 false positives are expected
+	if falsePositives > 30 {
+		t.Errorf("want a false positive rate around 0.1%% across all generations, got %d of 10000", falsePositives)
+	}
+}
+
+func TestTracker_SaveLoad(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "new_domains.bloom")
+
+	tracker, now := newTestTracker(t, ModeRegistrableDomain)
+	tracker.Observe(domains("example.com"))
+
+	*now = now.Add(90 * time.Minute)
+	tracker.Observe(domains("example.org"))
+
+	if err := tracker.Save(filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, loadedNow := newTestTracker(t, ModeRegistrableDomain)
+	*loadedNow = *now
+
+	if err := loaded.Load(filePath); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := loaded.Observe(domains("example.com", "example.org", "example.net")); !slices.Equal(got, []string{"example.net"}) {
+		t.Errorf("want only example.net to be new after loading, got %v", got)
+	}
+
+	if _, err := os.Stat(filePath + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("want temp file to be removed, got %v", err)
+	}
+
+	other, _ := newTestTracker(t, ModeFQDN)
+	if err := other.Load(filePath); !errors.Is(err, ErrIncompatibleFile) {
+		t.Errorf("want ErrIncompatibleFile for another mode, got %v", err)
+	}
+
+	if err := other.Load(filepath.Join(t.TempDir(), "missing.bloom")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("want os.ErrNotExist for a missing file, got %v", err)
+	}
+}
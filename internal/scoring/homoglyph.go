@@ -0,0 +1,69 @@
+package scoring
+
+import "strings"
+
+// homoglyphs maps characters to the latin letter they are confused with. Characters that are confused with each
+// other, such as "i", "l" and "1", are mapped to the same letter.
+var homoglyphs = map[rune]rune{
+	// Digits
+	'0': 'o', '1': 'l', '3': 'e', '5': 's',
+	// Latin
+	'i': 'l', 'ı': 'l', 'í': 'l', 'ì': 'l', 'ï': 'l', 'î': 'l',
+	'á': 'a', 'à': 'a', 'ä': 'a', 'â': 'a', 'ã': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ë': 'e', 'ê': 'e',
+	'ó': 'o', 'ò': 'o', 'ö': 'o', 'ô': 'o', 'õ': 'o', 'ø': 'o',
+	'ú': 'u', 'ù': 'u', 'ü': 'u', 'û': 'u',
+	'ç': 'c', 'ñ': 'n', 'ý': 'y', 'ÿ': 'y', 'ɡ': 'g',
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'һ': 'h', 'і': 'l', 'ӏ': 'l', 'ј': 'j', 'к': 'k', 'м': 'm',
+	'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's', 'т': 't', 'ѵ': 'v', 'ԝ': 'w', 'х': 'x', 'у': 'y',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'ι': 'l', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x',
+	// Armenian
+	'ց': 'g', 'հ': 'h', 'ո': 'n', 'ս': 'u', 'օ': 'o',
+}
+
+// multiCharHomoglyphs are sequences of latin letters that look like a single letter.
+var multiCharHomoglyphs = strings.NewReplacer("rn", "m", "vv", "w", "cl", "d")
+
+// skeleton returns the string with all homoglyphs replaced, so that strings looking alike have the same skeleton.
+func skeleton(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if replacement, ok := homoglyphs[r]; ok {
+			return replacement
+		}
+
+		return r
+	}, s)
+
+	return multiCharHomoglyphs.Replace(s)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := range ra {
+		current[0] = i + 1
+
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+
+			current[j+1] = min(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
@@ -0,0 +1,197 @@
+package scoring
+
+// The scoring package rates how likely the domains of a certificate are used for phishing, similar to
+// phishing_catcher. Each rule that matches a domain adds points, and the score of a certificate is the highest score
+// of its domains. The rules are meant as a pre-filter for consumers, so they favor recall over precision.
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// Names of the rules.
+const (
+	RuleKeyword       = "keyword"
+	RuleBrand         = "brand"
+	RuleLookalike     = "lookalike"
+	RuleHomoglyph     = "homoglyph"
+	RuleSuspiciousTLD = "suspicious_tld"
+	RuleDeepSubdomain = "deep_subdomain"
+	RulePunycode      = "punycode"
+)
+
+// Points of the rules. The points of keywords are configured.
+const (
+	brandPoints         = 60
+	homoglyphPoints     = 70
+	lookalikePoints     = 50
+	suspiciousTLDPoints = 20
+	punycodePoints      = 20
+	// deepSubdomainPoints are added for each subdomain level beyond maxSubdomainLevels.
+	deepSubdomainPoints = 10
+	maxSubdomainLevels  = 3
+	// minLookalikeLength is the minimum length of brands checked for look-alikes. Shorter brands have too many
+	// legitimate domains at an edit distance of 1.
+	minLookalikeLength = 5
+)
+
+type keyword struct {
+	word   string
+	points int
+}
+
+type brand struct {
+	name     string
+	skeleton string
+}
+
+// Scorer rates the domains of certificates.
+type Scorer struct {
+	keywords       []keyword
+	brands         []brand
+	suspiciousTLDs map[string]struct{}
+}
+
+// New creates a scorer with the keywords, brands and TLDs of the configuration.
+// Keywords without points are ignored, so that default keywords can be disabled.
+func New(conf config.ScoringConfig) *Scorer {
+	scorer := &Scorer{suspiciousTLDs: make(map[string]struct{}, len(conf.SuspiciousTLDs))}
+
+	for word, points := range conf.Keywords {
+		if word = strings.ToLower(word); word != "" && points > 0 {
+			scorer.keywords = append(scorer.keywords, keyword{word: word, points: points})
+		}
+	}
+
+	// Sort the keywords, so that the reasons are in a stable order
+	slices.SortFunc(scorer.keywords, func(a, b keyword) int { return strings.Compare(a.word, b.word) })
+
+	for _, name := range conf.Brands {
+		if name = strings.ToLower(name); name != "" {
+			scorer.brands = append(scorer.brands, brand{name: name, skeleton: skeleton(name)})
+		}
+	}
+
+	for _, tld := range conf.SuspiciousTLDs {
+		scorer.suspiciousTLDs[strings.ToLower(strings.TrimPrefix(tld, "."))] = struct{}{}
+	}
+
+	return scorer
+}
+
+// Score rates the domains and returns the highest score together with the reasons of all domains.
+func (s *Scorer) Score(domains []string) *models.Score {
+	result := &models.Score{Reasons: []models.ScoreReason{}}
+	seen := make(map[string]struct{}, len(domains))
+
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "*."))
+		if _, ok := seen[domain]; ok || domain == "" {
+			continue
+		}
+
+		seen[domain] = struct{}{}
+
+		reasons := s.scoreDomain(domain)
+
+		points := 0
+		for _, reason := range reasons {
+			points += reason.Points
+		}
+
+		result.Value = max(result.Value, points)
+		result.Reasons = append(result.Reasons, reasons...)
+	}
+
+	return result
+}
+
+// scoreDomain returns the rules matching the lower case domain.
+func (s *Scorer) scoreDomain(domain string) []models.ScoreReason {
+	var reasons []models.ScoreReason
+
+	add := func(rule, details string, points int) {
+		reasons = append(reasons, models.ScoreReason{Rule: rule, Domain: domain, Details: details, Points: points})
+	}
+
+	// Punycode is decoded, so that look-alikes using other scripts are detected
+	unicodeDomain := domain
+	if strings.Contains(domain, "xn--") {
+		if decoded, err := idna.ToUnicode(domain); err == nil && decoded != domain {
+			unicodeDomain = decoded
+			add(RulePunycode, decoded, punycodePoints)
+		}
+	}
+
+	suffix, _ := publicsuffix.PublicSuffix(domain)
+	suffixLabels := strings.Count(suffix, ".") + 1
+	name := stripLabels(domain, suffixLabels)
+
+	if _, ok := s.suspiciousTLDs[domain[strings.LastIndexByte(domain, '.')+1:]]; ok {
+		add(RuleSuspiciousTLD, suffix, suspiciousTLDPoints)
+	}
+
+	for _, kw := range s.keywords {
+		if strings.Contains(name, kw.word) {
+			add(RuleKeyword, kw.word, kw.points)
+		}
+	}
+
+	if levels := strings.Count(name, "."); levels > maxSubdomainLevels {
+		add(RuleDeepSubdomain, fmt.Sprintf("%d subdomain levels", levels), (levels-maxSubdomainLevels)*deepSubdomainPoints)
+	}
+
+	// The brands are compared against the labels without the public suffix. The label right before the suffix
+	// is the registered name, which is legitimate if it equals the brand.
+	if unicodeName := stripLabels(unicodeDomain, suffixLabels); unicodeName != "" {
+		labels := strings.Split(unicodeName, ".")
+
+		for _, b := range s.brands {
+			if rule, points := matchBrand(labels, b); rule != "" {
+				add(rule, b.name, points)
+			}
+		}
+	}
+
+	return reasons
+}
+
+// stripLabels removes the given number of labels from the end of the domain.
+func stripLabels(domain string, count int) string {
+	labels := strings.Split(domain, ".")
+	if len(labels) <= count {
+		return ""
+	}
+
+	return strings.Join(labels[:len(labels)-count], ".")
+}
+
+// matchBrand returns the first rule of the brand matching any of the labels. The last label is the registered name.
+func matchBrand(labels []string, b brand) (string, int) {
+	for i, label := range labels {
+		for token := range strings.SplitSeq(label, "-") {
+			switch {
+			case token == b.name:
+				if i == len(labels)-1 && label == b.name {
+					// The domain belongs to the brand
+					continue
+				}
+
+				return RuleBrand, brandPoints
+			case skeleton(token) == b.skeleton:
+				return RuleHomoglyph, homoglyphPoints
+			case len(b.name) >= minLookalikeLength && levenshtein(skeleton(token), b.skeleton) == 1:
+				return RuleLookalike, lookalikePoints
+			}
+		}
+	}
+
+	return "", 0
+}
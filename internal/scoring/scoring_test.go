@@ -0,0 +1,97 @@
+package scoring
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+func newTestScorer() *Scorer {
+	return New(config.ScoringConfig{
+		Keywords:       map[string]int{"login": 25, "Secure": 15, "disabled": 0},
+		Brands:         []string{"paypal", "apple"},
+		SuspiciousTLDs: []string{".xyz", "zip"},
+	})
+}
+
+func rules(score *models.Score) []string {
+	names := make([]string, 0, len(score.Reasons))
+	for _, reason := range score.Reasons {
+		names = append(names, reason.Rule+":"+reason.Details)
+	}
+
+	return names
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		domains   []string
+		wantValue int
+		wantRules []string
+	}{
+		{"legitimate brand domain", []string{"paypal.com", "www.paypal.com"}, 0, []string{}},
+		{"unrelated domain", []string{"example.com"}, 0, []string{}},
+		{"keywords", []string{"secure-login.example.com"}, 40, []string{"keyword:login", "keyword:secure"}},
+		{"disabled keyword", []string{"disabled.example.com"}, 0, []string{}},
+		{"keyword in suffix", []string{"example.login"}, 0, []string{}},
+		{"brand in subdomain", []string{"paypal.com.example.xyz"}, 80, []string{"suspicious_tld:xyz", "brand:paypal"}},
+		{"brand with hyphen", []string{"paypal-login.com"}, 85, []string{"keyword:login", "brand:paypal"}},
+		{"digit homoglyph", []string{"paypa1.com"}, 70, []string{"homoglyph:paypal"}},
+		{"latin homoglyph", []string{"appie.co.uk"}, 70, []string{"homoglyph:apple"}},
+		{"lookalike", []string{"paypall.com"}, 50, []string{"lookalike:paypal"}},
+		{"missing letter lookalike", []string{"appl.com"}, 50, []string{"lookalike:apple"}},
+		{"deep subdomain", []string{"a.b.c.d.e.example.com"}, 20, []string{"deep_subdomain:5 subdomain levels"}},
+		// "xn--pypal-4ve.com" is "pаypal.com" with a cyrillic "а"
+		{"punycode homoglyph", []string{"xn--pypal-4ve.com"}, 90, []string{"punycode:pаypal.com", "homoglyph:paypal"}},
+		{"wildcard", []string{"*.paypal.com.example.xyz"}, 80, []string{"suspicious_tld:xyz", "brand:paypal"}},
+		{"highest domain", []string{"example.zip", "login.example.com"}, 25, []string{"suspicious_tld:zip", "keyword:login"}},
+	}
+
+	scorer := newTestScorer()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := scorer.Score(tt.domains)
+
+			if score.Value != tt.wantValue {
+				t.Errorf("want score %d, got %d (%v)", tt.wantValue, score.Value, rules(score))
+			}
+
+			if got := rules(score); !slices.Equal(got, tt.wantRules) {
+				t.Errorf("want reasons %v, got %v", tt.wantRules, got)
+			}
+		})
+	}
+}
+
+func TestScore_ReasonDomain(t *testing.T) {
+	score := newTestScorer().Score([]string{"*.Login.example.com", "login.example.com"})
+
+	if len(score.Reasons) != 1 || score.Reasons[0].Domain != "login.example.com" || score.Reasons[0].Points != 25 {
+		t.Errorf("want a single reason for the deduplicated domain, got %+v", score.Reasons)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"paypal", "paypal", 0},
+		{"paypal", "paypall", 1},
+		{"paypal", "paypa", 1},
+		{"paypal", "pypal", 1},
+		{"kitten", "sitting", 3},
+		{"pаypal", "paypal", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
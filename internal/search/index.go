@@ -0,0 +1,404 @@
+package search
+
+// The search package stores the certificates in an embedded bbolt database, so that they can be searched by domain,
+// SHA256 fingerprint, serial number, issuer and time without running an external search engine.
+//
+// Certificates are stored in the entries bucket as lite JSON, keyed by the time they were seen followed by a unique
+// sequence number, so that the keys are ordered by time. The index bucket maps each search term to the entries
+// containing it, with keys made of the term, a zero byte and the entry key. As a consequence, the entries of a term
+// are ordered by time as well, which allows paging through them without sorting.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+
+	"github.com/fxamacker/cbor/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+var logger = logging.Logger(logging.SubsystemSearch)
+
+var (
+	entriesBucket = []byte("entries")
+	indexBucket   = []byte("index")
+)
+
+const (
+	// entryKeyLength is the length of the entry keys: the time the entry was seen in nanoseconds and a sequence number.
+	entryKeyLength = 16
+	// queueSize is the number of entries buffered for writing. Entries are dropped if the queue is full.
+	queueSize = 10_000
+	// batchSize is the maximum number of entries written in a single transaction.
+	batchSize = 1_000
+	// retentionInterval is the time between two runs of the retention job.
+	retentionInterval = time.Minute
+)
+
+// Kinds of search terms. The kind is the first byte of each term.
+const (
+	termDomain byte = 'd'
+	termSHA256 byte = 's'
+	termSerial byte = 'n'
+	termIssuer byte = 'i'
+)
+
+// storedEntry is the value of the entries bucket. The terms are stored along with the entry, so that the index keys
+// can be removed and other criteria checked without decoding the entry.
+type storedEntry struct {
+	Terms [][]byte `cbor:"1,keyasint"`
+	JSON  []byte   `cbor:"2,keyasint"`
+}
+
+// Index is a disk-backed search index of the certificates seen by the server.
+type Index struct {
+	db   *bolt.DB
+	conf config.SearchConfig
+
+	queue chan models.Entry
+	stop  chan struct{}
+	wg    sync.WaitGroup
+
+	// mu guards closed, so that no entry is queued after the writer stopped.
+	mu     sync.RWMutex
+	closed bool
+
+	entries atomic.Int64
+}
+
+// Open opens or creates the index database and starts the background jobs writing entries and enforcing the retention.
+func Open(conf config.SearchConfig) (*Index, error) {
+	db, err := bolt.Open(conf.File, 0o600, &bolt.Options{Timeout: time.Second, NoFreelistSync: true})
+	if err != nil {
+		return nil, fmt.Errorf("could not open search index: %w", err)
+	}
+
+	idx := &Index{
+		db:    db,
+		conf:  conf,
+		queue: make(chan models.Entry, queueSize),
+		stop:  make(chan struct{}),
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		entries, err := tx.CreateBucketIfNotExists(entriesBucket)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.CreateBucketIfNotExists(indexBucket); err != nil {
+			return err
+		}
+
+		idx.entries.Store(int64(entries.Stats().KeyN))
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not create search index buckets: %w", err)
+	}
+
+	metrics.Prometheus.RegisterGaugeMetricInt("certstreamservergo_search_entries", idx.entries.Load)
+
+	idx.wg.Add(2)
+
+	go idx.writer()
+	go idx.retention()
+
+	return idx, nil
+}
+
+// Add queues the entry for writing. If the writer can't keep up, the entry is dropped, so that the broadcast of
+// the entries is never blocked by the index.
+func (idx *Index) Add(entry models.Entry) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if idx.closed {
+		return
+	}
+
+	select {
+	case idx.queue <- entry:
+	default:
+		metrics.Prometheus.IncSearchDroppedEntries()
+	}
+}
+
+// Close writes the queued entries and closes the database.
+func (idx *Index) Close() error {
+	idx.mu.Lock()
+	if idx.closed {
+		idx.mu.Unlock()
+		return nil
+	}
+
+	idx.closed = true
+	close(idx.stop)
+	idx.mu.Unlock()
+
+	idx.wg.Wait()
+
+	return idx.db.Close()
+}
+
+// writer writes the queued entries until the index is closed. All entries queued at once are written in a single
+// transaction, which is much faster than a transaction per entry.
+func (idx *Index) writer() {
+	defer idx.wg.Done()
+
+	batch := make([]models.Entry, 0, batchSize)
+
+	for {
+		select {
+		case entry := <-idx.queue:
+			batch = append(batch[:0], entry)
+		case <-idx.stop:
+			// Write the remaining entries before closing
+			for len(idx.queue) > 0 {
+				batch = batch[:0]
+				for len(batch) < batchSize && len(idx.queue) > 0 {
+					batch = append(batch, <-idx.queue)
+				}
+
+				idx.write(batch)
+			}
+
+			return
+		}
+
+		for len(batch) < batchSize && len(idx.queue) > 0 {
+			batch = append(batch, <-idx.queue)
+		}
+
+		idx.write(batch)
+	}
+}
+
+// write stores the entries and their index keys.
+func (idx *Index) write(batch []models.Entry) {
+	err := idx.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		index := tx.Bucket(indexBucket)
+
+		for i := range batch {
+			seq, err := entries.NextSequence()
+			if err != nil {
+				return err
+			}
+
+			key := entryKey(seenTime(&batch[i]), seq)
+			stored := storedEntry{Terms: entryTerms(&batch[i]), JSON: bytes.TrimSuffix(batch[i].JSONLiteNoCache(), []byte{'\n'})}
+
+			value, err := cbor.Marshal(stored)
+			if err != nil {
+				return err
+			}
+
+			if err := entries.Put(key, value); err != nil {
+				return err
+			}
+
+			for _, term := range stored.Terms {
+				if err := index.Put(indexKey(term, key), nil); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error("Could not write entries to search index", "count", len(batch), logging.KeyError, err)
+		return
+	}
+
+	idx.entries.Add(int64(len(batch)))
+}
+
+// retention periodically removes the entries that are older than the retention or exceed the maximum number of entries.
+func (idx *Index) retention() {
+	defer idx.wg.Done()
+
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		idx.enforceRetention()
+
+		select {
+		case <-ticker.C:
+		case <-idx.stop:
+			return
+		}
+	}
+}
+
+// enforceRetention removes the oldest entries until all entries are within the retention and the maximum number of
+// entries. The entries are removed in batches, so that writing new entries isn't blocked for long.
+func (idx *Index) enforceRetention() {
+	cutoff := entryKey(time.Now().Add(-time.Duration(idx.conf.Retention)*time.Hour), 0)
+
+	for {
+		excess := 0
+		if idx.conf.MaxEntries > 0 {
+			excess = max(int(idx.entries.Load())-idx.conf.MaxEntries, 0)
+		}
+
+		removed, err := idx.removeOldest(cutoff, excess)
+		if err != nil {
+			logger.Error("Could not remove entries from search index", logging.KeyError, err)
+			return
+		}
+
+		if removed < batchSize {
+			return
+		}
+	}
+}
+
+// removeOldest removes up to batchSize of the oldest entries that were seen before the cutoff or are among the
+// excess oldest entries. It returns the number of removed entries.
+func (idx *Index) removeOldest(cutoff []byte, excess int) (int, error) {
+	removed := 0
+
+	err := idx.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		index := tx.Bucket(indexBucket)
+		c := entries.Cursor()
+
+		for key, value := c.First(); key != nil && removed < batchSize; key, value = c.First() {
+			if removed >= excess && string(key) >= string(cutoff) {
+				break
+			}
+
+			var stored storedEntry
+			if err := cbor.Unmarshal(value, &stored); err != nil {
+				return fmt.Errorf("could not decode entry: %w", err)
+			}
+
+			for _, term := range stored.Terms {
+				if err := index.Delete(indexKey(term, key)); err != nil {
+					return err
+				}
+			}
+
+			if err := c.Delete(); err != nil {
+				return err
+			}
+
+			removed++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	idx.entries.Add(-int64(removed))
+
+	return removed, nil
+}
+
+// entryKey returns the key of an entry seen at the given time.
+func entryKey(seen time.Time, seq uint64) []byte {
+	key := make([]byte, entryKeyLength)
+	binary.BigEndian.PutUint64(key, uint64(max(seen.UnixNano(), 0))) //nolint:gosec
+	binary.BigEndian.PutUint64(key[8:], seq)
+
+	return key
+}
+
+// seenTime returns the time at which the server saw the entry.
+func seenTime(entry *models.Entry) time.Time {
+	return time.UnixMilli(int64(entry.Data.Seen * 1_000))
+}
+
+// indexKey returns the key of the index bucket for the term and the entry key.
+func indexKey(term, key []byte) []byte {
+	indexKey := make([]byte, 0, len(term)+1+len(key))
+	indexKey = append(indexKey, term...)
+	indexKey = append(indexKey, 0)
+
+	return append(indexKey, key...)
+}
+
+// entryTerms returns the search terms of the entry, without duplicates.
+func entryTerms(entry *models.Entry) [][]byte {
+	leafCert := &entry.Data.LeafCert
+	terms := make([][]byte, 0, len(leafCert.Domains)+4)
+
+	addTerm := func(kind byte, value string) {
+		if value == "" {
+			return
+		}
+
+		term := append([]byte{kind}, value...)
+		for _, existing := range terms {
+			if string(existing) == string(term) {
+				return
+			}
+		}
+
+		terms = append(terms, term)
+	}
+
+	for _, domain := range leafCert.Domains {
+		addTerm(termDomain, reverseDomain(domain.Name))
+	}
+
+	addTerm(termSHA256, normalizeHex(leafCert.SHA256))
+	addTerm(termSerial, normalizeSerial(leafCert.SerialNumber))
+
+	if leafCert.Issuer.CN != nil {
+		addTerm(termIssuer, normalizeIssuer(*leafCert.Issuer.CN))
+	}
+
+	if leafCert.Issuer.O != nil {
+		addTerm(termIssuer, normalizeIssuer(*leafCert.Issuer.O))
+	}
+
+	return terms
+}
+
+// reverseDomain reverses the labels of the domain, e.g. "www.example.com" becomes "com.example.www", so that all
+// subdomains of a domain share a common prefix.
+func reverseDomain(domain string) string {
+	labels := strings.Split(domain, ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+
+	return strings.Join(labels, ".")
+}
+
+// normalizeHex removes the colons of a fingerprint and converts it to lower case.
+func normalizeHex(value string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), ":", ""))
+}
+
+// normalizeSerial normalizes a hex encoded serial number like a fingerprint and removes leading zeros.
+func normalizeSerial(value string) string {
+	serial := strings.TrimLeft(normalizeHex(value), "0")
+	if serial == "" && value != "" {
+		return "0"
+	}
+
+	return serial
+}
+
+// normalizeIssuer converts the issuer name to lower case, so that it's matched case-insensitively.
+func normalizeIssuer(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
@@ -0,0 +1,220 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+func newTestIndex(t *testing.T, conf config.SearchConfig) *Index {
+	t.Helper()
+
+	conf.File = filepath.Join(t.TempDir(), "search.db")
+	conf.PageSize = max(conf.PageSize, 10)
+	conf.MaxPageSize = max(conf.MaxPageSize, 100)
+	conf.Retention = max(conf.Retention, 24)
+
+	idx, err := Open(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { idx.Close() })
+
+	return idx
+}
+
+// testEntry creates an entry seen age ago. The serial number is used as the ID of the entry.
+func testEntry(serial string, age time.Duration, issuer string, domains ...string) models.Entry {
+	entry := models.Entry{MessageType: "certificate_update"}
+	entry.Data.Seen = float64(time.Now().Add(-age).UnixMilli()) / 1_000
+	entry.Data.LeafCert.SerialNumber = serial
+	entry.Data.LeafCert.SHA256 = "AB:CD:" + serial
+	entry.Data.LeafCert.Issuer.O = &issuer
+	entry.Data.LeafCert.AllDomains = domains
+
+	for _, domain := range domains {
+		entry.Data.LeafCert.Domains = append(entry.Data.LeafCert.Domains, models.Domain{Name: domain})
+	}
+
+	return entry
+}
+
+// serials returns the serial numbers of the results.
+func serials(t *testing.T, result Result) []string {
+	t.Helper()
+
+	serials := make([]string, len(result.Entries))
+
+	for i, raw := range result.Entries {
+		var entry models.Entry
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		serials[i] = entry.Data.LeafCert.SerialNumber
+	}
+
+	return serials
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := newTestIndex(t, config.SearchConfig{})
+	idx.write([]models.Entry{
+		testEntry("01", 5*time.Hour, "Let's Encrypt", "example.com", "www.example.com"),
+		testEntry("02", 4*time.Hour, "DigiCert Inc", "api.example.com"),
+		testEntry("03", 3*time.Hour, "Let's Encrypt", "example.org"),
+		testEntry("04", 2*time.Hour, "Let's Encrypt", "notexample.com"),
+		testEntry("05", time.Hour, "Let's Encrypt", "a.b.example.com"),
+	})
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all", Query{}, []string{"05", "04", "03", "02", "01"}},
+		{"exact domain", Query{Domain: "Example.com."}, []string{"01"}},
+		{"domain suffix", Query{Domain: "*.example.com"}, []string{"05", "02", "01"}},
+		{"sha256", Query{SHA256: "abcd03"}, []string{"03"}},
+		{"serial", Query{Serial: "0:4"}, []string{"04"}},
+		{"issuer", Query{Issuer: "let's encrypt"}, []string{"05", "04", "03", "01"}},
+		{"issuer and suffix", Query{Domain: "*.example.com", Issuer: "DigiCert Inc"}, []string{"02"}},
+		{"time range", Query{From: time.Now().Add(-4*time.Hour - time.Minute), To: time.Now().Add(-2*time.Hour + time.Minute)}, []string{"04", "03", "02"}},
+		{"time range and issuer", Query{Issuer: "let's encrypt", From: time.Now().Add(-3*time.Hour - time.Minute)}, []string{"05", "04", "03"}},
+		{"no match", Query{Domain: "example.net"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := idx.Search(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := serials(t, result); !slices.Equal(got, tt.want) {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+
+			if result.NextCursor != "" {
+				t.Errorf("want no next cursor, got '%s'", result.NextCursor)
+			}
+		})
+	}
+}
+
+func TestIndex_SearchPagination(t *testing.T) {
+	idx := newTestIndex(t, config.SearchConfig{})
+	idx.write([]models.Entry{
+		testEntry("01", 5*time.Hour, "Let's Encrypt", "a.example.com"),
+		testEntry("02", 4*time.Hour, "Let's Encrypt", "b.example.com"),
+		testEntry("03", 3*time.Hour, "DigiCert Inc", "c.example.com"),
+		testEntry("04", 2*time.Hour, "Let's Encrypt", "d.example.com"),
+		testEntry("05", time.Hour, "Let's Encrypt", "e.example.com"),
+	})
+
+	for _, query := range []Query{{Limit: 2, Issuer: "let's encrypt"}, {Limit: 2, Domain: "*.example.com", Issuer: "let's encrypt"}} {
+		var pages [][]string
+
+		for {
+			result, err := idx.Search(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			pages = append(pages, serials(t, result))
+
+			if result.NextCursor == "" {
+				break
+			}
+
+			query.Cursor = result.NextCursor
+		}
+
+		want := [][]string{{"05", "04"}, {"02", "01"}}
+		if !slices.EqualFunc(pages, want, slices.Equal) {
+			t.Errorf("want pages %v, got %v", want, pages)
+		}
+	}
+
+	if _, err := idx.Search(Query{Cursor: "invalid"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("want ErrInvalidCursor, got %v", err)
+	}
+
+	if _, err := idx.Search(Query{From: time.Now(), To: time.Now().Add(-time.Hour)}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("want ErrInvalidQuery for reversed time range, got %v", err)
+	}
+}
+
+func TestIndex_Retention(t *testing.T) {
+	idx := newTestIndex(t, config.SearchConfig{Retention: 24, MaxEntries: 2})
+	idx.write([]models.Entry{
+		testEntry("01", 48*time.Hour, "Let's Encrypt", "example.com"),
+		testEntry("02", 3*time.Hour, "Let's Encrypt", "example.com"),
+		testEntry("03", 2*time.Hour, "Let's Encrypt", "example.com"),
+		testEntry("04", time.Hour, "Let's Encrypt", "example.com"),
+	})
+
+	idx.enforceRetention()
+
+	result, err := idx.Search(Query{Domain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := serials(t, result); !slices.Equal(got, []string{"04", "03"}) {
+		t.Errorf("want only the two newest entries, got %v", got)
+	}
+
+	if got := idx.entries.Load(); got != 2 {
+		t.Errorf("want 2 entries, got %d", got)
+	}
+
+	// The index keys of the removed entries must be removed as well
+	if result, _ := idx.Search(Query{Serial: "01"}); len(result.Entries) != 0 {
+		t.Errorf("want removed entry not to be found")
+	}
+}
+
+func TestIndex_Persistence(t *testing.T) {
+	conf := config.SearchConfig{File: filepath.Join(t.TempDir(), "search.db"), Retention: 24, PageSize: 10, MaxPageSize: 10}
+
+	idx, err := Open(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idx.Add(testEntry("01", time.Hour, "Let's Encrypt", "example.com"))
+
+	if err := idx.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries are dropped after closing the index
+	idx.Add(testEntry("02", time.Hour, "Let's Encrypt", "example.com"))
+
+	idx, err = Open(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	result, err := idx.Search(Query{Domain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := serials(t, result); !slices.Equal(got, []string{"01"}) {
+		t.Errorf("want queued entry to be written on close, got %v", got)
+	}
+
+	if got := idx.entries.Load(); got != 1 {
+		t.Errorf("want 1 entry after reopening, got %d", got)
+	}
+}
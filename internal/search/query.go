@@ -0,0 +1,274 @@
+package search
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/net/idna"
+)
+
+var (
+	ErrInvalidQuery  = errors.New("invalid search query")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrQueryTooBroad = errors.New("search query matches too many entries")
+)
+
+// maxSuffixCandidates is the maximum number of index keys a domain suffix search may read. The entries of several
+// domains aren't ordered by time, so they are collected and sorted before paging through them.
+const maxSuffixCandidates = 100_000
+
+// maxEntryKey is greater than the keys of all entries.
+var maxEntryKey = bytes.Repeat([]byte{0xff}, entryKeyLength)
+
+// Query contains the search criteria. All criteria that are set must match.
+type Query struct {
+	// Domain is matched exactly. A leading "*." matches the domain and all its subdomains.
+	Domain string
+	// SHA256 is the fingerprint of the certificate, with or without colons.
+	SHA256 string
+	// Serial is the hex encoded serial number of the certificate, with or without colons.
+	Serial string
+	// Issuer is matched case-insensitively against the common name and the organization of the issuer.
+	Issuer string
+	// From and To restrict the time the server saw the certificates. Zero values are unbounded.
+	From time.Time
+	To   time.Time
+	// Limit is the number of results per page. It defaults to the configured page size.
+	Limit int
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+}
+
+// Result is a page of lite entries, ordered from newest to oldest.
+type Result struct {
+	Entries []json.RawMessage `json:"results"`
+	// NextCursor is set if there are more results. It's passed as the cursor to fetch the next page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// criteria is the normalized representation of a query.
+type criteria struct {
+	// terms must all be contained in the entry. The first term is the most selective one.
+	terms [][]byte
+	// suffix is set to the reversed domain for domain suffix searches.
+	suffix string
+	// lower and upper are the bounds of the entry keys. lower is inclusive and upper exclusive.
+	lower []byte
+	upper []byte
+	limit int
+}
+
+// Search returns a page of the entries matching the query.
+func (idx *Index) Search(query Query) (Result, error) {
+	crit, err := idx.parseQuery(query)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Entries: make([]json.RawMessage, 0, crit.limit)}
+
+	err = idx.db.View(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		var lastKey []byte
+
+		// collect adds the entry to the result if it matches all criteria. It returns false once the page is full
+		// and another matching entry exists.
+		collect := func(key []byte) (bool, error) {
+			value := entries.Get(key)
+			if value == nil {
+				return true, nil
+			}
+
+			var stored storedEntry
+			if err := cbor.Unmarshal(value, &stored); err != nil {
+				return false, fmt.Errorf("could not decode entry: %w", err)
+			}
+
+			if !crit.matches(&stored) {
+				return true, nil
+			}
+
+			if len(result.Entries) == crit.limit {
+				result.NextCursor = hex.EncodeToString(lastKey)
+				return false, nil
+			}
+
+			result.Entries = append(result.Entries, stored.JSON)
+			lastKey = slices.Clone(key)
+
+			return true, nil
+		}
+
+		switch {
+		case len(crit.terms) > 0:
+			prefix := append(slices.Clone(crit.terms[0]), 0)
+			return reverseScan(tx.Bucket(indexBucket).Cursor(), prefix, crit.lower, crit.upper, collect)
+		case crit.suffix != "":
+			keys, err := crit.suffixCandidates(tx.Bucket(indexBucket).Cursor())
+			if err != nil {
+				return err
+			}
+
+			for _, key := range keys {
+				if ok, err := collect(key); !ok || err != nil {
+					return err
+				}
+			}
+
+			return nil
+		default:
+			return reverseScan(entries.Cursor(), nil, crit.lower, crit.upper, collect)
+		}
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// parseQuery normalizes the query like the terms of the entries.
+func (idx *Index) parseQuery(query Query) (criteria, error) {
+	crit := criteria{upper: maxEntryKey, limit: idx.conf.PageSize}
+
+	if query.Limit > 0 {
+		crit.limit = min(query.Limit, idx.conf.MaxPageSize)
+	}
+
+	if query.SHA256 != "" {
+		crit.terms = append(crit.terms, append([]byte{termSHA256}, normalizeHex(query.SHA256)...))
+	}
+
+	if query.Serial != "" {
+		crit.terms = append(crit.terms, append([]byte{termSerial}, normalizeSerial(query.Serial)...))
+	}
+
+	if query.Domain != "" {
+		domain, wildcard := strings.CutPrefix(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(query.Domain)), "."), "*.")
+
+		// Domains are stored as found in the certificates, i.e. punycode encoded
+		domain, err := idna.ToASCII(domain)
+		if err != nil || domain == "" {
+			return criteria{}, fmt.Errorf("%w: invalid domain '%s'", ErrInvalidQuery, query.Domain)
+		}
+
+		if wildcard {
+			crit.suffix = reverseDomain(domain)
+		} else {
+			crit.terms = append(crit.terms, append([]byte{termDomain}, reverseDomain(domain)...))
+		}
+	}
+
+	if query.Issuer != "" {
+		crit.terms = append(crit.terms, append([]byte{termIssuer}, normalizeIssuer(query.Issuer)...))
+	}
+
+	if !query.From.IsZero() {
+		crit.lower = entryKey(query.From, 0)
+	}
+
+	if !query.To.IsZero() {
+		crit.upper = entryKey(query.To.Add(time.Nanosecond), 0)
+	}
+
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return criteria{}, fmt.Errorf("%w: the end of the time range is before its start", ErrInvalidQuery)
+	}
+
+	if query.Cursor != "" {
+		cursor, err := hex.DecodeString(query.Cursor)
+		if err != nil || len(cursor) != entryKeyLength {
+			return criteria{}, ErrInvalidCursor
+		}
+
+		if bytes.Compare(cursor, crit.upper) < 0 {
+			crit.upper = cursor
+		}
+	}
+
+	return crit, nil
+}
+
+// matches returns true if the stored entry contains all terms and, for suffix searches, a matching domain.
+func (crit *criteria) matches(stored *storedEntry) bool {
+	for _, term := range crit.terms {
+		if !slices.ContainsFunc(stored.Terms, func(t []byte) bool { return bytes.Equal(t, term) }) {
+			return false
+		}
+	}
+
+	if crit.suffix == "" {
+		return true
+	}
+
+	return slices.ContainsFunc(stored.Terms, func(t []byte) bool {
+		if len(t) == 0 || t[0] != termDomain {
+			return false
+		}
+
+		domain := string(t[1:])
+
+		return domain == crit.suffix || strings.HasPrefix(domain, crit.suffix+".")
+	})
+}
+
+// suffixCandidates returns the keys of the entries containing the domain or one of its subdomains within the bounds,
+// from newest to oldest.
+func (crit *criteria) suffixCandidates(c *bolt.Cursor) ([][]byte, error) {
+	var keys [][]byte
+
+	exact := append(append([]byte{termDomain}, crit.suffix...), 0)
+	subdomains := append(append([]byte{termDomain}, crit.suffix...), '.')
+
+	for _, prefix := range [][]byte{exact, subdomains} {
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			key := k[len(k)-entryKeyLength:]
+			if (crit.lower != nil && bytes.Compare(key, crit.lower) < 0) || bytes.Compare(key, crit.upper) >= 0 {
+				continue
+			}
+
+			if len(keys) == maxSuffixCandidates {
+				return nil, ErrQueryTooBroad
+			}
+
+			keys = append(keys, slices.Clone(key))
+		}
+	}
+
+	// Sort from newest to oldest and remove the entries found via several domains
+	slices.SortFunc(keys, func(a, b []byte) int { return bytes.Compare(b, a) })
+
+	return slices.CompactFunc(keys, bytes.Equal), nil
+}
+
+// reverseScan calls fn with the entry keys of the keys starting with the prefix, from the upper bound (exclusive)
+// down to the lower bound (inclusive), until fn returns false or an error.
+func reverseScan(c *bolt.Cursor, prefix, lower, upper []byte, fn func(key []byte) (bool, error)) error {
+	k, _ := c.Seek(append(slices.Clone(prefix), upper...))
+	if k == nil {
+		k, _ = c.Last()
+	} else {
+		k, _ = c.Prev()
+	}
+
+	for ; k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Prev() {
+		key := k[len(prefix):]
+		if lower != nil && bytes.Compare(key, lower) < 0 {
+			return nil
+		}
+
+		if ok, err := fn(key); !ok || err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
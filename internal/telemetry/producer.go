@@ -0,0 +1,191 @@
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+)
+
+// prometheusProducer exports the metrics of the Prometheus endpoint via OTLP, so both stay consistent without
+// registering every metric twice. Series following the Prometheus naming conventions for counters and histograms
+// (_total, _bucket, _sum, _count) are exported as cumulative sums, all others as gauges.
+type prometheusProducer struct {
+	startTime time.Time
+}
+
+func newPrometheusProducer() *prometheusProducer {
+	return &prometheusProducer{startTime: time.Now()}
+}
+
+// Produce converts the current values of all registered metrics to OpenTelemetry metric data.
+func (p *prometheusProducer) Produce(_ context.Context) ([]metricdata.ScopeMetrics, error) {
+	var buf bytes.Buffer
+	metrics.Prometheus.Write(&buf, false)
+
+	now := time.Now()
+	sums := make(map[string][]metricdata.DataPoint[float64])
+	gauges := make(map[string][]metricdata.DataPoint[float64])
+
+	var names []string
+
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		name, attrs, value, ok := parsePrometheusLine(scanner.Text())
+		if !ok {
+			continue
+		}
+
+		if _, sum := sums[name]; !sum {
+			if _, gauge := gauges[name]; !gauge {
+				names = append(names, name)
+			}
+		}
+
+		dataPoint := metricdata.DataPoint[float64]{
+			Attributes: attribute.NewSet(attrs...),
+			StartTime:  p.startTime,
+			Time:       now,
+			Value:      value,
+		}
+
+		if isCumulative(name) {
+			sums[name] = append(sums[name], dataPoint)
+		} else {
+			gauges[name] = append(gauges[name], dataPoint)
+		}
+	}
+
+	scopeMetrics := metricdata.ScopeMetrics{Scope: instrumentation.Scope{Name: ScopeName}}
+
+	for _, name := range names {
+		if dataPoints, ok := sums[name]; ok {
+			scopeMetrics.Metrics = append(scopeMetrics.Metrics, metricdata.Metrics{
+				Name: name,
+				Data: metricdata.Sum[float64]{DataPoints: dataPoints, Temporality: metricdata.CumulativeTemporality, IsMonotonic: true},
+			})
+
+			continue
+		}
+
+		scopeMetrics.Metrics = append(scopeMetrics.Metrics, metricdata.Metrics{
+			Name: name,
+			Data: metricdata.Gauge[float64]{DataPoints: gauges[name]},
+		})
+	}
+
+	return []metricdata.ScopeMetrics{scopeMetrics}, nil
+}
+
+// isCumulative returns true if the metric name follows the Prometheus naming conventions for counters.
+func isCumulative(name string) bool {
+	for _, suffix := range []string{"_total", "_bucket", "_sum", "_count"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parsePrometheusLine parses a single sample in the Prometheus text format, e.g. `name{label="value"} 1`.
+// It returns false for comments, empty lines and malformed samples.
+func parsePrometheusLine(line string) (string, []attribute.KeyValue, float64, bool) {
+	if line == "" || line[0] == '#' {
+		return "", nil, 0, false
+	}
+
+	nameEnd := strings.IndexAny(line, "{ ")
+	if nameEnd <= 0 {
+		return "", nil, 0, false
+	}
+
+	name, rest := line[:nameEnd], line[nameEnd:]
+
+	var attrs []attribute.KeyValue
+
+	if rest[0] == '{' {
+		var ok bool
+
+		attrs, rest, ok = parsePrometheusLabels(rest[1:])
+		if !ok {
+			return "", nil, 0, false
+		}
+	}
+
+	// The value may be followed by a timestamp
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, false
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, 0, false
+	}
+
+	return name, attrs, value, true
+}
+
+// parsePrometheusLabels parses the labels following the opening brace up to the closing brace.
+// It returns the labels and the remainder of the line after the closing brace.
+func parsePrometheusLabels(s string) ([]attribute.KeyValue, string, bool) {
+	var attrs []attribute.KeyValue
+
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if s == "" {
+			return nil, "", false
+		}
+
+		if s[0] == '}' {
+			return attrs, s[1:], true
+		}
+
+		keyEnd := strings.Index(s, "=\"")
+		if keyEnd <= 0 {
+			return nil, "", false
+		}
+
+		key := s[:keyEnd]
+		s = s[keyEnd+2:]
+
+		var value strings.Builder
+
+		closed := false
+
+		for i := 0; i < len(s); i++ {
+			switch {
+			case s[i] == '\\' && i+1 < len(s):
+				i++
+				if s[i] == 'n' {
+					value.WriteByte('\n')
+				} else {
+					value.WriteByte(s[i])
+				}
+			case s[i] == '"':
+				s, closed = s[i+1:], true
+			default:
+				value.WriteByte(s[i])
+			}
+
+			if closed {
+				break
+			}
+		}
+
+		if !closed {
+			return nil, "", false
+		}
+
+		attrs = append(attrs, attribute.String(key, value.String()))
+	}
+}
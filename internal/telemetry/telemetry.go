@@ -0,0 +1,126 @@
+package telemetry
+
+// The telemetry package exports traces and the existing metrics to an OpenTelemetry collector via OTLP/HTTP.
+// Until Setup is called, the global no-op providers are used, so spans created via Tracer cost next to nothing.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+// ScopeName is the name of the instrumentation scope used for all spans and metrics.
+const ScopeName = "github.com/d-Rickyy-b/certstream-server-go"
+
+// Attributes added to the spans of the CT watcher.
+const (
+	AttrLogURL      = attribute.Key("ct.log.url")
+	AttrLogOperator = attribute.Key("ct.log.operator")
+	AttrEntryIndex  = attribute.Key("ct.entry.index")
+)
+
+// Telemetry holds the providers exporting traces and metrics to the collector.
+type Telemetry struct {
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+}
+
+// Setup creates the OTLP exporters according to the config and registers the tracer provider globally.
+// The metrics known from the Prometheus endpoint are exported every MetricsInterval seconds.
+func Setup(ctx context.Context, conf config.OpenTelemetryConfig) (*Telemetry, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(conf.ServiceName),
+		semconv.ServiceVersion(config.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenTelemetry resource: %w", err)
+	}
+
+	traceOptions := []otlptracehttp.Option{otlptracehttp.WithEndpoint(conf.Endpoint), otlptracehttp.WithHeaders(conf.Headers)}
+	if conf.Insecure {
+		traceOptions = append(traceOptions, otlptracehttp.WithInsecure())
+	}
+
+	traceExporter, err := otlptracehttp.New(ctx, traceOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	t := &Telemetry{
+		tracerProvider: sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(traceExporter),
+			sdktrace.WithResource(res),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.TraceSampleRatio))),
+		),
+	}
+	otel.SetTracerProvider(t.tracerProvider)
+
+	if conf.MetricsInterval > 0 {
+		metricOptions := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(conf.Endpoint), otlpmetrichttp.WithHeaders(conf.Headers)}
+		if conf.Insecure {
+			metricOptions = append(metricOptions, otlpmetrichttp.WithInsecure())
+		}
+
+		metricExporter, err := otlpmetrichttp.New(ctx, metricOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+		}
+
+		reader := sdkmetric.NewPeriodicReader(metricExporter,
+			sdkmetric.WithInterval(time.Duration(conf.MetricsInterval)*time.Second),
+			sdkmetric.WithProducer(newPrometheusProducer()),
+		)
+		t.meterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(res))
+	}
+
+	return t, nil
+}
+
+// Shutdown flushes the pending spans and metrics and stops the exporters.
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	err := t.tracerProvider.Shutdown(ctx)
+
+	if t.meterProvider != nil {
+		err = errors.Join(err, t.meterProvider.Shutdown(ctx))
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to shut down OpenTelemetry: %w", err)
+	}
+
+	return nil
+}
+
+// Tracer returns the tracer used to instrument the certstream pipeline.
+func Tracer() trace.Tracer {
+	return otel.Tracer(ScopeName)
+}
+
+// LogAttributes returns the span start option adding the URL and operator of a CT log to a span.
+func LogAttributes(url, operator string) trace.SpanStartEventOption {
+	return trace.WithAttributes(AttrLogURL.String(url), AttrLogOperator.String(operator))
+}
+
+// EndSpan records the given error, if any, and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,129 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+	metricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+)
+
+// collector is a stand-in for an OpenTelemetry collector that stores the received span and metric names.
+type collector struct {
+	mu      sync.Mutex
+	spans   []string
+	metrics []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/traces":
+		var req tracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &req); err == nil {
+			for _, resourceSpans := range req.GetResourceSpans() {
+				for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+					for _, span := range scopeSpans.GetSpans() {
+						c.spans = append(c.spans, span.GetName())
+					}
+				}
+			}
+		}
+	case "/v1/metrics":
+		var req metricspb.ExportMetricsServiceRequest
+		if err := proto.Unmarshal(body, &req); err == nil {
+			for _, resourceMetrics := range req.GetResourceMetrics() {
+				for _, scopeMetrics := range resourceMetrics.GetScopeMetrics() {
+					for _, metric := range scopeMetrics.GetMetrics() {
+						c.metrics = append(c.metrics, metric.GetName())
+					}
+				}
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestSetup_ExportsSpansAndMetrics(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	stub := &collector{}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	metrics.Prometheus.IncWorkerRestart("Test Operator", "ct.example.com/log")
+
+	telemetry, err := Setup(context.Background(), config.OpenTelemetryConfig{
+		Endpoint:         strings.TrimPrefix(server.URL, "http://"),
+		Insecure:         true,
+		ServiceName:      "certstream-server-go",
+		TraceSampleRatio: 1,
+		MetricsInterval:  60,
+	})
+	if err != nil {
+		t.Fatalf("could not set up telemetry: %v", err)
+	}
+
+	_, span := Tracer().Start(context.Background(), "parse entry", LogAttributes("ct.example.com/log", "Test Operator"))
+	EndSpan(span, nil)
+
+	if err := telemetry.Shutdown(context.Background()); err != nil {
+		t.Fatalf("could not shut down telemetry: %v", err)
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	if len(stub.spans) != 1 || stub.spans[0] != "parse entry" {
+		t.Errorf("want the span 'parse entry' to be exported, got %v", stub.spans)
+	}
+
+	found := false
+
+	for _, name := range stub.metrics {
+		if name == "certstreamservergo_worker_restarts_total" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("want the metric certstreamservergo_worker_restarts_total to be exported, got %v", stub.metrics)
+	}
+}
+
+func TestParsePrometheusLine(t *testing.T) {
+	name, attrs, value, ok := parsePrometheusLine(`certstreamservergo_log_errors_total{url="ct.example.com/log",operator="Op \"A\", B",type="dns"} 3`)
+	if !ok || name != "certstreamservergo_log_errors_total" || value != 3 {
+		t.Fatalf("unexpected result: %q %v %v %v", name, attrs, value, ok)
+	}
+
+	if len(attrs) != 3 || attrs[1].Value.AsString() != `Op "A", B` || attrs[2].Value.AsString() != "dns" {
+		t.Errorf("unexpected labels: %v", attrs)
+	}
+
+	if name, _, value, ok := parsePrometheusLine("certstreamservergo_certificates_total 1.5e+06"); !ok || name != "certstreamservergo_certificates_total" || value != 1.5e6 {
+		t.Errorf("unexpected result for a sample without labels: %q %v %v", name, value, ok)
+	}
+
+	for _, line := range []string{"", "# TYPE foo counter", `foo{bar="baz} 1`, "foo"} {
+		if _, _, _, ok := parsePrometheusLine(line); ok {
+			t.Errorf("want %q to be rejected", line)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package web
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+)
+
+// Reasons for rejecting a connection. They are used as label for the rejected connections metric.
+const (
+	rejectReasonMaxClients   = "max_clients"
+	rejectReasonIPLimit      = "ip_limit"
+	rejectReasonKeyLimit     = "key_limit"
+	rejectReasonRateLimit    = "rate_limit"
+	rejectReasonUnauthorized = "unauthorized"
+	rejectReasonForbidden    = "forbidden"
+)
+
+var admission *admissionController
+
+// tokenBucket is a simple token bucket rate limiter.
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// admissionController limits the total number of clients, the number of clients per IP address
+// and the rate at which a single IP address may open new connections.
+type admissionController struct {
+	limits config.LimitsConfig
+
+	mu      sync.Mutex
+	total   int
+	perIP   map[string]int
+	buckets map[string]*tokenBucket
+}
+
+// newAdmissionController creates a new admissionController with the given limits.
+func newAdmissionController(limits config.LimitsConfig) *admissionController {
+	return &admissionController{
+		limits:  limits,
+		perIP:   make(map[string]int),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// admit checks whether a new connection from the given IP address is allowed and reserves a slot for it.
+// If the connection is rejected, the reason is returned.
+func (ac *admissionController) admit(ip string) (reason string, ok bool) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if !ac.allowRate(ip) {
+		return rejectReasonRateLimit, false
+	}
+
+	if ac.limits.MaxClients > 0 && ac.total >= ac.limits.MaxClients {
+		return rejectReasonMaxClients, false
+	}
+
+	if ac.limits.MaxClientsPerIP > 0 && ac.perIP[ip] >= ac.limits.MaxClientsPerIP {
+		return rejectReasonIPLimit, false
+	}
+
+	ac.total++
+	ac.perIP[ip]++
+
+	return "", true
+}
+
+// release frees a slot previously reserved with admit.
+func (ac *admissionController) release(ip string) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.total > 0 {
+		ac.total--
+	}
+
+	ac.perIP[ip]--
+	if ac.perIP[ip] <= 0 {
+		delete(ac.perIP, ip)
+	}
+}
+
+// allowRate takes a token from the IP's token bucket. It returns false if the bucket is empty.
+// The caller must hold the mutex.
+func (ac *admissionController) allowRate(ip string) bool {
+	if ac.limits.ConnectionRate <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	burst := float64(ac.limits.ConnectionBurst)
+
+	bucket, ok := ac.buckets[ip]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, lastSeen: now}
+		ac.buckets[ip] = bucket
+
+		// Buckets that are full again carry no information, so they can be dropped to keep the map small.
+		if len(ac.buckets) > 10000 {
+			ac.dropFullBuckets(now)
+		}
+	}
+
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	bucket.tokens = math.Min(burst, bucket.tokens+elapsed*ac.limits.ConnectionRate)
+	bucket.lastSeen = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+
+	return true
+}
+
+// dropFullBuckets removes all token buckets that would be refilled completely by now. The caller must hold the mutex.
+func (ac *admissionController) dropFullBuckets(now time.Time) {
+	burst := float64(ac.limits.ConnectionBurst)
+
+	for ip, bucket := range ac.buckets {
+		if bucket.tokens+now.Sub(bucket.lastSeen).Seconds()*ac.limits.ConnectionRate >= burst {
+			delete(ac.buckets, ip)
+		}
+	}
+}
+
+// remoteIP returns the IP address of the request's remote address without the port.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// The RealIP middleware sets the RemoteAddr without a port
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// admitClient checks the connection limits for the request. If the connection is rejected,
+// a 429 response is written and the rejection is counted in the metrics.
+func admitClient(w http.ResponseWriter, r *http.Request) bool {
+	if admission == nil {
+		return true
+	}
+
+	reason, ok := admission.admit(remoteIP(r))
+	if ok {
+		return true
+	}
+
+	logger.Info("Rejecting client", "remote_addr", r.RemoteAddr, "reason", reason)
+	rejectConnection(reason)
+
+	if reason == rejectReasonRateLimit && admission.limits.ConnectionRate > 0 {
+		retryAfter := math.Ceil(1 / admission.limits.ConnectionRate)
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter)))
+	}
+
+	http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+
+	return false
+}
+
+// releaseClient frees the connection slot of the given IP address.
+func releaseClient(ip string) {
+	if admission != nil {
+		admission.release(ip)
+	}
+}
+
+// rejectConnection counts a rejected connection with the given reason in the metrics.
+func rejectConnection(reason string) {
+	metrics.Prometheus.IncRejectedConnection(reason)
+}
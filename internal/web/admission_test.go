@@ -0,0 +1,85 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+func TestAdmissionController_MaxClients(t *testing.T) {
+	ac := newAdmissionController(config.LimitsConfig{MaxClients: 2})
+
+	if _, ok := ac.admit("10.0.0.1"); !ok {
+		t.Fatalf("first client should be admitted")
+	}
+	if _, ok := ac.admit("10.0.0.2"); !ok {
+		t.Fatalf("second client should be admitted")
+	}
+	if reason, ok := ac.admit("10.0.0.3"); ok || reason != rejectReasonMaxClients {
+		t.Errorf("third client: want rejection %q, got ok=%v reason=%q", rejectReasonMaxClients, ok, reason)
+	}
+
+	ac.release("10.0.0.1")
+
+	if _, ok := ac.admit("10.0.0.3"); !ok {
+		t.Errorf("client should be admitted after a slot was released")
+	}
+}
+
+func TestAdmissionController_MaxClientsPerIP(t *testing.T) {
+	ac := newAdmissionController(config.LimitsConfig{MaxClientsPerIP: 1})
+
+	if _, ok := ac.admit("10.0.0.1"); !ok {
+		t.Fatalf("first client should be admitted")
+	}
+	if reason, ok := ac.admit("10.0.0.1"); ok || reason != rejectReasonIPLimit {
+		t.Errorf("second client of same IP: want rejection %q, got ok=%v reason=%q", rejectReasonIPLimit, ok, reason)
+	}
+	if _, ok := ac.admit("10.0.0.2"); !ok {
+		t.Errorf("client of another IP should be admitted")
+	}
+
+	ac.release("10.0.0.1")
+
+	if len(ac.perIP) != 1 {
+		t.Errorf("expected released IP to be removed from the map, got %v", ac.perIP)
+	}
+}
+
+func TestAdmissionController_ConnectionRate(t *testing.T) {
+	ac := newAdmissionController(config.LimitsConfig{ConnectionRate: 0.001, ConnectionBurst: 3})
+
+	for i := range 3 {
+		if _, ok := ac.admit("10.0.0.1"); !ok {
+			t.Fatalf("connection %d should be within the burst", i)
+		}
+	}
+
+	if reason, ok := ac.admit("10.0.0.1"); ok || reason != rejectReasonRateLimit {
+		t.Errorf("want rejection %q, got ok=%v reason=%q", rejectReasonRateLimit, ok, reason)
+	}
+	if _, ok := ac.admit("10.0.0.2"); !ok {
+		t.Errorf("other IPs should not be rate limited")
+	}
+}
+
+func TestAdmitClient_Returns429(t *testing.T) {
+	admission = newAdmissionController(config.LimitsConfig{MaxClients: 1})
+	t.Cleanup(func() { admission = nil })
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if !admitClient(httptest.NewRecorder(), r) {
+		t.Fatalf("first client should be admitted")
+	}
+
+	w := httptest.NewRecorder()
+	if admitClient(w, r) {
+		t.Fatalf("second client should be rejected")
+	}
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("want status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+}
@@ -0,0 +1,220 @@
+package web
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+// apiKeySubprotocolPrefix is the prefix of the websocket subprotocol that can be used to transmit the API key.
+// Browsers can't set custom headers for websocket connections, so they can use e.g. "apikey.<key>" as subprotocol.
+const apiKeySubprotocolPrefix = "apikey."
+
+var apiKeys *apiKeyStore
+
+// apiKey represents a single consumer of the websocket server and keeps track of its active connections.
+type apiKey struct {
+	name           string
+	subTypes       []SubscriptionType
+	maxConnections int
+
+	mu          sync.Mutex
+	connections int
+}
+
+// allows returns true if the key may access the endpoint of the given subscription type.
+func (k *apiKey) allows(subType SubscriptionType) bool {
+	return len(k.subTypes) == 0 || slices.Contains(k.subTypes, subType)
+}
+
+// acquire reserves a connection slot for the key. It returns false if the maximum number of connections is reached.
+func (k *apiKey) acquire() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.maxConnections > 0 && k.connections >= k.maxConnections {
+		return false
+	}
+
+	k.connections++
+
+	return true
+}
+
+// release frees a connection slot previously reserved with acquire.
+func (k *apiKey) release() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.connections > 0 {
+		k.connections--
+	}
+}
+
+// apiKeyStore holds all configured API keys. Keys are stored by their SHA256 hash.
+type apiKeyStore struct {
+	keys map[[sha256.Size]byte]*apiKey
+}
+
+// newAPIKeyStore creates a new apiKeyStore from the configured API keys.
+// Keys without their own connection limit get the limit defaultMaxConnections.
+func newAPIKeyStore(keys []config.APIKey, defaultMaxConnections int) *apiKeyStore {
+	store := &apiKeyStore{keys: make(map[[sha256.Size]byte]*apiKey, len(keys))}
+
+	for _, key := range keys {
+		var subTypes []SubscriptionType
+
+		for _, endpoint := range key.Endpoints {
+			switch endpoint {
+			case "full":
+				subTypes = append(subTypes, SubTypeFull)
+			case "lite":
+				subTypes = append(subTypes, SubTypeLite)
+			case "domains":
+				subTypes = append(subTypes, SubTypeDomain)
+			case "new_domains":
+				subTypes = append(subTypes, SubTypeNewDomains)
+			}
+		}
+
+		maxConnections := key.MaxConnections
+		if maxConnections == 0 {
+			maxConnections = defaultMaxConnections
+		}
+
+		store.keys[sha256.Sum256([]byte(key.Key))] = &apiKey{
+			name:           key.Name,
+			subTypes:       subTypes,
+			maxConnections: maxConnections,
+		}
+	}
+
+	return store
+}
+
+// lookup returns the apiKey matching the given key or nil if the key is unknown.
+func (s *apiKeyStore) lookup(key string) *apiKey {
+	return s.keys[sha256.Sum256([]byte(key))]
+}
+
+// apiKeyFromRequest extracts the API key from the request. The key is searched for in the "X-API-Key" header,
+// the "api_key" query parameter and the websocket subprotocols, in this order.
+// If the key was sent as subprotocol, the subprotocol is returned as well, since it must be echoed by the server.
+func apiKeyFromRequest(r *http.Request) (key, subprotocol string) {
+	if key = r.Header.Get("X-API-Key"); key != "" {
+		return key, ""
+	}
+
+	if key = r.URL.Query().Get("api_key"); key != "" {
+		return key, ""
+	}
+
+	for _, protocol := range strings.Split(r.Header.Get("Sec-WebSocket-Protocol"), ",") {
+		protocol = strings.TrimSpace(protocol)
+		if strings.HasPrefix(protocol, apiKeySubprotocolPrefix) {
+			return strings.TrimPrefix(protocol, apiKeySubprotocolPrefix), protocol
+		}
+	}
+
+	return "", ""
+}
+
+// clientAuth contains the result of a successful authentication of a client.
+type clientAuth struct {
+	// name replaces the remote address of the client. It's empty for unauthenticated clients.
+	name string
+	// key is the API key the client authenticated with, if any.
+	key *apiKey
+	// subprotocol is the websocket subprotocol that must be echoed to the client.
+	subprotocol string
+	// filter restricts the entries sent to the client, if set.
+	filter *entryFilter
+	// expiresAt is the time at which the client's credentials expire. The zero value means no expiry.
+	expiresAt time.Time
+}
+
+// authenticate checks whether the request carries valid credentials for the given subscription type.
+// Clients can either authenticate with a bearer token, which was already validated by the jwtAuth middleware,
+// or an API key, for which a connection slot is reserved. If the request is rejected, an error is written to
+// the response and ok is false. If authentication is disabled, an empty clientAuth and ok = true are returned.
+func authenticate(w http.ResponseWriter, r *http.Request, subType SubscriptionType) (auth clientAuth, ok bool) {
+	rawKey, subprotocol := apiKeyFromRequest(r)
+
+	auth, reason := checkCredentials(tokenClaimsFromContext(r.Context()), rawKey, subType, r.RemoteAddr, r.URL.Path)
+	switch reason {
+	case "":
+		if auth.key != nil {
+			auth.subprotocol = subprotocol
+		}
+
+		return auth, true
+	case rejectReasonForbidden:
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case rejectReasonKeyLimit:
+		http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	default:
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}
+
+	return clientAuth{}, false
+}
+
+// checkCredentials checks the claims of a validated token or the given API key for access to the given
+// subscription type. If the client is rejected, the reason is returned and the rejection is counted in the metrics.
+// remoteAddr and resource are only used for logging.
+func checkCredentials(claims *tokenClaims, rawKey string, subType SubscriptionType, remoteAddr, resource string) (clientAuth, string) {
+	if claims != nil {
+		if !claims.allows(subType) {
+			logger.Info("Token does not grant access", "subject", claims.subject, "resource", resource)
+			rejectConnection(rejectReasonForbidden)
+
+			return clientAuth{}, rejectReasonForbidden
+		}
+
+		return clientAuth{name: claims.subject, filter: claims.filter, expiresAt: claims.expiresAt}, ""
+	}
+
+	if apiKeys == nil {
+		if tokenVerifier != nil {
+			// Token authentication is enabled, but the client did not provide a token
+			rejectConnection(rejectReasonUnauthorized)
+			return clientAuth{}, rejectReasonUnauthorized
+		}
+
+		return clientAuth{}, ""
+	}
+
+	if rawKey == "" {
+		rejectConnection(rejectReasonUnauthorized)
+		return clientAuth{}, rejectReasonUnauthorized
+	}
+
+	key := apiKeys.lookup(rawKey)
+	if key == nil {
+		logger.Info("Rejecting client with invalid API key", "remote_addr", remoteAddr)
+		rejectConnection(rejectReasonUnauthorized)
+
+		return clientAuth{}, rejectReasonUnauthorized
+	}
+
+	if !key.allows(subType) {
+		logger.Info("API key is not allowed to access resource", "api_key", key.name, "resource", resource)
+		rejectConnection(rejectReasonForbidden)
+
+		return clientAuth{}, rejectReasonForbidden
+	}
+
+	if !key.acquire() {
+		logger.Info("API key reached its maximum of connections", "api_key", key.name, "max_connections", key.maxConnections)
+		rejectConnection(rejectReasonKeyLimit)
+
+		return clientAuth{}, rejectReasonKeyLimit
+	}
+
+	return clientAuth{name: key.name, key: key}, ""
+}
@@ -0,0 +1,80 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+func TestAPIKeyFromRequest(t *testing.T) {
+	cases := []struct {
+		name            string
+		url             string
+		header          http.Header
+		wantKey         string
+		wantSubprotocol string
+	}{
+		{"header", "/", http.Header{"X-Api-Key": {"k1"}}, "k1", ""},
+		{"query", "/?api_key=k2", nil, "k2", ""},
+		{"subprotocol", "/", http.Header{"Sec-Websocket-Protocol": {"certstream, apikey.k3"}}, "k3", "apikey.k3"},
+		{"none", "/", nil, "", ""},
+	}
+
+	for _, testcase := range cases {
+		t.Run(testcase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, testcase.url, nil)
+			for k, v := range testcase.header {
+				r.Header[k] = v
+			}
+
+			key, subprotocol := apiKeyFromRequest(r)
+			if key != testcase.wantKey || subprotocol != testcase.wantSubprotocol {
+				t.Errorf("want (%q, %q), got (%q, %q)", testcase.wantKey, testcase.wantSubprotocol, key, subprotocol)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	apiKeys = newAPIKeyStore([]config.APIKey{
+		{Key: "lite-only", Name: "partner", Endpoints: []string{"lite"}, MaxConnections: 1},
+	}, 0)
+	t.Cleanup(func() { apiKeys = nil })
+
+	authRequest := func(key string, subType SubscriptionType) (*apiKey, int) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if key != "" {
+			r.Header.Set("X-API-Key", key)
+		}
+
+		w := httptest.NewRecorder()
+		auth, _ := authenticate(w, r, subType)
+
+		return auth.key, w.Code
+	}
+
+	if _, code := authRequest("", SubTypeLite); code != http.StatusUnauthorized {
+		t.Errorf("missing key: want %d, got %d", http.StatusUnauthorized, code)
+	}
+	if _, code := authRequest("wrong", SubTypeLite); code != http.StatusUnauthorized {
+		t.Errorf("invalid key: want %d, got %d", http.StatusUnauthorized, code)
+	}
+	if _, code := authRequest("lite-only", SubTypeFull); code != http.StatusForbidden {
+		t.Errorf("forbidden endpoint: want %d, got %d", http.StatusForbidden, code)
+	}
+
+	key, _ := authRequest("lite-only", SubTypeLite)
+	if key == nil || key.name != "partner" {
+		t.Fatalf("valid key: expected key 'partner', got %v", key)
+	}
+	if _, code := authRequest("lite-only", SubTypeLite); code != http.StatusTooManyRequests {
+		t.Errorf("connection limit: want %d, got %d", http.StatusTooManyRequests, code)
+	}
+
+	key.release()
+	if k, _ := authRequest("lite-only", SubTypeLite); k == nil {
+		t.Errorf("expected connection to be accepted after releasing the slot")
+	}
+}
@@ -0,0 +1,161 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+// batch collects messages for a client that requested multiple entries per websocket frame.
+// A batch is sent as a JSON or CBOR array as soon as it contains size messages or interval has passed since
+// the first message was added, whichever comes first.
+type batch struct {
+	size     int
+	interval time.Duration
+	pending  [][]byte
+	timer    *time.Timer
+	// buf is reused for building the frames.
+	buf []byte
+}
+
+// newBatch creates an empty batch. The timer is only started when the first message is added.
+func newBatch(size int, interval time.Duration) *batch {
+	timer := time.NewTimer(interval)
+	timer.Stop()
+
+	return &batch{
+		size:     size,
+		interval: interval,
+		pending:  make([][]byte, 0, size),
+		timer:    timer,
+	}
+}
+
+// batchFor returns the batch requested by the client via the "batch_size" and "batch_interval" (in milliseconds)
+// query parameters or nil if the client didn't request batching or batching is disabled.
+// Missing or too large values are replaced by the configured maximum.
+func batchFor(r *http.Request) *batch {
+	conf := config.AppConfig.Webserver.Batching
+	query := r.URL.Query()
+
+	if !conf.Enabled || (!query.Has("batch_size") && !query.Has("batch_interval")) {
+		return nil
+	}
+
+	size, sizeOk := batchParameter(query.Get("batch_size"), conf.MaxSize)
+	interval, intervalOk := batchParameter(query.Get("batch_interval"), conf.MaxInterval)
+
+	if !sizeOk || !intervalOk {
+		logger.Info("Client requested invalid batching parameters, sending entries individually", "remote_addr", r.RemoteAddr)
+		return nil
+	}
+
+	return newBatch(size, time.Duration(interval)*time.Millisecond)
+}
+
+// batchParameter parses a batching query parameter. Empty values default to maximum, larger values are capped.
+func batchParameter(value string, maximum int) (int, bool) {
+	if value == "" {
+		return maximum, true
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return 0, false
+	}
+
+	return min(parsed, maximum), true
+}
+
+// add appends the message to the batch and returns true if the batch is full and must be flushed.
+func (b *batch) add(message []byte) bool {
+	if len(b.pending) == 0 {
+		b.timer.Reset(b.interval)
+	}
+
+	b.pending = append(b.pending, message)
+
+	return len(b.pending) >= b.size
+}
+
+// timeout returns a channel that receives when a non-empty batch must be flushed.
+// It's nil for empty batches, so it can be used in a select statement unconditionally.
+func (b *batch) timeout() <-chan time.Time {
+	if b == nil || len(b.pending) == 0 {
+		return nil
+	}
+
+	return b.timer.C
+}
+
+// frame builds a single frame containing all pending messages and empties the batch. For CBOR clients the frame
+// is a CBOR array, otherwise a JSON array.
+// The returned slice is only valid until the next call.
+func (b *batch) frame(format WireFormat) []byte {
+	b.timer.Stop()
+
+	buf := b.buf[:0]
+
+	if format == FormatCBOR {
+		buf = appendCBORArrayHeader(buf, len(b.pending))
+		for _, message := range b.pending {
+			buf = append(buf, message...)
+		}
+	} else {
+		buf = append(buf, '[')
+
+		for i, message := range b.pending {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+
+			// Full and lite entries are terminated by a newline
+			buf = append(buf, bytes.TrimSuffix(message, []byte{'\n'})...)
+		}
+
+		buf = append(buf, ']')
+	}
+
+	clear(b.pending)
+	b.pending = b.pending[:0]
+	b.buf = buf
+
+	return buf
+}
+
+// appendCBORArrayHeader appends the header of a definite-length CBOR array (major type 4) with n elements.
+func appendCBORArrayHeader(buf []byte, n int) []byte {
+	const majorTypeArray = 0x80
+
+	switch {
+	case n < 24:
+		return append(buf, majorTypeArray|byte(n))
+	case n <= 0xff:
+		return append(buf, majorTypeArray|24, byte(n))
+	case n <= 0xffff:
+		return append(buf, majorTypeArray|25, byte(n>>8), byte(n))
+	default:
+		return append(buf, majorTypeArray|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+// send writes the message to the client or adds it to the client's batch, if batching was requested.
+func (c *client) send(message []byte, writeWait time.Duration) error {
+	if c.batch == nil {
+		return c.writeMessage(message, writeWait)
+	}
+
+	if c.batch.add(message) {
+		return c.flushBatch(writeWait)
+	}
+
+	return nil
+}
+
+// flushBatch writes all messages of the client's batch in a single frame.
+func (c *client) flushBatch(writeWait time.Duration) error {
+	return c.writeMessage(c.batch.frame(c.format), writeWait)
+}
@@ -0,0 +1,101 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+func TestBatchFor(t *testing.T) {
+	config.AppConfig.Webserver.Batching = config.BatchingConfig{Enabled: true, MaxSize: 100, MaxInterval: 1000}
+	t.Cleanup(func() { config.AppConfig.Webserver.Batching = config.BatchingConfig{} })
+
+	tests := []struct {
+		name         string
+		url          string
+		wantBatch    bool
+		wantSize     int
+		wantInterval time.Duration
+	}{
+		{"not requested", "/", false, 0, 0},
+		{"size and interval", "/?batch_size=10&batch_interval=250", true, 10, 250 * time.Millisecond},
+		{"size only", "/?batch_size=10", true, 10, time.Second},
+		{"capped", "/?batch_size=1000&batch_interval=5000", true, 100, time.Second},
+		{"invalid", "/?batch_size=-1", false, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := batchFor(httptest.NewRequest("GET", tt.url, nil))
+			if (b != nil) != tt.wantBatch {
+				t.Fatalf("want batch %v, got %v", tt.wantBatch, b != nil)
+			}
+
+			if b != nil && (b.size != tt.wantSize || b.interval != tt.wantInterval) {
+				t.Errorf("want (%d, %s), got (%d, %s)", tt.wantSize, tt.wantInterval, b.size, b.interval)
+			}
+		})
+	}
+
+	config.AppConfig.Webserver.Batching.Enabled = false
+	if b := batchFor(httptest.NewRequest("GET", "/?batch_size=10", nil)); b != nil {
+		t.Errorf("want no batch if batching is disabled")
+	}
+}
+
+func TestBatch_Frame(t *testing.T) {
+	b := newBatch(3, time.Minute)
+
+	if b.timeout() != nil {
+		t.Errorf("want no timeout for empty batch")
+	}
+
+	b.add([]byte("{\"a\":1}\n"))
+
+	if b.timeout() == nil {
+		t.Errorf("want timeout for non-empty batch")
+	}
+
+	if b.add([]byte(`{"b":2}`)) {
+		t.Errorf("want batch with 2 of 3 messages not to be full")
+	}
+
+	if !b.add([]byte(`{"c":3}`)) {
+		t.Errorf("want batch with 3 of 3 messages to be full")
+	}
+
+	var decoded []map[string]int
+	if err := json.Unmarshal(b.frame(FormatJSON), &decoded); err != nil {
+		t.Fatalf("frame is not valid JSON: %v", err)
+	}
+
+	if len(decoded) != 3 || decoded[2]["c"] != 3 {
+		t.Errorf("want 3 messages, got %v", decoded)
+	}
+
+	if b.timeout() != nil {
+		t.Errorf("want batch to be empty after building the frame")
+	}
+
+	// 30 messages require a one byte length after the CBOR array header. Integers below 24 are encoded as a single byte.
+	want := make([]int, 30)
+	for i := range want {
+		want[i] = i % 24
+		b.add(cbor.RawMessage{byte(want[i])})
+	}
+
+	var values []int
+	if err := cbor.Unmarshal(b.frame(FormatCBOR), &values); err != nil {
+		t.Fatalf("frame is not valid CBOR: %v", err)
+	}
+
+	if !slices.Equal(values, want) {
+		t.Errorf("want %v, got %v", want, values)
+	}
+}
@@ -1,63 +1,255 @@
 package web
 
 import (
-	"log"
-	"sync"
+	"runtime"
+	"slices"
+	"sync/atomic"
 
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
 )
 
+// shardBufferSize is the number of operations that can be queued for a single shard.
+const shardBufferSize = 100
+
+// managerOp is a request to the broadcaster to add or remove a client. If neither is set, the broadcaster
+// waits until all shards processed the previous operations. done is closed as soon as the request was handled.
+type managerOp struct {
+	add    *client
+	remove *client
+	done   chan struct{}
+}
+
+// BroadcastManager distributes the entries sent to its Broadcast channel to all registered clients.
+// The broadcaster goroutine is the only one modifying the client list and sequencing entries. The actual fan-out
+// to the clients is done by several shards, each with its own goroutine and share of the clients.
 type BroadcastManager struct {
-	Broadcast  chan models.Entry
-	clients    []*client
-	clientLock sync.RWMutex
+	Broadcast chan models.Entry
+	ops       chan managerOp
+	shards    []*broadcastShard
+	// clients is a copy-on-write snapshot of all registered clients. It's replaced by the broadcaster on every
+	// change, so readers never need to hold a lock.
+	clients atomic.Pointer[[]*client]
+	// subscribers is the number of clients per subscription. Entries are only encoded for the subscriptions
+	// that have clients. It's only accessed by the broadcaster.
+	subscribers map[subscription]int
+	// seq is the sequence number of the last broadcast entry. It's only modified by the broadcaster.
+	seq    uint64
+	replay *replayBuffer
+	// latest holds the most recent entries for the /latest.json endpoint, if enabled.
+	latest *replayBuffer
 }
 
 func NewBroadcastManager() *BroadcastManager {
-	bm := &BroadcastManager{}
+	bm := &BroadcastManager{
+		ops:         make(chan managerOp),
+		subscribers: make(map[subscription]int),
+	}
+	bm.clients.Store(&[]*client{})
+
 	metrics.Prometheus.RegisterGaugeMetricInt("certstreamservergo_clients_total{type=\"full\"}", bm.ClientFullCount)
 	metrics.Prometheus.RegisterGaugeMetricInt("certstreamservergo_clients_total{type=\"lite\"}", bm.ClientLiteCount)
 	metrics.Prometheus.RegisterGaugeMetricInt("certstreamservergo_clients_total{type=\"domain\"}", bm.ClientDomainsCount)
+	metrics.Prometheus.RegisterGaugeMetricInt("certstreamservergo_clients_total{type=\"new_domains\"}", bm.ClientNewDomainsCount)
 
 	return bm
 }
 
+// StartShards starts count broadcast shards. If count is 0, one shard per CPU is started.
+// It must be called before starting the broadcaster.
+func (bm *BroadcastManager) StartShards(count int) {
+	if count <= 0 {
+		count = runtime.NumCPU()
+	}
+
+	for range count {
+		shard := newBroadcastShard(shardBufferSize)
+		bm.shards = append(bm.shards, shard)
+
+		go shard.run()
+	}
+
+	logger.Info("Started broadcast shards", "count", count)
+}
+
+// EnableReplay enables the replay buffer holding the last size entries. If filePath is not empty,
+// previously stored entries are loaded from that file.
+func (bm *BroadcastManager) EnableReplay(size int, filePath string) {
+	bm.replay = newReplayBuffer(size)
+
+	if filePath == "" {
+		return
+	}
+
+	if err := bm.replay.load(filePath); err != nil {
+		logger.Error("Error loading replay buffer", "file", filePath, logging.KeyError, err)
+		return
+	}
+
+	// Continue numbering where the previous run stopped
+	bm.seq = bm.replay.lastSeq()
+}
+
+// EnableLatest keeps the last size entries for the /latest.json endpoint.
+// It must be called before starting the broadcaster.
+func (bm *BroadcastManager) EnableLatest(size int) {
+	bm.latest = newReplayBuffer(size)
+}
+
+// LatestEntries returns the newest n broadcast entries, ordered from newest to oldest.
+func (bm *BroadcastManager) LatestEntries(n int) []models.Entry {
+	if bm.latest == nil {
+		return nil
+	}
+
+	return bm.latest.latest(n)
+}
+
+// SaveReplayBuffer stores the current content of the replay buffer in the given file.
+func (bm *BroadcastManager) SaveReplayBuffer(filePath string) error {
+	if bm.replay == nil || filePath == "" {
+		return nil
+	}
+
+	return bm.replay.save(filePath)
+}
+
 // registerClient adds a client to the list of clients of the BroadcastManager.
 // The client will receive certificate broadcasts right after registration.
+// The registration is handled by the broadcaster in between two entries. That way a client that requested
+// to resume the stream gets all buffered entries up to the current one and will neither miss nor receive
+// duplicate entries.
 func (bm *BroadcastManager) registerClient(c *client) {
-	bm.clientLock.Lock()
-	bm.clients = append(bm.clients, c)
-	log.Printf("Clients: %d, Capacity: %d\n", len(bm.clients), cap(bm.clients))
-	metrics.Prometheus.RegisterClient(c.name, func() float64 { return float64(c.skippedCerts) })
-	bm.clientLock.Unlock()
+	done := make(chan struct{})
+	bm.ops <- managerOp{add: c, done: done}
+	<-done
 }
 
 // unregisterClient removes a client from the list of clients of the BroadcastManager.
 // The client will no longer receive certificate broadcasts right after unregistering.
 func (bm *BroadcastManager) unregisterClient(targetClient *client) {
-	bm.clientLock.Lock()
+	// Free the client's connection slots
+	releaseClient(targetClient.ip)
+
+	if targetClient.key != nil {
+		targetClient.key.release()
+	}
+
+	done := make(chan struct{})
+	bm.ops <- managerOp{remove: targetClient, done: done}
+	<-done
+}
 
-	// Close the broadcast channel of the client, otherwise this leads to a memory leak
-	close(targetClient.broadcastChan)
-	metrics.Prometheus.UnregisterClient(targetClient.name)
+// sync blocks until all entries and registrations received by the broadcaster so far were processed by all shards.
+func (bm *BroadcastManager) sync() {
+	done := make(chan struct{})
+	bm.ops <- managerOp{done: done}
+	<-done
+}
+
+// snapshot returns the current list of registered clients. The returned slice must not be modified.
+func (bm *BroadcastManager) snapshot() []*client {
+	return *bm.clients.Load()
+}
 
-	// Remove client from internal client list
-	for i, c := range bm.clients {
-		if targetClient != c {
-			continue
+// addClient assigns the client to the shard with the fewest clients and adds it to the client list.
+// It must only be called by the broadcaster.
+func (bm *BroadcastManager) addClient(c *client) {
+	if c.resume && bm.replay != nil {
+		var complete bool
+
+		c.replay, complete = bm.replay.since(c.since)
+		if !complete {
+			c.logger().Info("Client requested entries that are no longer buffered", "since", c.since)
 		}
+	}
 
-		// Copy the last element of the slice to the position of the removed element
-		// Then remove the last element by re-slicing
-		bm.clients[i] = bm.clients[len(bm.clients)-1]
-		bm.clients[len(bm.clients)-1] = nil
-		bm.clients = bm.clients[:len(bm.clients)-1]
+	clients := bm.snapshot()
 
-		break
+	// Clients authenticated with the same API key share a name, so the metric is only registered once per name.
+	if !hasClientWithName(clients, c.name) {
+		name := c.name
+		metrics.Prometheus.RegisterClient(name, func() float64 { return float64(bm.skippedCertsByName(name)) })
 	}
 
-	bm.clientLock.Unlock()
+	c.shard = bm.shards[0]
+	for _, shard := range bm.shards[1:] {
+		if shard.size < c.shard.size {
+			c.shard = shard
+		}
+	}
+
+	c.shard.size++
+	c.shard.ops <- shardOp{add: c}
+	bm.subscribers[c.subscription()]++
+
+	newClients := make([]*client, 0, len(clients)+1)
+	newClients = append(newClients, clients...)
+	newClients = append(newClients, c)
+	bm.clients.Store(&newClients)
+
+	logger.Debug("Clients changed", "count", len(newClients))
+}
+
+// removeClient removes the client from its shard and the client list. It must only be called by the broadcaster.
+func (bm *BroadcastManager) removeClient(targetClient *client) {
+	clients := bm.snapshot()
+
+	index := slices.Index(clients, targetClient)
+	if index < 0 {
+		return
+	}
+
+	// The shard closes the client's broadcast channel, since it's the only one sending to it
+	targetClient.shard.size--
+	targetClient.shard.ops <- shardOp{remove: targetClient}
+	bm.subscribers[targetClient.subscription()]--
+
+	newClients := make([]*client, 0, len(clients)-1)
+	newClients = append(newClients, clients[:index]...)
+	newClients = append(newClients, clients[index+1:]...)
+	bm.clients.Store(&newClients)
+
+	if !hasClientWithName(newClients, targetClient.name) {
+		metrics.Prometheus.UnregisterClient(targetClient.name)
+	}
+}
+
+// syncShards waits until all shards processed the operations queued so far. It must only be called by the broadcaster.
+func (bm *BroadcastManager) syncShards() {
+	doneChans := make([]chan struct{}, len(bm.shards))
+
+	for i, shard := range bm.shards {
+		doneChans[i] = make(chan struct{})
+		shard.ops <- shardOp{done: doneChans[i]}
+	}
+
+	for _, done := range doneChans {
+		<-done
+	}
+}
+
+// hasClientWithName returns true if a client with the given name is contained in clients.
+func hasClientWithName(clients []*client, name string) bool {
+	for _, c := range clients {
+		if c.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skippedCertsByName returns the sum of skipped certs of all clients with the given name.
+func (bm *BroadcastManager) skippedCertsByName(name string) (skipped uint64) {
+	for _, c := range bm.snapshot() {
+		if c.name == name {
+			skipped += c.skippedCerts.Load()
+		}
+	}
+
+	return skipped
 }
 
 // ClientFullCount returns the current number of clients connected to the service on the `full` endpoint.
@@ -75,13 +267,15 @@ func (bm *BroadcastManager) ClientDomainsCount() (count int64) {
 	return bm.clientCountByType(SubTypeDomain)
 }
 
+// ClientNewDomainsCount returns the current number of clients connected to the service on the `new-domains` endpoint.
+func (bm *BroadcastManager) ClientNewDomainsCount() (count int64) {
+	return bm.clientCountByType(SubTypeNewDomains)
+}
+
 // clientCountByType returns the current number of clients connected to the service on the endpoint matching
 // the specified SubscriptionType.
 func (bm *BroadcastManager) clientCountByType(subType SubscriptionType) (count int64) {
-	bm.clientLock.RLock()
-	defer bm.clientLock.RUnlock()
-
-	for _, c := range bm.clients {
+	for _, c := range bm.snapshot() {
 		if c.subType == subType {
 			count++
 		}
@@ -91,54 +285,68 @@ func (bm *BroadcastManager) clientCountByType(subType SubscriptionType) (count i
 }
 
 func (bm *BroadcastManager) GetSkippedCerts() map[string]uint64 {
-	bm.clientLock.RLock()
-	defer bm.clientLock.RUnlock()
+	clients := bm.snapshot()
 
-	skippedCerts := make(map[string]uint64, len(bm.clients))
-	for _, c := range bm.clients {
-		skippedCerts[c.name] = c.skippedCerts
+	skippedCerts := make(map[string]uint64, len(clients))
+	for _, c := range clients {
+		skippedCerts[c.name] += c.skippedCerts.Load()
 	}
 
 	return skippedCerts
 }
 
-// broadcaster is run in a goroutine and handles the dispatching of entries to clients.
+// prepare encodes the entry for all subscriptions with at least one client. It must only be called by the broadcaster.
+func (bm *BroadcastManager) prepare(entry *models.Entry) *preparedEntry {
+	prepared := &preparedEntry{entry: entry, data: make(map[subscription][]byte, len(bm.subscribers))}
+
+	for sub, count := range bm.subscribers {
+		// Most entries don't contain new domains, so they aren't encoded for the new domains stream
+		if count > 0 && (sub.subType != SubTypeNewDomains || len(entry.NewDomains) > 0) {
+			prepared.data[sub] = sub.encode(entry)
+		}
+	}
+
+	return prepared
+}
+
+// broadcaster is run in a goroutine and handles the sequencing of entries and the (un-)registration of clients.
+// Each entry is encoded once per subscription and then handed to all shards, which dispatch it to their clients.
 func (bm *BroadcastManager) broadcaster() {
 	for {
-		var data []byte
-
-		// Take entry out of broadcast channel and generate JSON representations for the entry.
-		entry := <-bm.Broadcast
-		dataLite := entry.JSONLite()
-		dataFull := entry.JSON()
-		dataDomain := entry.JSONDomains()
-
-		bm.clientLock.RLock()
-
-		for _, c := range bm.clients {
-			switch c.subType {
-			case SubTypeLite:
-				data = dataLite
-			case SubTypeFull:
-				data = dataFull
-			case SubTypeDomain:
-				data = dataDomain
-			default:
-				log.Printf("Unknown subscription type '%d' for client '%s'. Skipping this client!\n", c.subType, c.name)
-				continue
+		select {
+		case entry := <-bm.Broadcast:
+			bm.seq++
+			entry.Seq = bm.seq
+
+			prepared := bm.prepare(&entry)
+
+			// Add the entry after encoding, so the cached JSON of the subscribed formats is kept for replaying it later.
+			if bm.replay != nil {
+				bm.replay.add(entry)
 			}
 
-			select {
-			case c.broadcastChan <- data:
-			default:
-				// Default case is executed if the client's broadcast channel is full.
-				c.skippedCerts++
-				if c.skippedCerts%1000 == 1 {
-					log.Printf("Not providing client '%s' with cert because client's buffer is full. The client can't keep up. Skipped certs: %d\n", c.name, c.skippedCerts)
+			if bm.latest != nil {
+				bm.latest.add(entry)
+			}
+
+			for _, shard := range bm.shards {
+				if shard.size == 0 {
+					continue
 				}
+
+				shard.ops <- shardOp{entry: prepared}
+			}
+		case op := <-bm.ops:
+			switch {
+			case op.add != nil:
+				bm.addClient(op.add)
+			case op.remove != nil:
+				bm.removeClient(op.remove)
+			default:
+				bm.syncShards()
 			}
-		}
 
-		bm.clientLock.RUnlock()
+			close(op.done)
+		}
 	}
 }
@@ -0,0 +1,225 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// newTestBroadcastManager creates a running BroadcastManager with the given number of shards.
+func newTestBroadcastManager(tb testing.TB, shards int) *BroadcastManager {
+	tb.Helper()
+
+	bm := NewBroadcastManager()
+	bm.Broadcast = make(chan models.Entry)
+	bm.StartShards(shards)
+
+	go bm.broadcaster()
+
+	return bm
+}
+
+func TestBroadcastManager_Dispatch(t *testing.T) {
+	bm := newTestBroadcastManager(t, 2)
+
+	clients := make([]*client, 5)
+	for i := range clients {
+		clients[i] = newClient(nil, SubTypeDomain, fmt.Sprintf("client-%d", i), 10)
+		bm.registerClient(clients[i])
+	}
+
+	if got := bm.ClientDomainsCount(); got != 5 {
+		t.Errorf("ClientDomainsCount: want 5, got %d", got)
+	}
+
+	bm.Broadcast <- models.Entry{}
+	bm.sync()
+
+	for _, c := range clients {
+		if len(c.broadcastChan) != 1 {
+			t.Errorf("client '%s': want 1 entry, got %d", c.name, len(c.broadcastChan))
+		}
+	}
+
+	bm.unregisterClient(clients[0])
+	bm.Broadcast <- models.Entry{}
+	bm.sync()
+
+	if got := bm.ClientDomainsCount(); got != 4 {
+		t.Errorf("ClientDomainsCount: want 4, got %d", got)
+	}
+
+	if _, ok := <-clients[0].broadcastChan; !ok {
+		t.Errorf("want first entry to be still buffered for unregistered client")
+	}
+
+	if _, ok := <-clients[0].broadcastChan; ok {
+		t.Errorf("want broadcast channel of unregistered client to be closed")
+	}
+
+	for _, c := range clients[1:] {
+		if len(c.broadcastChan) != 2 {
+			t.Errorf("client '%s': want 2 entries, got %d", c.name, len(c.broadcastChan))
+		}
+	}
+}
+
+func TestBroadcastManager_ResumeWithoutGaps(t *testing.T) {
+	bm := newTestBroadcastManager(t, 2)
+	bm.EnableReplay(10, "")
+
+	for range 3 {
+		bm.Broadcast <- models.Entry{}
+	}
+
+	c := newClient(nil, SubTypeDomain, "resuming-client", 10)
+	c.resume = true
+	c.since = 1
+	bm.registerClient(c)
+
+	bm.Broadcast <- models.Entry{}
+	bm.sync()
+
+	if len(c.replay) != 2 || c.replay[0].Seq != 2 || c.replay[1].Seq != 3 {
+		t.Errorf("want replayed entries 2 and 3, got %d entries", len(c.replay))
+	}
+
+	if len(c.broadcastChan) != 1 {
+		t.Errorf("want exactly one live entry, got %d", len(c.broadcastChan))
+	}
+}
+
+func TestBroadcastManager_GroupedDomains(t *testing.T) {
+	bm := newTestBroadcastManager(t, 1)
+
+	grouped := newClient(nil, SubTypeDomain, "grouped-client", 10)
+	grouped.grouped = true
+	bm.registerClient(grouped)
+
+	ungrouped := newClient(nil, SubTypeDomain, "ungrouped-client", 10)
+	bm.registerClient(ungrouped)
+
+	entry := models.Entry{}
+	entry.Data.LeafCert.AllDomains = []string{"example.com", "www.example.com"}
+	entry.Data.LeafCert.Domains = []models.Domain{
+		{Name: "example.com", RegistrableDomain: "example.com"},
+		{Name: "www.example.com", RegistrableDomain: "example.com"},
+	}
+
+	bm.Broadcast <- entry
+	bm.sync()
+
+	var groupedEntry models.GroupedDomainsEntry
+	if err := json.Unmarshal(<-grouped.broadcastChan, &groupedEntry); err != nil {
+		t.Fatal(err)
+	}
+
+	if groupedEntry.MessageType != "dns_entries_grouped" || len(groupedEntry.Data) != 1 || len(groupedEntry.Data[0].Domains) != 2 {
+		t.Errorf("want grouped domains for grouped client, got %+v", groupedEntry)
+	}
+
+	var ungroupedEntry models.DomainsEntry
+	if err := json.Unmarshal(<-ungrouped.broadcastChan, &ungroupedEntry); err != nil {
+		t.Fatal(err)
+	}
+
+	if ungroupedEntry.MessageType != "dns_entries" || len(ungroupedEntry.Data) != 2 {
+		t.Errorf("want plain domains for ungrouped client, got %+v", ungroupedEntry)
+	}
+}
+
+func TestBroadcastManager_NewDomains(t *testing.T) {
+	bm := newTestBroadcastManager(t, 1)
+
+	c := newClient(nil, SubTypeNewDomains, "new-domains-client", 10)
+	bm.registerClient(c)
+
+	if got := bm.ClientNewDomainsCount(); got != 1 {
+		t.Errorf("ClientNewDomainsCount: want 1, got %d", got)
+	}
+
+	bm.Broadcast <- models.Entry{}
+	bm.Broadcast <- models.Entry{NewDomains: []string{"example.com"}}
+	bm.sync()
+
+	if len(c.broadcastChan) != 1 {
+		t.Fatalf("want only the entry with new domains, got %d entries", len(c.broadcastChan))
+	}
+
+	var newDomains models.DomainsEntry
+	if err := json.Unmarshal(<-c.broadcastChan, &newDomains); err != nil {
+		t.Fatal(err)
+	}
+
+	if newDomains.MessageType != "new_domains" || len(newDomains.Data) != 1 || newDomains.Data[0] != "example.com" || newDomains.Seq != 2 {
+		t.Errorf("unexpected new domains: %+v", newDomains)
+	}
+}
+
+func TestBroadcastManager_PrepareOnlySubscribedFormats(t *testing.T) {
+	bm := NewBroadcastManager()
+	bm.subscribers[subscription{subType: SubTypeDomain, format: FormatCBOR}] = 1
+	bm.subscribers[subscription{subType: SubTypeFull, format: FormatJSON}] = 0
+
+	prepared := bm.prepare(&models.Entry{})
+
+	if len(prepared.data) != 1 {
+		t.Errorf("want exactly one encoding, got %d", len(prepared.data))
+	}
+
+	if prepared.data[subscription{subType: SubTypeDomain, format: FormatCBOR}] == nil {
+		t.Errorf("want CBOR domains encoding for subscribed format")
+	}
+}
+
+// BenchmarkBroadcaster measures the cost of distributing a single entry to all connected clients.
+func BenchmarkBroadcaster(b *testing.B) {
+	if err := logging.Setup(config.LoggingConfig{Output: os.DevNull}); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Cleanup(func() { _ = logging.Setup(config.LoggingConfig{}) })
+
+	entry := models.Entry{
+		Data: models.Data{
+			LeafCert: models.LeafCert{AllDomains: []string{"example.com", "www.example.com"}},
+		},
+		MessageType: "certificate_update",
+	}
+
+	for _, numClients := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("clients=%d", numClients), func(b *testing.B) {
+			bm := newTestBroadcastManager(b, 0)
+
+			clients := make([]*client, numClients)
+			for i := range clients {
+				clients[i] = newClient(nil, SubscriptionType(i%3), fmt.Sprintf("client-%d", i), 300)
+				bm.registerClient(clients[i])
+
+				// Simulate the broadcastHandler draining the channel
+				go func(c *client) {
+					for range c.broadcastChan {
+					}
+				}(clients[i])
+			}
+
+			b.ResetTimer()
+
+			for range b.N {
+				bm.Broadcast <- entry
+			}
+
+			bm.sync()
+			b.StopTimer()
+
+			for _, c := range clients {
+				bm.unregisterClient(c)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package web
+
+import (
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// preparedEntry is an entry together with its encoded representations. Each subscription is encoded only once
+// by the broadcaster and then shared by all shards.
+type preparedEntry struct {
+	entry *models.Entry
+	data  map[subscription][]byte
+}
+
+// dataFor returns the representation of the entry matching the client's subscription type and format.
+func (pe *preparedEntry) dataFor(c *client) []byte {
+	return pe.data[c.subscription()]
+}
+
+// shardOp is a single operation processed by a broadcastShard. Exactly one of the fields is set.
+// Since entries and client (un-)registrations are processed in order, a client registered after entry n
+// is guaranteed to receive every entry after n.
+type shardOp struct {
+	entry  *preparedEntry
+	add    *client
+	remove *client
+	// done is closed as soon as all previous operations were processed.
+	done chan struct{}
+}
+
+// broadcastShard dispatches entries to a subset of all clients in its own goroutine.
+// The shard's client list is only accessed by that goroutine and therefore doesn't need any locking.
+type broadcastShard struct {
+	ops     chan shardOp
+	clients []*client
+	// size is the number of clients assigned to the shard. It's maintained by the broadcaster.
+	size int
+}
+
+// newBroadcastShard creates a new broadcastShard with the given operation buffer size.
+func newBroadcastShard(bufferSize int) *broadcastShard {
+	return &broadcastShard{
+		ops: make(chan shardOp, bufferSize),
+	}
+}
+
+// run processes the operations of the shard. It's run in a goroutine.
+func (s *broadcastShard) run() {
+	for op := range s.ops {
+		switch {
+		case op.entry != nil:
+			s.dispatch(op.entry)
+		case op.add != nil:
+			s.clients = append(s.clients, op.add)
+		case op.remove != nil:
+			s.removeClient(op.remove)
+		case op.done != nil:
+			close(op.done)
+		}
+	}
+}
+
+// dispatch hands the entry to all clients of the shard.
+func (s *broadcastShard) dispatch(pe *preparedEntry) {
+	for _, c := range s.clients {
+		if !c.wants(pe.entry) {
+			continue
+		}
+
+		data := pe.dataFor(c)
+		if data == nil {
+			c.logger().Warn("Unknown subscription type. Skipping this client!", "subscription_type", c.subType)
+			continue
+		}
+
+		c.deliver(data)
+	}
+}
+
+// removeClient removes the client from the shard and closes its broadcast channel.
+// Closing the channel must happen here, since the shard is the only goroutine sending to it.
+func (s *broadcastShard) removeClient(targetClient *client) {
+	for i, c := range s.clients {
+		if targetClient != c {
+			continue
+		}
+
+		// Copy the last element of the slice to the position of the removed element
+		// Then remove the last element by re-slicing
+		s.clients[i] = s.clients[len(s.clients)-1]
+		s.clients[len(s.clients)-1] = nil
+		s.clients = s.clients[:len(s.clients)-1]
+
+		break
+	}
+
+	// Close the broadcast channel of the client, otherwise this leads to a memory leak
+	close(targetClient.broadcastChan)
+}
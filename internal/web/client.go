@@ -2,17 +2,22 @@ package web
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
 )
 
 const (
 	SubTypeFull SubscriptionType = iota
 	SubTypeLite
 	SubTypeDomain
+	SubTypeNewDomains
 )
 
 type SubscriptionType int
@@ -22,26 +27,63 @@ type client struct {
 	conn          *websocket.Conn
 	broadcastChan chan []byte
 	name          string
+	ip            string
 	subType       SubscriptionType
-	skippedCerts  uint64
+	format        WireFormat
+	// grouped indicates that the client receives the domains grouped by registrable domain.
+	grouped bool
+	// skippedCerts is incremented by the client's shard and read by the client's handler for heartbeats and by the
+	// metrics, so it's accessed atomically.
+	skippedCerts atomic.Uint64
+	// policy defines what happens when the client's broadcastChan is full.
+	policy           SlowConsumerPolicy
+	sentCerts        uint64
+	consecutiveDrops uint64
+	pendingGap       uint64
+	// kick receives a reason when the client should be disconnected by the server.
+	kick chan string
+	// resume indicates whether the client requested to resume the stream after the sequence number since.
+	resume bool
+	since  uint64
+	// replay contains the buffered entries that are sent to the client before any live data.
+	replay []models.Entry
+	// key is the API key the client authenticated with. It's nil if authentication is disabled.
+	key *apiKey
+	// filter restricts the entries sent to the client. It's nil if the client receives all entries.
+	filter *entryFilter
+	// expiresAt is the time at which the client's token expires and the connection is closed.
+	expiresAt time.Time
+	// shard is the broadcastShard dispatching entries to the client.
+	shard *broadcastShard
+	// batch collects messages that are sent together in a single frame. It's nil if the client didn't request batching.
+	batch *batch
+	// log adds the client's name to each record.
+	log *slog.Logger
 }
 
 func newClient(conn *websocket.Conn, subType SubscriptionType, name string, certBufferSize int) *client {
 	return &client{
 		conn:          conn,
 		broadcastChan: make(chan []byte, certBufferSize),
+		kick:          make(chan string, 1),
 		name:          name,
 		subType:       subType,
+		log:           logger.With(logging.KeyClient, name),
 	}
 }
 
+// logger returns the logger for the client, which adds the client's name to each record.
+func (c *client) logger() *slog.Logger {
+	return c.log
+}
+
 // Each client has a broadcastHandler that runs in the background and sends out the broadcast messages to the client.
 func (c *client) broadcastHandler() {
 	writeWait := 60 * time.Second
 	pingTicker := time.NewTicker(30 * time.Second)
 
 	defer func() {
-		log.Println("Closing broadcast handler for client:", c.conn.RemoteAddr())
+		c.logger().Debug("Closing broadcast handler for client", "remote_addr", c.conn.RemoteAddr().String())
 
 		pingTicker.Stop()
 
@@ -50,6 +92,39 @@ func (c *client) broadcastHandler() {
 		_ = c.conn.Close()
 	}()
 
+	// Close the connection as soon as the client's token expires
+	var expiry <-chan time.Time
+
+	if !c.expiresAt.IsZero() {
+		expiryTimer := time.NewTimer(time.Until(c.expiresAt))
+		defer expiryTimer.Stop()
+
+		expiry = expiryTimer.C
+	}
+
+	// Send in-band heartbeats in addition to websocket pings if enabled
+	var heartbeat <-chan time.Time
+
+	if heartbeatTicker := newHeartbeatTicker(); heartbeatTicker != nil {
+		defer heartbeatTicker.Stop()
+
+		heartbeat = heartbeatTicker.C
+	}
+
+	// Send out missed entries before live data
+	for i := range c.replay {
+		if !c.wants(&c.replay[i]) {
+			continue
+		}
+
+		if err := c.send(c.encode(&c.replay[i]), writeWait); err != nil {
+			c.logger().Warn("Error while replaying entries", logging.KeyError, err)
+			return
+		}
+	}
+
+	c.replay = nil
+
 	for {
 		select {
 		case <-pingTicker.C:
@@ -58,28 +133,79 @@ func (c *client) broadcastHandler() {
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
-		case message := <-c.broadcastChan:
-			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+		case <-expiry:
+			c.logger().Info("Token of client expired, closing connection")
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				log.Printf("Error while getting next writer: %v\n", err)
+			closeMessage := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "Token expired")
+			_ = c.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(5*time.Second))
+
+			return
+		case reason := <-c.kick:
+			closeMessage := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+			_ = c.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(5*time.Second))
+
+			return
+		case message := <-c.broadcastChan:
+			if err := c.send(message, writeWait); err != nil {
+				c.logger().Warn("Error while sending message", logging.KeyError, err)
 				return
 			}
-
-			_, writeErr := w.Write(message)
-			if writeErr != nil {
-				log.Printf("Error while writing: %v\n", writeErr)
+		case <-heartbeat:
+			message, err := c.encodeHeartbeat()
+			if err != nil {
+				c.logger().Error("Error while encoding heartbeat", logging.KeyError, err)
+				continue
 			}
 
-			if closeErr := w.Close(); closeErr != nil {
-				log.Printf("Error while closing: %v\n", closeErr)
+			if err := c.send(message, writeWait); err != nil {
+				c.logger().Warn("Error while sending heartbeat", logging.KeyError, err)
+				return
+			}
+		case <-c.batch.timeout():
+			if err := c.flushBatch(writeWait); err != nil {
+				c.logger().Warn("Error while sending batch", logging.KeyError, err)
 				return
 			}
 		}
 	}
 }
 
+// writeMessage writes a single message to the client's websocket connection.
+func (c *client) writeMessage(message []byte, writeWait time.Duration) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+	w, err := c.conn.NextWriter(c.messageType())
+	if err != nil {
+		return fmt.Errorf("error while getting next writer: %w", err)
+	}
+
+	_, writeErr := w.Write(message)
+	if writeErr != nil {
+		c.logger().Warn("Error while writing", logging.KeyError, writeErr)
+	}
+
+	if closeErr := w.Close(); closeErr != nil {
+		return fmt.Errorf("error while closing writer: %w", closeErr)
+	}
+
+	return nil
+}
+
+// encode returns the representation of the entry matching the client's subscription type and format.
+func (c *client) encode(entry *models.Entry) []byte {
+	return c.subscription().encode(entry)
+}
+
+// wants returns true if the entry should be sent to the client. Clients of the new domains stream only receive
+// entries containing new domains.
+func (c *client) wants(entry *models.Entry) bool {
+	if c.subType == SubTypeNewDomains && len(entry.NewDomains) == 0 {
+		return false
+	}
+
+	return c.filter == nil || c.filter.matches(entry)
+}
+
 // listenWebsocket is running in the background on a goroutine and listens for messages from the client.
 // It responds to ping messages with a pong message. It closes the connection if the client sends
 // a close message or no ping is received within 65 seconds.
@@ -122,24 +248,24 @@ func (c *client) listenWebsocket() {
 		_, _, readErr := c.conn.ReadMessage()
 		if readErr != nil {
 			if websocket.IsUnexpectedCloseError(readErr, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
-				log.Printf("Unexpected websocket close error: %v\n", readErr)
+				c.logger().Warn("Unexpected websocket close error", logging.KeyError, readErr)
 			}
 
 			// If client fails to send ping messages
 			if strings.Contains(strings.ToLower(readErr.Error()), "i/o timeout") {
-				log.Printf("No ping received from client: %v\n", c.conn.RemoteAddr())
+				c.logger().Info("No ping received from client", "remote_addr", c.conn.RemoteAddr().String())
 
 				closeMessage := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "No ping received!")
 
 				writeErr := c.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(5*time.Second))
 				if writeErr != nil {
-					log.Printf("Error while sending close message: %v\n", writeErr)
+					c.logger().Warn("Error while sending close message", logging.KeyError, writeErr)
 				}
 			} else if strings.Contains(strings.ToLower(readErr.Error()), "an existing connection was forcibly closed by the remote host") {
-				log.Printf("Connection to client lost: %v\n", c.conn.RemoteAddr())
+				c.logger().Info("Connection to client lost", "remote_addr", c.conn.RemoteAddr().String())
 			}
 
-			log.Printf("Disconnecting client %v!\n", c.conn.RemoteAddr())
+			c.logger().Info("Disconnecting client", "remote_addr", c.conn.RemoteAddr().String())
 
 			break
 		}
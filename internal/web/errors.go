@@ -0,0 +1,10 @@
+package web
+
+import "errors"
+
+var (
+	ErrNoJWKSConfigured  = errors.New("no JWKS file or URL configured")
+	ErrJWKSRequestFailed = errors.New("JWKS request failed")
+	ErrTokenNoExpiry     = errors.New("token has no expiry")
+	ErrUnknownStream     = errors.New("unknown stream type in token")
+)
@@ -2,8 +2,11 @@ package web
 
 import (
 	"net/http"
+	"slices"
 
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/newdomains"
 )
 
 var exampleCert models.Entry
@@ -23,12 +26,49 @@ func exampleLite(w http.ResponseWriter, _ *http.Request) {
 }
 
 // exampleDomains handles requests to the /domains-only/example.json endpoint.
-// It returns a JSON representation of the domain data.
-func exampleDomains(w http.ResponseWriter, _ *http.Request) {
+// It returns a JSON representation of the domain data, grouped by registrable domain if requested.
+func exampleDomains(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
+
+	if groupedFor(r, SubTypeDomain) {
+		w.Write(exampleCert.JSONDomainsGrouped()) //nolint:errcheck
+		return
+	}
+
 	w.Write(exampleCert.JSONDomains()) //nolint:errcheck
 }
 
+// exampleNewDomains handles requests to the /new-domains/example.json endpoint.
+// It returns a JSON representation of the new domains data, as if all domains of the example certificate were new.
+func exampleNewDomains(w http.ResponseWriter, _ *http.Request) {
+	example := newDomainsExample()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(example.JSONNewDomains()) //nolint:errcheck
+}
+
+// newDomainsExample returns a copy of the example certificate with all its domains marked as new, according to the
+// configured mode.
+func newDomainsExample() models.Entry {
+	example := exampleCert.Clone()
+	if example.NewDomains != nil {
+		return example
+	}
+
+	for _, domain := range example.Data.LeafCert.Domains {
+		key := domain.RegistrableDomain
+		if config.AppConfig.NewDomains.Mode == newdomains.ModeFQDN {
+			key = domain.Name
+		}
+
+		if key != "" && !slices.Contains(example.NewDomains, key) {
+			example.NewDomains = append(example.NewDomains, key)
+		}
+	}
+
+	return example
+}
+
 // SetExampleCert sets one certificate as the example Cert that is returned by the example endpoints.
 func SetExampleCert(cert models.Entry) {
 	exampleCert = cert
@@ -0,0 +1,99 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// entryFilter restricts the entries that are sent to a client.
+type entryFilter struct {
+	// domains is a list of domains. An entry matches if any of its domains equals or is a subdomain of one of them.
+	domains []string
+	// minScore is the minimum phishing score of an entry. Entries without a score don't match if it's set.
+	minScore int
+	// parent is an additional filter that must match as well, e.g. the domains a client's token is restricted to.
+	parent *entryFilter
+}
+
+// newDomainFilter creates an entryFilter that only lets through entries for the given domains and their subdomains.
+// Wildcard prefixes such as "*.example.com" are treated like "example.com".
+func newDomainFilter(domains []string) *entryFilter {
+	filter := &entryFilter{domains: make([]string, 0, len(domains))}
+
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "*."))
+		if domain == "" {
+			continue
+		}
+
+		filter.domains = append(filter.domains, domain)
+	}
+
+	return filter
+}
+
+// newScoreFilter creates an entryFilter that only lets through entries with at least the given phishing score.
+func newScoreFilter(minScore int) *entryFilter {
+	return &entryFilter{minScore: minScore}
+}
+
+// scoreFilterFor returns the filter for the minimum score requested by the client via the "min_score" query
+// parameter or nil if the client didn't request a valid minimum score.
+func scoreFilterFor(r *http.Request) *entryFilter {
+	value := r.URL.Query().Get("min_score")
+	if value == "" {
+		return nil
+	}
+
+	minScore, err := strconv.Atoi(value)
+	if err != nil || minScore <= 0 {
+		logger.Info("Client requested invalid minimum score, sending all entries", "min_score", value, "remote_addr", r.RemoteAddr)
+		return nil
+	}
+
+	return newScoreFilter(minScore)
+}
+
+// restrict returns a filter that only lets through entries matching both the filter and parent.
+// If one of the filters is nil, the other one is returned.
+func (f *entryFilter) restrict(parent *entryFilter) *entryFilter {
+	if f == nil {
+		return parent
+	}
+
+	if parent == nil {
+		return f
+	}
+
+	return &entryFilter{domains: f.domains, minScore: f.minScore, parent: parent}
+}
+
+// matches returns true if the entry should be sent to the client.
+func (f *entryFilter) matches(entry *models.Entry) bool {
+	if f.parent != nil && !f.parent.matches(entry) {
+		return false
+	}
+
+	if f.minScore > 0 && (entry.Data.Score == nil || entry.Data.Score.Value < f.minScore) {
+		return false
+	}
+
+	if len(f.domains) == 0 {
+		return true
+	}
+
+	for _, domain := range entry.Data.LeafCert.AllDomains {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "*."))
+
+		for _, allowed := range f.domains {
+			if domain == allowed || strings.HasSuffix(domain, "."+allowed) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+func TestEntryFilter_MinScore(t *testing.T) {
+	entry := func(domain string, score *models.Score) *models.Entry {
+		return &models.Entry{Data: models.Data{LeafCert: models.LeafCert{AllDomains: []string{domain}}, Score: score}}
+	}
+
+	tests := []struct {
+		name   string
+		filter *entryFilter
+		entry  *models.Entry
+		want   bool
+	}{
+		{"score above minimum", newScoreFilter(50), entry("example.com", &models.Score{Value: 70}), true},
+		{"score equals minimum", newScoreFilter(50), entry("example.com", &models.Score{Value: 50}), true},
+		{"score below minimum", newScoreFilter(50), entry("example.com", &models.Score{Value: 49}), false},
+		{"no score", newScoreFilter(50), entry("example.com", nil), false},
+		{"restricted by domain", newScoreFilter(50).restrict(newDomainFilter([]string{"example.org"})), entry("example.com", &models.Score{Value: 70}), false},
+		{"domain and score", newScoreFilter(50).restrict(newDomainFilter([]string{"example.com"})), entry("www.example.com", &models.Score{Value: 70}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matches(tt.entry); got != tt.want {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestScoreFilterFor(t *testing.T) {
+	tests := []struct {
+		query        string
+		wantMinScore int
+	}{
+		{"", 0},
+		{"?min_score=60", 60},
+		{"?min_score=0", 0},
+		{"?min_score=-5", 0},
+		{"?min_score=high", 0},
+	}
+
+	for _, tt := range tests {
+		filter := scoreFilterFor(httptest.NewRequest("GET", "/"+tt.query, nil))
+
+		minScore := 0
+		if filter != nil {
+			minScore = filter.minScore
+		}
+
+		if minScore != tt.wantMinScore {
+			t.Errorf("query %q: want minimum score %d, got %d", tt.query, tt.wantMinScore, minScore)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package web
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/mem"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	certstreamv1 "github.com/d-Rickyy-b/certstream-server-go/api/certstream/v1"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+)
+
+// rawMessage is an already protobuf encoded message. Entries are encoded once by the broadcaster
+// and then sent as they are to all gRPC clients.
+type rawMessage []byte
+
+// rawCodec passes rawMessages through without encoding them again. All other messages are handled by the
+// default protobuf codec.
+type rawCodec struct {
+	encoding.CodecV2
+}
+
+// Marshal returns the wire format of v.
+func (c rawCodec) Marshal(v any) (mem.BufferSlice, error) {
+	if raw, ok := v.(rawMessage); ok {
+		return mem.BufferSlice{mem.SliceBuffer(raw)}, nil
+	}
+
+	return c.CodecV2.Marshal(v)
+}
+
+// marshalProtobuf encodes the given message and logs errors.
+func marshalProtobuf(message proto.Message) []byte {
+	data, err := proto.Marshal(message)
+	if err != nil {
+		logger.Error("Error encoding protobuf message", logging.KeyError, err)
+	}
+
+	return data
+}
+
+// GRPCServer serves the certstream gRPC service. Subscribers are registered as clients of the ClientHandler,
+// so they receive the same entries and are subject to the same limits and slow consumer handling as websocket clients.
+type GRPCServer struct {
+	certstreamv1.UnimplementedCertstreamServiceServer
+
+	addr   string
+	server *grpc.Server
+}
+
+// NewGRPCServer creates a new gRPC server listening on the given interface and port.
+// If certPath and keyPath are set, TLS is used.
+func NewGRPCServer(networkIf string, port int, certPath, keyPath string) *GRPCServer {
+	grpcServer := &GRPCServer{addr: net.JoinHostPort(networkIf, strconv.Itoa(port))}
+
+	options := []grpc.ServerOption{
+		grpc.ForceServerCodecV2(rawCodec{CodecV2: encoding.GetCodecV2("proto")}),
+	}
+
+	if certPath != "" && keyPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			logger.Error("Error loading gRPC TLS certificate", logging.KeyError, err)
+			os.Exit(1)
+		}
+
+		options = append(options, grpc.Creds(credentials.NewTLS(&tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		})))
+	}
+
+	grpcServer.server = grpc.NewServer(options...)
+	certstreamv1.RegisterCertstreamServiceServer(grpcServer.server, grpcServer)
+
+	return grpcServer
+}
+
+// Start starts listening for gRPC connections. It blocks until the server is stopped.
+func (gs *GRPCServer) Start() {
+	logger.Info("Starting gRPC server", "addr", gs.addr)
+
+	listener, err := net.Listen("tcp", gs.addr)
+	if err != nil {
+		logger.Error("Error while listening for gRPC connections", logging.KeyError, err)
+		os.Exit(1)
+	}
+
+	if err := gs.server.Serve(listener); err != nil {
+		logger.Error("Error while serving gRPC server", logging.KeyError, err)
+		os.Exit(1)
+	}
+}
+
+// Stop tries to stop the gRPC server gracefully. If it doesn't stop within 15 seconds, it is forcefully closed.
+func (gs *GRPCServer) Stop() {
+	logger.Info("Stopping gRPC server...")
+
+	stopped := make(chan struct{})
+
+	go func() {
+		gs.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(15 * time.Second):
+		gs.server.Stop()
+	}
+}
+
+// Subscribe streams all entries matching the filter to the client until the client cancels the call.
+func (gs *GRPCServer) Subscribe(filter *certstreamv1.Filter, stream grpc.ServerStreamingServer[certstreamv1.Message]) error {
+	ctx := stream.Context()
+	subType := subscriptionTypeFromProto(filter.GetStream())
+
+	if subType == SubTypeNewDomains && !config.AppConfig.NewDomains.Enabled {
+		return status.Error(codes.FailedPrecondition, "the new domains stream is disabled")
+	}
+
+	ip := grpcPeerIP(ctx)
+	if admission != nil {
+		if reason, ok := admission.admit(ip); !ok {
+			rejectConnection(reason)
+			return status.Errorf(codes.ResourceExhausted, "connection rejected: %s", reason)
+		}
+	}
+
+	auth, err := authenticateGRPC(ctx, subType)
+	if err != nil {
+		releaseClient(ip)
+		return err
+	}
+
+	name := ip
+	if auth.name != "" {
+		name = auth.name
+	}
+
+	c := newClient(nil, subType, name, config.AppConfig.General.BufferSizes.Websocket)
+	c.format = FormatProtobuf
+	c.grouped = subType == SubTypeDomain && filter.GetGroupByRegistrableDomain()
+	c.key = auth.key
+	c.ip = ip
+	c.policy = configuredSlowConsumerPolicy(subType)
+	c.filter = auth.filter
+	c.expiresAt = auth.expiresAt
+
+	if len(filter.GetDomains()) > 0 || filter.GetMinScore() > 0 {
+		requested := newDomainFilter(filter.GetDomains())
+		requested.minScore = int(filter.GetMinScore())
+		c.filter = requested.restrict(auth.filter)
+	}
+
+	if filter.Since != nil {
+		c.since, c.resume = filter.GetSince(), true
+	}
+
+	logger.Info("New gRPC subscriber", logging.KeyClient, name, "stream", filter.GetStream().String())
+
+	ClientHandler.registerClient(c)
+	defer ClientHandler.unregisterClient(c)
+
+	return c.streamTo(stream)
+}
+
+// streamTo sends the replayed and live entries of the client to the gRPC stream until the call is canceled.
+func (c *client) streamTo(stream grpc.ServerStreamingServer[certstreamv1.Message]) error {
+	ctx := stream.Context()
+
+	var expiry <-chan time.Time
+
+	if !c.expiresAt.IsZero() {
+		expiryTimer := time.NewTimer(time.Until(c.expiresAt))
+		defer expiryTimer.Stop()
+
+		expiry = expiryTimer.C
+	}
+
+	var heartbeat <-chan time.Time
+
+	if heartbeatTicker := newHeartbeatTicker(); heartbeatTicker != nil {
+		defer heartbeatTicker.Stop()
+
+		heartbeat = heartbeatTicker.C
+	}
+
+	for i := range c.replay {
+		if !c.wants(&c.replay[i]) {
+			continue
+		}
+
+		if err := stream.SendMsg(rawMessage(c.encode(&c.replay[i]))); err != nil {
+			return err
+		}
+	}
+
+	c.replay = nil
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-expiry:
+			return status.Error(codes.Unauthenticated, "token expired")
+		case reason := <-c.kick:
+			return status.Error(codes.ResourceExhausted, reason)
+		case <-heartbeat:
+			message, err := c.encodeHeartbeat()
+			if err != nil {
+				c.logger().Error("Error while encoding heartbeat", logging.KeyError, err)
+				continue
+			}
+
+			if err := stream.SendMsg(rawMessage(message)); err != nil {
+				return err
+			}
+		case message, ok := <-c.broadcastChan:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(rawMessage(message)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ListLogs returns the CT logs the server is watching, sorted by operator and URL.
+func (gs *GRPCServer) ListLogs(_ context.Context, _ *certstreamv1.ListLogsRequest) (*certstreamv1.ListLogsResponse, error) {
+	response := &certstreamv1.ListLogsResponse{}
+
+	for operator, urls := range metrics.Metrics.GetCTMetrics() {
+		for url, processed := range urls {
+			response.Logs = append(response.Logs, &certstreamv1.Log{
+				Operator:              operator,
+				Url:                   url,
+				ProcessedCertificates: processed,
+				LastIndex:             metrics.Metrics.GetCTIndex(url),
+			})
+		}
+	}
+
+	slices.SortFunc(response.GetLogs(), func(a, b *certstreamv1.Log) int {
+		return cmp.Or(cmp.Compare(a.GetOperator(), b.GetOperator()), cmp.Compare(a.GetUrl(), b.GetUrl()))
+	})
+
+	return response, nil
+}
+
+// GetExample returns the example entry for the requested stream.
+func (gs *GRPCServer) GetExample(_ context.Context, request *certstreamv1.GetExampleRequest) (*certstreamv1.Message, error) {
+	switch subscriptionTypeFromProto(request.GetStream()) {
+	case SubTypeFull:
+		return exampleCert.ProtoMessage(false), nil
+	case SubTypeDomain:
+		return exampleCert.ProtoDomainsMessage(), nil
+	case SubTypeNewDomains:
+		example := newDomainsExample()
+		return example.ProtoNewDomainsMessage(), nil
+	case SubTypeLite:
+	}
+
+	return exampleCert.ProtoMessage(true), nil
+}
+
+// subscriptionTypeFromProto converts the requested stream to a SubscriptionType. The lite stream is the default.
+func subscriptionTypeFromProto(stream certstreamv1.Stream) SubscriptionType {
+	switch stream {
+	case certstreamv1.Stream_STREAM_FULL:
+		return SubTypeFull
+	case certstreamv1.Stream_STREAM_DOMAINS:
+		return SubTypeDomain
+	case certstreamv1.Stream_STREAM_NEW_DOMAINS:
+		return SubTypeNewDomains
+	case certstreamv1.Stream_STREAM_UNSPECIFIED, certstreamv1.Stream_STREAM_LITE:
+	}
+
+	return SubTypeLite
+}
+
+// authenticateGRPC checks the credentials in the metadata of a gRPC call. Clients can send a bearer token
+// in the "authorization" metadata or an API key in the "x-api-key" metadata.
+func authenticateGRPC(ctx context.Context, subType SubscriptionType) (clientAuth, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	remoteAddr := grpcPeerIP(ctx)
+	resource := "gRPC " + subType.endpointName()
+
+	var claims *tokenClaims
+
+	if values := md.Get("authorization"); len(values) > 0 && tokenVerifier != nil {
+		if rawToken, found := strings.CutPrefix(values[0], "Bearer "); found {
+			verified, err := tokenVerifier.verify(strings.TrimSpace(rawToken))
+			if err != nil {
+				logger.Info("Rejecting gRPC client with invalid token", "remote_addr", remoteAddr, logging.KeyError, err)
+				rejectConnection(rejectReasonUnauthorized)
+
+				return clientAuth{}, status.Error(codes.Unauthenticated, "invalid token")
+			}
+
+			claims = verified
+		}
+	}
+
+	var rawKey string
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		rawKey = values[0]
+	}
+
+	auth, reason := checkCredentials(claims, rawKey, subType, remoteAddr, resource)
+
+	switch reason {
+	case "":
+		return auth, nil
+	case rejectReasonForbidden:
+		return clientAuth{}, status.Error(codes.PermissionDenied, "access to this stream is not allowed")
+	case rejectReasonKeyLimit:
+		return clientAuth{}, status.Error(codes.ResourceExhausted, "too many connections for this API key")
+	default:
+		return clientAuth{}, status.Error(codes.Unauthenticated, "missing or invalid credentials")
+	}
+}
+
+// grpcPeerIP returns the IP address of the gRPC client.
+func grpcPeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
@@ -0,0 +1,110 @@
+package web
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	certstreamv1 "github.com/d-Rickyy-b/certstream-server-go/api/certstream/v1"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// newTestGRPCClient starts a GRPCServer on an in-memory listener and returns a client connected to it.
+func newTestGRPCClient(t *testing.T) certstreamv1.CertstreamServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := NewGRPCServer("127.0.0.1", 0, "", "")
+
+	go func() { _ = grpcServer.server.Serve(listener) }()
+
+	t.Cleanup(grpcServer.server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create gRPC client: %v", err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return certstreamv1.NewCertstreamServiceClient(conn)
+}
+
+func TestGRPCServer_Subscribe(t *testing.T) {
+	previousHandler := ClientHandler
+	ClientHandler = newTestBroadcastManager(t, 1)
+
+	t.Cleanup(func() { ClientHandler = previousHandler })
+
+	config.AppConfig.General.BufferSizes.Websocket = 10
+	grpcClient := newTestGRPCClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := grpcClient.Subscribe(ctx, &certstreamv1.Filter{
+		Stream:  certstreamv1.Stream_STREAM_DOMAINS,
+		Domains: []string{"example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	// The client is registered asynchronously once the server received the call
+	for ClientHandler.ClientDomainsCount() == 0 {
+		if ctx.Err() != nil {
+			t.Fatalf("client was not registered: %v", ctx.Err())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	for _, domain := range []string{"other.org", "www.example.com"} {
+		var entry models.Entry
+		entry.Data.LeafCert.AllDomains = []string{domain}
+		ClientHandler.Broadcast <- entry
+	}
+
+	message, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+
+	if got := message.GetDomainsUpdate().GetDomains(); !slices.Equal(got, []string{"www.example.com"}) {
+		t.Errorf("want domains [www.example.com], got %v", got)
+	}
+
+	if message.GetSeq() != 2 {
+		t.Errorf("want seq 2, got %d", message.GetSeq())
+	}
+}
+
+func TestGRPCServer_GetExample(t *testing.T) {
+	previousExample := exampleCert
+	exampleCert = models.Entry{MessageType: "certificate_update"}
+	exampleCert.Data.LeafCert.AsDER = "MIIB"
+
+	t.Cleanup(func() { exampleCert = previousExample })
+
+	grpcClient := newTestGRPCClient(t)
+
+	message, err := grpcClient.GetExample(context.Background(), &certstreamv1.GetExampleRequest{Stream: certstreamv1.Stream_STREAM_FULL})
+	if err != nil {
+		t.Fatalf("GetExample failed: %v", err)
+	}
+
+	leafCert := message.GetCertificateUpdate().GetData().GetLeafCert()
+	if !slices.Equal(leafCert.GetDer(), []byte{0x30, 0x82, 0x01}) {
+		t.Errorf("want decoded DER data in full example, got %x", leafCert.GetDer())
+	}
+}
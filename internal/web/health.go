@@ -0,0 +1,129 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+// Health tracks the state of the CT watcher for the readiness probe. It's updated by the watcher.
+var Health = &HealthState{}
+
+// HealthState contains the information needed to decide whether the server is ready to serve clients.
+// All fields are accessed atomically, since they are updated by the watcher and read by the probes concurrently.
+type HealthState struct {
+	logListLoaded    atomic.Bool
+	streamingWorkers atomic.Int64
+	// lastEntry is the time of the last entry handled by the watcher in unix nanoseconds.
+	lastEntry atomic.Int64
+	// congestedSince is the time in unix nanoseconds since which the broadcast channel is filled above the
+	// configured threshold. It's 0 while the channel is below the threshold.
+	congestedSince atomic.Int64
+}
+
+// SetLogListLoaded marks the log list as loaded. Until then, the server is not ready.
+func (h *HealthState) SetLogListLoaded() {
+	if !h.logListLoaded.Swap(true) {
+		// Start measuring the time without entries once the workers are started
+		h.lastEntry.CompareAndSwap(0, time.Now().UnixNano())
+	}
+}
+
+// SetWorkerStreaming must be called with true when a CT log worker starts streaming entries and with false when it stops.
+func (h *HealthState) SetWorkerStreaming(streaming bool) {
+	if streaming {
+		h.streamingWorkers.Add(1)
+		return
+	}
+
+	h.streamingWorkers.Add(-1)
+}
+
+// EntrySeen records that the watcher handled a new entry and checks the fill level of the broadcast channel.
+func (h *HealthState) EntrySeen() {
+	now := time.Now()
+	h.lastEntry.Store(now.UnixNano())
+	h.observeBroadcastFill(now)
+}
+
+// observeBroadcastFill records since when the broadcast channel is filled above the configured threshold.
+func (h *HealthState) observeBroadcastFill(now time.Time) {
+	broadcast := ClientHandler.Broadcast
+	if cap(broadcast) == 0 {
+		return
+	}
+
+	fill := float64(len(broadcast)) / float64(cap(broadcast))
+	if fill < config.AppConfig.Health.BroadcastFillThreshold {
+		h.congestedSince.Store(0)
+		return
+	}
+
+	h.congestedSince.CompareAndSwap(0, now.UnixNano())
+}
+
+// readinessFailures returns the reasons why the server is not ready. It's empty if the server is ready.
+func (h *HealthState) readinessFailures(now time.Time) []string {
+	conf := config.AppConfig.Health
+
+	var failures []string
+
+	if !h.logListLoaded.Load() {
+		failures = append(failures, "log list not loaded yet")
+	}
+
+	if workers := h.streamingWorkers.Load(); workers < int64(conf.MinWorkers) {
+		failures = append(failures, fmt.Sprintf("%d of %d required workers streaming", workers, conf.MinWorkers))
+	}
+
+	if lastEntry := h.lastEntry.Load(); lastEntry != 0 && conf.MaxEntryAge > 0 {
+		if age := now.Sub(time.Unix(0, lastEntry)); age > time.Duration(conf.MaxEntryAge)*time.Second {
+			failures = append(failures, fmt.Sprintf("no entry received for %s", age.Truncate(time.Second)))
+		}
+	}
+
+	h.observeBroadcastFill(now)
+
+	if congestedSince := h.congestedSince.Load(); congestedSince != 0 && conf.BroadcastFillDuration > 0 {
+		if duration := now.Sub(time.Unix(0, congestedSince)); duration > time.Duration(conf.BroadcastFillDuration)*time.Second {
+			failures = append(failures, fmt.Sprintf("broadcast channel congested for %s", duration.Truncate(time.Second)))
+		}
+	}
+
+	return failures
+}
+
+// healthzHandler handles requests to the /healthz liveness probe. It succeeds as long as the server responds.
+func healthzHandler(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+	}{"ok"})
+}
+
+// readyzHandler handles requests to the /readyz readiness probe.
+// It responds with 503 Service Unavailable and the reasons if the server is not ready.
+func readyzHandler(w http.ResponseWriter, _ *http.Request) {
+	failures := Health.readinessFailures(time.Now())
+
+	response := struct {
+		Status   string   `json:"status"`
+		Failures []string `json:"failures,omitempty"`
+	}{Status: "ok", Failures: failures}
+
+	statusCode := http.StatusOK
+	if len(failures) > 0 {
+		response.Status = "not ready"
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, statusCode, response)
+}
+
+// RegisterHealthChecks registers the /healthz and /readyz probes.
+func (ws *Server) RegisterHealthChecks() {
+	ws.routes.Get("/healthz", healthzHandler)
+	ws.routes.Get("/readyz", readyzHandler)
+}
@@ -0,0 +1,77 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+func TestHealthState_Readiness(t *testing.T) {
+	previousHandler, previousConfig := ClientHandler, config.AppConfig.Health
+	t.Cleanup(func() { ClientHandler, config.AppConfig.Health = previousHandler, previousConfig })
+
+	config.AppConfig.Health = config.HealthConfig{MinWorkers: 1, MaxEntryAge: 60, BroadcastFillThreshold: 0.5, BroadcastFillDuration: 30}
+	ClientHandler = &BroadcastManager{Broadcast: make(chan models.Entry, 2)}
+
+	h := &HealthState{}
+	now := time.Now()
+
+	if failures := h.readinessFailures(now); len(failures) != 2 {
+		t.Errorf("want 2 failures before the log list was loaded, got %v", failures)
+	}
+
+	h.SetLogListLoaded()
+	h.SetWorkerStreaming(true)
+
+	if failures := h.readinessFailures(now); len(failures) != 0 {
+		t.Errorf("want server to be ready, got %v", failures)
+	}
+
+	if failures := h.readinessFailures(now.Add(2 * time.Minute)); len(failures) != 1 {
+		t.Errorf("want 1 failure without entries for 2 minutes, got %v", failures)
+	}
+
+	h.SetWorkerStreaming(false)
+
+	if failures := h.readinessFailures(now); len(failures) != 1 {
+		t.Errorf("want 1 failure without streaming workers, got %v", failures)
+	}
+
+	// Fill the broadcast channel above the threshold
+	h.SetWorkerStreaming(true)
+	ClientHandler.Broadcast <- models.Entry{}
+	h.EntrySeen()
+
+	if failures := h.readinessFailures(time.Now()); len(failures) != 0 {
+		t.Errorf("want server to be ready while the broadcast channel was only congested shortly, got %v", failures)
+	}
+
+	if failures := h.readinessFailures(time.Now().Add(time.Minute)); len(failures) != 2 {
+		t.Errorf("want 2 failures for a congested broadcast channel and no recent entry, got %v", failures)
+	}
+
+	<-ClientHandler.Broadcast
+	h.EntrySeen()
+
+	if failures := h.readinessFailures(time.Now()); len(failures) != 0 {
+		t.Errorf("want server to be ready after the broadcast channel drained, got %v", failures)
+	}
+}
+
+func TestReadyzHandler(t *testing.T) {
+	previousHealth := Health
+	t.Cleanup(func() { Health = previousHealth })
+
+	Health = &HealthState{}
+
+	recorder := httptest.NewRecorder()
+	readyzHandler(recorder, httptest.NewRequest("GET", "/readyz", nil))
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("want status 503 before the log list was loaded, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,282 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+)
+
+type tokenContextKey struct{}
+
+var (
+	tokenVerifier *jwtVerifier
+
+	allowedSignatureAlgorithms = []jose.SignatureAlgorithm{
+		jose.RS256, jose.RS384, jose.RS512,
+		jose.PS256, jose.PS384, jose.PS512,
+		jose.ES256, jose.ES384, jose.ES512,
+		jose.EdDSA,
+	}
+)
+
+// tokenClaims contains the information extracted from a validated bearer token.
+type tokenClaims struct {
+	subject   string
+	subTypes  []SubscriptionType
+	filter    *entryFilter
+	expiresAt time.Time
+}
+
+// allows returns true if the token grants access to the endpoint of the given subscription type.
+func (tc *tokenClaims) allows(subType SubscriptionType) bool {
+	return slices.Contains(tc.subTypes, subType)
+}
+
+// jwtVerifier validates bearer tokens against the keys of a JWKS. Keys fetched from a URL are cached
+// and refreshed after the configured interval or when a token references an unknown key ID.
+type jwtVerifier struct {
+	conf       config.JWTConfig
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	keys      *jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+// newJWTVerifier creates a new jwtVerifier and loads the configured JWKS.
+func newJWTVerifier(conf config.JWTConfig) (*jwtVerifier, error) {
+	verifier := &jwtVerifier{
+		conf:       conf,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if _, err := verifier.keySet(true); err != nil {
+		return nil, err
+	}
+
+	return verifier, nil
+}
+
+// keySet returns the cached JWKS. It's reloaded if it's older than the refresh interval or if forceRefresh is set.
+// To prevent hammering the JWKS URL with tokens using unknown key IDs, forced refreshes happen at most once a minute.
+func (v *jwtVerifier) keySet(forceRefresh bool) (*jose.JSONWebKeySet, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	age := time.Since(v.fetchedAt)
+	refreshInterval := time.Duration(v.conf.JWKSRefreshInterval) * time.Second
+
+	if v.keys != nil && age < refreshInterval && (!forceRefresh || age < time.Minute) {
+		return v.keys, nil
+	}
+
+	keys, err := v.loadKeys()
+	if err != nil {
+		if v.keys != nil {
+			// Keep using the previous keys if the JWKS is temporarily unavailable
+			logger.Warn("Error refreshing JWKS, using cached keys", logging.KeyError, err)
+			return v.keys, nil
+		}
+
+		return nil, err
+	}
+
+	v.keys = keys
+	v.fetchedAt = time.Now()
+
+	return v.keys, nil
+}
+
+// loadKeys reads the JWKS from the configured file or URL.
+func (v *jwtVerifier) loadKeys() (*jose.JSONWebKeySet, error) {
+	var data []byte
+
+	switch {
+	case v.conf.JWKSFile != "":
+		fileData, readErr := os.ReadFile(v.conf.JWKSFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", readErr)
+		}
+
+		data = fileData
+	case v.conf.JWKSURL != "":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		req, newReqErr := http.NewRequestWithContext(ctx, http.MethodGet, v.conf.JWKSURL, nil)
+		if newReqErr != nil {
+			return nil, fmt.Errorf("failed to create JWKS request: %w", newReqErr)
+		}
+
+		resp, reqErr := v.httpClient.Do(req)
+		if reqErr != nil {
+			return nil, fmt.Errorf("failed to fetch JWKS: %w", reqErr)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%w: unexpected status code %d", ErrJWKSRequestFailed, resp.StatusCode)
+		}
+
+		bodyData, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed reading JWKS response body: %w", readErr)
+		}
+
+		data = bodyData
+	default:
+		return nil, ErrNoJWKSConfigured
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	logger.Info("Loaded keys from JWKS", "count", len(keys.Keys))
+
+	return &keys, nil
+}
+
+// verify validates the signature and claims of the given token and extracts the stream permissions.
+func (v *jwtVerifier) verify(rawToken string) (*tokenClaims, error) {
+	token, parseErr := jwt.ParseSigned(rawToken, allowedSignatureAlgorithms)
+	if parseErr != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", parseErr)
+	}
+
+	keys, keysErr := v.keySet(false)
+	if keysErr != nil {
+		return nil, keysErr
+	}
+
+	// Refresh the keys if the token was signed with a key we don't know yet (e.g. after a key rotation)
+	if len(token.Headers) > 0 && len(keys.Key(token.Headers[0].KeyID)) == 0 && v.conf.JWKSURL != "" {
+		if keys, keysErr = v.keySet(true); keysErr != nil {
+			return nil, keysErr
+		}
+	}
+
+	var standardClaims jwt.Claims
+	var customClaims map[string]any
+
+	if err := token.Claims(keys, &standardClaims, &customClaims); err != nil {
+		return nil, fmt.Errorf("failed to verify token: %w", err)
+	}
+
+	expected := jwt.Expected{Issuer: v.conf.Issuer}
+	if v.conf.Audience != "" {
+		expected.AnyAudience = jwt.Audience{v.conf.Audience}
+	}
+
+	if err := standardClaims.ValidateWithLeeway(expected, time.Minute); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+
+	if standardClaims.Expiry == nil {
+		return nil, ErrTokenNoExpiry
+	}
+
+	claims := &tokenClaims{
+		subject:   standardClaims.Subject,
+		expiresAt: standardClaims.Expiry.Time(),
+	}
+
+	for _, stream := range claimToStrings(customClaims[v.conf.StreamsClaim]) {
+		switch stream {
+		case "full":
+			claims.subTypes = append(claims.subTypes, SubTypeFull)
+		case "lite":
+			claims.subTypes = append(claims.subTypes, SubTypeLite)
+		case "domains":
+			claims.subTypes = append(claims.subTypes, SubTypeDomain)
+		case "new_domains":
+			claims.subTypes = append(claims.subTypes, SubTypeNewDomains)
+		default:
+			return nil, fmt.Errorf("%w: '%s'", ErrUnknownStream, stream)
+		}
+	}
+
+	if domains := claimToStrings(customClaims[v.conf.DomainsClaim]); len(domains) > 0 {
+		claims.filter = newDomainFilter(domains)
+	}
+
+	return claims, nil
+}
+
+// claimToStrings converts a claim value to a list of strings. Both JSON arrays and space separated strings
+// (as commonly used for the "scope" claim) are supported.
+func claimToStrings(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		result := make([]string, 0, len(value))
+
+		for _, element := range value {
+			if str, ok := element.(string); ok {
+				result = append(result, str)
+			}
+		}
+
+		return result
+	default:
+		return nil
+	}
+}
+
+// bearerToken extracts the bearer token from the "Authorization" header or the "access_token" query parameter.
+func bearerToken(r *http.Request) string {
+	authHeader := r.Header.Get("Authorization")
+	if token, found := strings.CutPrefix(authHeader, "Bearer "); found {
+		return strings.TrimSpace(token)
+	}
+
+	return r.URL.Query().Get("access_token")
+}
+
+// jwtAuth returns a middleware that validates bearer tokens. Requests with an invalid token are rejected.
+// Requests without a token are passed on, so that other authentication methods can be used.
+// The claims of valid tokens are stored in the request context.
+func jwtAuth(verifier *jwtVerifier) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rawToken := bearerToken(r)
+			if rawToken == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := verifier.verify(rawToken)
+			if err != nil {
+				logger.Info("Rejecting client with invalid token", "remote_addr", r.RemoteAddr, logging.KeyError, err)
+				rejectConnection(rejectReasonUnauthorized)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), tokenContextKey{}, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// tokenClaimsFromContext returns the claims of a validated token or nil if the request carried no token.
+func tokenClaimsFromContext(ctx context.Context) *tokenClaims {
+	claims, _ := ctx.Value(tokenContextKey{}).(*tokenClaims)
+	return claims
+}
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+// newTestVerifier creates a jwtVerifier backed by a JWKS file containing a freshly generated key.
+// It returns the verifier and a signer for creating tokens.
+func newTestVerifier(t *testing.T) (*jwtVerifier, jose.Signer) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	jwks := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &privateKey.PublicKey, KeyID: "test", Algorithm: string(jose.ES256), Use: "sig"}}}
+
+	jwksData, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("failed to marshal JWKS: %v", err)
+	}
+
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(jwksPath, jwksData, 0o600); err != nil {
+		t.Fatalf("failed to write JWKS: %v", err)
+	}
+
+	verifier, err := newJWTVerifier(config.JWTConfig{
+		Enabled:             true,
+		JWKSFile:            jwksPath,
+		JWKSRefreshInterval: 3600,
+		Issuer:              "https://sso.example.com",
+		StreamsClaim:        "streams",
+		DomainsClaim:        "domains",
+	})
+	if err != nil {
+		t.Fatalf("failed to create verifier: %v", err)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: privateKey},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"))
+	if err != nil {
+		t.Fatalf("failed to create signer: %v", err)
+	}
+
+	return verifier, signer
+}
+
+// signToken creates a signed token with the given claims.
+func signToken(t *testing.T, signer jose.Signer, issuer string, expiry time.Time, custom map[string]any) string {
+	t.Helper()
+
+	token, err := jwt.Signed(signer).Claims(jwt.Claims{
+		Subject: "internal-client",
+		Issuer:  issuer,
+		Expiry:  jwt.NewNumericDate(expiry),
+	}).Claims(custom).Serialize()
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return token
+}
+
+func TestJWTVerifier_ValidToken(t *testing.T) {
+	verifier, signer := newTestVerifier(t)
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	token := signToken(t, signer, "https://sso.example.com", expiry, map[string]any{
+		"streams": []string{"lite", "domains"},
+		"domains": []string{"*.example.com"},
+	})
+
+	claims, err := verifier.verify(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if claims.subject != "internal-client" {
+		t.Errorf("subject: want 'internal-client', got %q", claims.subject)
+	}
+	if !claims.expiresAt.Equal(expiry) {
+		t.Errorf("expiresAt: want %v, got %v", expiry, claims.expiresAt)
+	}
+	if !claims.allows(SubTypeLite) || !claims.allows(SubTypeDomain) || claims.allows(SubTypeFull) {
+		t.Errorf("unexpected stream permissions: %v", claims.subTypes)
+	}
+	if claims.filter == nil || len(claims.filter.domains) != 1 || claims.filter.domains[0] != "example.com" {
+		t.Errorf("unexpected domain filter: %v", claims.filter)
+	}
+}
+
+func TestJWTVerifier_RejectsInvalidTokens(t *testing.T) {
+	verifier, signer := newTestVerifier(t)
+	streams := map[string]any{"streams": "lite"}
+
+	cases := map[string]string{
+		"expired":        signToken(t, signer, "https://sso.example.com", time.Now().Add(-time.Hour), streams),
+		"wrong issuer":   signToken(t, signer, "https://evil.example.com", time.Now().Add(time.Hour), streams),
+		"unknown stream": signToken(t, signer, "https://sso.example.com", time.Now().Add(time.Hour), map[string]any{"streams": "all"}),
+		"garbage":        "not-a-token",
+	}
+
+	for name, token := range cases {
+		if _, err := verifier.verify(token); err == nil {
+			t.Errorf("%s: expected token to be rejected", name)
+		}
+	}
+}
+
+func TestJWTAuthMiddleware(t *testing.T) {
+	verifier, signer := newTestVerifier(t)
+	token := signToken(t, signer, "https://sso.example.com", time.Now().Add(time.Hour), map[string]any{"streams": "full"})
+
+	var gotClaims *tokenClaims
+	handler := jwtAuth(verifier)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		gotClaims = tokenClaimsFromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if gotClaims == nil || !gotClaims.allows(SubTypeFull) {
+		t.Errorf("expected claims with access to the full stream in the request context, got %v", gotClaims)
+	}
+
+	w := httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/?access_token=invalid", nil)
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("invalid token: want %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
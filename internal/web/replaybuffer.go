@@ -0,0 +1,160 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// replayBuffer is a bounded ring buffer holding the most recently broadcast entries.
+// Reconnecting clients can request all entries after a given sequence number to be replayed before live data resumes.
+type replayBuffer struct {
+	mu      sync.RWMutex
+	entries []models.Entry
+	start   int
+	count   int
+}
+
+// persistedEntry is an entry as stored in the replay buffer file. The new domains aren't part of the JSON
+// representation of an entry, but must survive a restart, so that clients resuming the new domains stream receive them.
+type persistedEntry struct {
+	models.Entry
+	NewDomains []string `json:"new_domains,omitempty"`
+}
+
+// newReplayBuffer creates a new replayBuffer that holds at most size entries.
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{
+		entries: make([]models.Entry, size),
+	}
+}
+
+// add appends an entry to the buffer. If the buffer is full, the oldest entry is overwritten.
+func (rb *replayBuffer) add(entry models.Entry) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if rb.count < len(rb.entries) {
+		rb.entries[(rb.start+rb.count)%len(rb.entries)] = entry
+		rb.count++
+
+		return
+	}
+
+	rb.entries[rb.start] = entry
+	rb.start = (rb.start + 1) % len(rb.entries)
+}
+
+// since returns a copy of all entries with a sequence number greater than seq, ordered from oldest to newest.
+// The second return value is false if entries between seq and the oldest buffered entry were already evicted.
+func (rb *replayBuffer) since(seq uint64) ([]models.Entry, bool) {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	if rb.count == 0 {
+		return nil, true
+	}
+
+	oldestSeq := rb.entries[rb.start].Seq
+	complete := seq+1 >= oldestSeq
+
+	skip := 0
+	if complete {
+		skip = int(min(seq+1-oldestSeq, uint64(rb.count)))
+	}
+
+	result := make([]models.Entry, 0, rb.count-skip)
+	for i := skip; i < rb.count; i++ {
+		result = append(result, rb.entries[(rb.start+i)%len(rb.entries)])
+	}
+
+	return result, complete
+}
+
+// lastSeq returns the sequence number of the newest entry in the buffer or 0 if the buffer is empty.
+func (rb *replayBuffer) lastSeq() uint64 {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	if rb.count == 0 {
+		return 0
+	}
+
+	return rb.entries[(rb.start+rb.count-1)%len(rb.entries)].Seq
+}
+
+// latest returns a copy of the newest n entries, ordered from newest to oldest.
+func (rb *replayBuffer) latest(n int) []models.Entry {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	n = min(n, rb.count)
+
+	result := make([]models.Entry, 0, n)
+	for i := rb.count - 1; i >= rb.count-n; i-- {
+		result = append(result, rb.entries[(rb.start+i)%len(rb.entries)])
+	}
+
+	return result
+}
+
+// save stores the content of the buffer in the given file.
+// The data is written to a temp file first, which is then moved to the actual file path.
+func (rb *replayBuffer) save(filePath string) error {
+	entries, _ := rb.since(0)
+
+	persisted := make([]persistedEntry, len(entries))
+	for i := range entries {
+		persisted[i] = persistedEntry{Entry: entries[i], NewDomains: entries[i].NewDomains}
+	}
+
+	data, marshalErr := json.Marshal(persisted)
+	if marshalErr != nil {
+		return fmt.Errorf("could not marshal replay buffer: %w", marshalErr)
+	}
+
+	tempFilePath := filePath + ".tmp"
+
+	writeErr := os.WriteFile(tempFilePath, data, 0o644) //nolint:gosec
+	if writeErr != nil {
+		return fmt.Errorf("could not write replay buffer to temporary file: %w", writeErr)
+	}
+
+	renameErr := os.Rename(tempFilePath, filePath)
+	if renameErr != nil {
+		return fmt.Errorf("could not rename replay buffer temp file: %w", renameErr)
+	}
+
+	return nil
+}
+
+// load fills the buffer with the entries stored in the given file. A missing file is not treated as an error.
+func (rb *replayBuffer) load(filePath string) error {
+	data, readErr := os.ReadFile(filePath)
+	if readErr != nil {
+		if os.IsNotExist(readErr) {
+			logger.Info("Replay buffer file does not exist yet, starting with an empty buffer", "file", filePath)
+			return nil
+		}
+
+		return fmt.Errorf("could not read replay buffer file: %w", readErr)
+	}
+
+	var entries []persistedEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("could not unmarshal replay buffer file: %w", err)
+	}
+
+	for _, persisted := range entries {
+		entry := persisted.Entry
+		entry.NewDomains = persisted.NewDomains
+		rb.add(entry)
+	}
+
+	logger.Info("Loaded entries into the replay buffer", "count", len(entries))
+
+	return nil
+}
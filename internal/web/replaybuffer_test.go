@@ -0,0 +1,126 @@
+package web
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// fillReplayBuffer adds entries with the sequence numbers from..to (inclusive) to the buffer.
+func fillReplayBuffer(t *testing.T, rb *replayBuffer, from, to uint64) {
+	t.Helper()
+
+	for seq := from; seq <= to; seq++ {
+		rb.add(models.Entry{Seq: seq, MessageType: "certificate_update"})
+	}
+}
+
+func TestReplayBuffer_SinceReturnsNewerEntries(t *testing.T) {
+	rb := newReplayBuffer(10)
+	fillReplayBuffer(t, rb, 1, 5)
+
+	entries, complete := rb.since(3)
+	if !complete {
+		t.Errorf("expected replay to be complete")
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Seq != 4 || entries[1].Seq != 5 {
+		t.Errorf("expected sequence numbers 4 and 5, got %d and %d", entries[0].Seq, entries[1].Seq)
+	}
+}
+
+func TestReplayBuffer_SinceLatestIsEmpty(t *testing.T) {
+	rb := newReplayBuffer(10)
+	fillReplayBuffer(t, rb, 1, 5)
+
+	entries, complete := rb.since(5)
+	if !complete {
+		t.Errorf("expected replay to be complete")
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
+
+func TestReplayBuffer_EvictsOldestEntries(t *testing.T) {
+	rb := newReplayBuffer(3)
+	fillReplayBuffer(t, rb, 1, 7)
+
+	entries, complete := rb.since(2)
+	if complete {
+		t.Errorf("expected replay to be incomplete since entries 3 and 4 were evicted")
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	for i, want := range []uint64{5, 6, 7} {
+		if entries[i].Seq != want {
+			t.Errorf("entry %d: want seq %d, got %d", i, want, entries[i].Seq)
+		}
+	}
+
+	if got := rb.lastSeq(); got != 7 {
+		t.Errorf("lastSeq: want 7, got %d", got)
+	}
+}
+
+func TestReplayBuffer_SaveAndLoad(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "replay.json")
+
+	rb := newReplayBuffer(5)
+	fillReplayBuffer(t, rb, 10, 20)
+
+	if err := rb.save(filePath); err != nil {
+		t.Fatalf("failed to save replay buffer: %v", err)
+	}
+
+	loaded := newReplayBuffer(5)
+	if err := loaded.load(filePath); err != nil {
+		t.Fatalf("failed to load replay buffer: %v", err)
+	}
+
+	entries, _ := loaded.since(0)
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d", len(entries))
+	}
+	if entries[0].Seq != 16 || loaded.lastSeq() != 20 {
+		t.Errorf("expected entries 16..20, got %d..%d", entries[0].Seq, loaded.lastSeq())
+	}
+}
+
+func TestReplayBuffer_SaveAndLoadNewDomains(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "replay.json")
+
+	rb := newReplayBuffer(5)
+	rb.add(models.Entry{Seq: 1, NewDomains: []string{"example.com"}})
+	rb.add(models.Entry{Seq: 2})
+
+	if err := rb.save(filePath); err != nil {
+		t.Fatalf("failed to save replay buffer: %v", err)
+	}
+
+	loaded := newReplayBuffer(5)
+	if err := loaded.load(filePath); err != nil {
+		t.Fatalf("failed to load replay buffer: %v", err)
+	}
+
+	entries, _ := loaded.since(0)
+	if len(entries) != 2 || len(entries[0].NewDomains) != 1 || entries[0].NewDomains[0] != "example.com" {
+		t.Errorf("expected new domains to be restored, got %+v", entries)
+	}
+
+	if entries[1].NewDomains != nil {
+		t.Errorf("expected no new domains for the second entry, got %v", entries[1].NewDomains)
+	}
+}
+
+func TestReplayBuffer_LoadMissingFile(t *testing.T) {
+	rb := newReplayBuffer(5)
+
+	if err := rb.load(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("expected missing file to be ignored, got %v", err)
+	}
+}
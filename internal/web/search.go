@@ -0,0 +1,102 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/search"
+)
+
+// searchIndex is the index queried by the search endpoint. It's nil until the watcher opened the index.
+var searchIndex atomic.Pointer[search.Index]
+
+// SetSearchIndex sets the index that is queried by the search endpoint.
+func SetSearchIndex(idx *search.Index) {
+	searchIndex.Store(idx)
+}
+
+type searchError struct {
+	Error string `json:"error"`
+}
+
+// searchHandler handles requests to the /search endpoint.
+// It returns a page of the indexed certificates matching the query parameters in the lite format,
+// ordered from newest to oldest.
+func searchHandler(w http.ResponseWriter, r *http.Request) {
+	idx := searchIndex.Load()
+	if idx == nil {
+		writeJSON(w, http.StatusServiceUnavailable, searchError{"search index is not available"})
+		return
+	}
+
+	query, err := parseSearchQuery(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, searchError{err.Error()})
+		return
+	}
+
+	result, err := idx.Search(query)
+	switch {
+	case errors.Is(err, search.ErrInvalidQuery), errors.Is(err, search.ErrInvalidCursor), errors.Is(err, search.ErrQueryTooBroad):
+		writeJSON(w, http.StatusBadRequest, searchError{err.Error()})
+	case err != nil:
+		logger.Error("Error searching the index", logging.KeyError, err)
+		writeJSON(w, http.StatusInternalServerError, searchError{"search failed"})
+	default:
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// parseSearchQuery reads the search criteria from the query parameters.
+func parseSearchQuery(r *http.Request) (search.Query, error) {
+	params := r.URL.Query()
+
+	query := search.Query{
+		Domain: params.Get("domain"),
+		SHA256: params.Get("sha256"),
+		Serial: params.Get("serial"),
+		Issuer: params.Get("issuer"),
+		Cursor: params.Get("cursor"),
+	}
+
+	var err error
+
+	if query.From, err = parseSearchTime(params.Get("from")); err != nil {
+		return search.Query{}, err
+	}
+
+	if query.To, err = parseSearchTime(params.Get("to")); err != nil {
+		return search.Query{}, err
+	}
+
+	if limit := params.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit <= 0 {
+			return search.Query{}, errors.New("limit must be a positive number")
+		}
+	}
+
+	return query, nil
+}
+
+// parseSearchTime parses a time given as RFC 3339 timestamp or as unix timestamp in seconds.
+// An empty value is returned as zero time.
+func parseSearchTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(seconds * 1_000)), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("times must be RFC 3339 or unix timestamps")
+	}
+
+	return t, nil
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/search"
+)
+
+func TestSearchEndpoint(t *testing.T) {
+	previousWebserver, previousSearch := config.AppConfig.Webserver, config.AppConfig.Search
+	t.Cleanup(func() {
+		config.AppConfig.Webserver, config.AppConfig.Search = previousWebserver, previousSearch
+		SetSearchIndex(nil)
+	})
+
+	config.AppConfig.Webserver.FullURL = "/full-stream"
+	config.AppConfig.Webserver.LiteURL = "/"
+	config.AppConfig.Webserver.DomainsOnlyURL = "/domains-only"
+	config.AppConfig.Webserver.SearchURL = "/search"
+	config.AppConfig.Search = config.SearchConfig{
+		Enabled:     true,
+		File:        filepath.Join(t.TempDir(), "search.db"),
+		Retention:   24,
+		PageSize:    10,
+		MaxPageSize: 10,
+	}
+
+	router := chi.NewRouter()
+	setupWebsocketRoutes(router)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("GET", url, nil))
+
+		return recorder
+	}
+
+	if got := get("/search?domain=example.com").Code; got != http.StatusServiceUnavailable {
+		t.Errorf("want 503 without index, got %d", got)
+	}
+
+	idx, err := search.Open(config.AppConfig.Search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idx.Close()
+
+	SetSearchIndex(idx)
+
+	entry := models.Entry{MessageType: "certificate_update"}
+	entry.Data.Seen = float64(time.Now().UnixMilli()) / 1_000
+	entry.Data.LeafCert.AllDomains = []string{"www.example.com"}
+	entry.Data.LeafCert.Domains = []models.Domain{{Name: "www.example.com", RegistrableDomain: "example.com"}}
+	idx.Add(entry)
+
+	var result struct {
+		Results    []models.Entry `json:"results"`
+		NextCursor string         `json:"next_cursor"`
+	}
+
+	// Entries are written in the background
+	for deadline := time.Now().Add(5 * time.Second); len(result.Results) == 0 && time.Now().Before(deadline); {
+		recorder := get("/search?domain=*.example.com&from=" + time.Now().Add(-time.Hour).Format(time.RFC3339))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("want 200, got %d: %s", recorder.Code, recorder.Body)
+		}
+
+		if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(result.Results) != 1 || result.Results[0].Data.LeafCert.AllDomains[0] != "www.example.com" {
+		t.Errorf("want the indexed entry, got %+v", result)
+	}
+
+	for _, url := range []string{"/search?limit=0", "/search?from=yesterday", "/search?cursor=abc"} {
+		if got := get(url).Code; got != http.StatusBadRequest {
+			t.Errorf("%s: want 400, got %d", url, got)
+		}
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
@@ -15,6 +15,7 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 
 	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
 	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
 
 	"github.com/gorilla/websocket"
@@ -23,6 +24,8 @@ import (
 var (
 	ClientHandler = NewBroadcastManager()
 	upgrader      websocket.Upgrader
+	// logger is used by the websocket, metrics and gRPC servers and their clients.
+	logger = logging.Logger(logging.SubsystemWeb)
 )
 
 // Server is a struct that holds the necessary information to run a webserver.
@@ -48,7 +51,7 @@ func (ws *Server) RegisterPrometheus(url string, callback func(w io.Writer, expo
 // IPWhitelist returns a middleware that checks if the IP of the client is in the whitelist.
 func IPWhitelist(whitelist []string) func(next http.Handler) http.Handler {
 	// build a list of whitelisted IPs and CIDRs
-	log.Println("Building IP whitelist...")
+	logger.Debug("Building IP whitelist...")
 
 	var ipList []net.IP
 	var cidrList []net.IPNet
@@ -58,7 +61,7 @@ func IPWhitelist(whitelist []string) func(next http.Handler) http.Handler {
 		if err != nil {
 			var ip net.IP
 			if ip = net.ParseIP(element); ip == nil {
-				log.Println("Invalid IP in metrics whitelist: ", element)
+				logger.Warn("Invalid IP in metrics whitelist", "ip", element)
 
 				continue
 			}
@@ -71,8 +74,7 @@ func IPWhitelist(whitelist []string) func(next http.Handler) http.Handler {
 		cidrList = append(cidrList, *ipNet)
 	}
 
-	log.Println("IP whitelist: ", ipList)
-	log.Println("CIDR whitelist: ", cidrList)
+	logger.Info("Built IP whitelist", "ips", ipList, "cidrs", cidrList)
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -104,7 +106,7 @@ func IPWhitelist(whitelist []string) func(next http.Handler) http.Handler {
 				}
 			}
 
-			log.Printf("IP %s not in whitelist, rejecting request\n", r.RemoteAddr)
+			logger.Info("IP not in whitelist, rejecting request", "remote_addr", r.RemoteAddr)
 			http.Error(w, "Forbidden", http.StatusForbidden)
 		})
 	}
@@ -113,41 +115,69 @@ func IPWhitelist(whitelist []string) func(next http.Handler) http.Handler {
 // initFullWebsocket is called when a client connects to the /full-stream endpoint.
 // It upgrades the connection to a websocket and starts a goroutine to listen for messages from the client.
 func initFullWebsocket(w http.ResponseWriter, r *http.Request) {
-	connection, err := upgradeConnection(w, r)
-	if err != nil {
-		log.Println("Error while trying to upgrade connection:", err)
-		return
-	}
-
-	setupClient(connection, SubTypeFull, r.RemoteAddr)
+	initWebsocket(w, r, SubTypeFull)
 }
 
 // initLiteWebsocket is called when a client connects to the / endpoint.
 // It upgrades the connection to a websocket and starts a goroutine to listen for messages from the client.
 func initLiteWebsocket(w http.ResponseWriter, r *http.Request) {
-	connection, err := upgradeConnection(w, r)
-	if err != nil {
-		log.Println("Error while trying to upgrade connection:", err)
-		return
-	}
-
-	setupClient(connection, SubTypeLite, r.RemoteAddr)
+	initWebsocket(w, r, SubTypeLite)
 }
 
 // initDomainWebsocket is called when a client connects to the /domains-only endpoint.
 // It upgrades the connection to a websocket and starts a goroutine to listen for messages from the client.
 func initDomainWebsocket(w http.ResponseWriter, r *http.Request) {
-	connection, err := upgradeConnection(w, r)
+	initWebsocket(w, r, SubTypeDomain)
+}
+
+// initNewDomainsWebsocket is called when a client connects to the /new-domains endpoint.
+// It upgrades the connection to a websocket and starts a goroutine to listen for messages from the client.
+func initNewDomainsWebsocket(w http.ResponseWriter, r *http.Request) {
+	initWebsocket(w, r, SubTypeNewDomains)
+}
+
+// initWebsocket checks the connection limits, authenticates the client, upgrades the connection to a websocket
+// and sets up the client for the given subscription type.
+func initWebsocket(w http.ResponseWriter, r *http.Request, subType SubscriptionType) {
+	if !admitClient(w, r) {
+		return
+	}
+
+	auth, ok := authenticate(w, r, subType)
+	if !ok {
+		releaseClient(remoteIP(r))
+		return
+	}
+
+	// One of the requested subprotocols must be echoed, otherwise browsers will close the connection.
+	// The format subprotocol is preferred, so that clients can tell which format was negotiated.
+	format, subprotocol := wireFormatFor(r)
+	if subprotocol == "" {
+		subprotocol = auth.subprotocol
+	}
+
+	var responseHeader http.Header
+	if subprotocol != "" {
+		responseHeader = http.Header{"Sec-WebSocket-Protocol": {subprotocol}}
+	}
+
+	connection, err := upgradeConnection(w, r, responseHeader)
 	if err != nil {
-		log.Println("Error while trying to upgrade connection:", err)
+		logger.Warn("Error while trying to upgrade connection", logging.KeyError, err)
+		releaseClient(remoteIP(r))
+
+		if auth.key != nil {
+			auth.key.release()
+		}
+
 		return
 	}
 
-	setupClient(connection, SubTypeDomain, r.RemoteAddr)
+	setupClient(connection, subType, format, r, auth)
 }
 
 // upgradeConnection upgrades the connection to a websocket and returns the connection.
-func upgradeConnection(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
+func upgradeConnection(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (*websocket.Conn, error) {
 	var remoteAddr string
 
 	xForwardedFor := r.Header.Get("X-Forwarded-For")
@@ -157,16 +187,16 @@ func upgradeConnection(w http.ResponseWriter, r *http.Request) (*websocket.Conn,
 		remoteAddr = fmt.Sprintf("'%s'", r.RemoteAddr)
 	}
 
-	log.Printf("Starting new websocket for %s - %s\n", remoteAddr, r.URL)
+	logger.Info("Starting new websocket", "remote_addr", remoteAddr, "path", r.URL.Path)
 
-	connection, err := upgrader.Upgrade(w, r, nil)
+	connection, err := upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
 		return nil, err
 	}
 
 	defaultCloseHandler := connection.CloseHandler()
 	connection.SetCloseHandler(func(code int, text string) error {
-		log.Printf("Stopping websocket for %s - %s\n", remoteAddr, r.URL)
+		logger.Info("Stopping websocket", "remote_addr", remoteAddr, "path", r.URL.Path)
 		return defaultCloseHandler(code, text)
 	})
 
@@ -174,12 +204,50 @@ func upgradeConnection(w http.ResponseWriter, r *http.Request) (*websocket.Conn,
 }
 
 // setupClient initializes a client struct and starts the broadcastHandler and websocket listener.
-func setupClient(connection *websocket.Conn, subscriptionType SubscriptionType, name string) {
+// If the client authenticated, the name of its credentials is used as client name instead of the remote address.
+func setupClient(connection *websocket.Conn, subscriptionType SubscriptionType, format WireFormat, r *http.Request, auth clientAuth) {
+	name := r.RemoteAddr
+	if auth.name != "" {
+		name = auth.name
+	}
+
 	c := newClient(connection, subscriptionType, name, config.AppConfig.General.BufferSizes.Websocket)
+	c.format = format
+	c.grouped = groupedFor(r, subscriptionType)
+	c.key = auth.key
+	c.ip = remoteIP(r)
+	c.policy = slowConsumerPolicyFor(r, subscriptionType)
+	c.filter = scoreFilterFor(r).restrict(auth.filter)
+	c.expiresAt = auth.expiresAt
+	c.since, c.resume = resumeSequence(r)
+	c.batch = batchFor(r)
+
+	// The client must be registered before starting the broadcastHandler, so that replayed entries are available.
+	ClientHandler.registerClient(c)
+
 	go c.broadcastHandler()
 	go c.listenWebsocket()
+}
 
-	ClientHandler.registerClient(c)
+// resumeSequence returns the sequence number after which a client wants to resume the stream.
+// It's either passed via the "since" query parameter or the "Last-Event-ID" header.
+func resumeSequence(r *http.Request) (uint64, bool) {
+	since := r.URL.Query().Get("since")
+	if since == "" {
+		since = r.Header.Get("Last-Event-ID")
+	}
+
+	if since == "" {
+		return 0, false
+	}
+
+	seq, err := strconv.ParseUint(since, 10, 64)
+	if err != nil {
+		logger.Info("Invalid sequence number provided by client", "since", since, "remote_addr", r.RemoteAddr)
+		return 0, false
+	}
+
+	return seq, true
 }
 
 // setupWebsocketRoutes configures all the routes necessary for the websocket webserver.
@@ -200,6 +268,26 @@ func setupWebsocketRoutes(r *chi.Mux) {
 			r.HandleFunc("/", initDomainWebsocket)
 			r.HandleFunc("/example.json", exampleDomains)
 		})
+
+		if config.AppConfig.NewDomains.Enabled {
+			r.Route(config.AppConfig.Webserver.NewDomainsURL, func(r chi.Router) {
+				r.HandleFunc("/", initNewDomainsWebsocket)
+				r.HandleFunc("/example.json", exampleNewDomains)
+			})
+		}
+
+		if config.AppConfig.Search.Enabled {
+			r.Get(config.AppConfig.Webserver.SearchURL, searchHandler)
+		}
+
+		if config.AppConfig.IssuerStats.Enabled {
+			r.Get("/stats/issuers", issuerStatsHandler)
+		}
+
+		if config.AppConfig.Webserver.Stats.Enabled {
+			r.Get("/stats", statsHandler)
+			r.Get("/latest.json", latestHandler)
+		}
 	})
 }
 
@@ -283,10 +371,40 @@ func NewWebsocketServer(networkIf string, port int, certPath, keyPath string) *S
 		websocketServer.routes.Use(IPWhitelist(config.AppConfig.Webserver.Whitelist))
 	}
 
+	if config.AppConfig.Webserver.Auth.Enabled {
+		apiKeys = newAPIKeyStore(config.AppConfig.Webserver.Auth.Keys, config.AppConfig.Webserver.Limits.MaxClientsPerKey)
+	}
+
+	limits := config.AppConfig.Webserver.Limits
+	if limits.MaxClients > 0 || limits.MaxClientsPerIP > 0 || limits.ConnectionRate > 0 {
+		admission = newAdmissionController(limits)
+	}
+
+	if config.AppConfig.Webserver.JWT.Enabled {
+		verifier, err := newJWTVerifier(config.AppConfig.Webserver.JWT)
+		if err != nil {
+			logger.Error("Error while setting up JWT authentication", logging.KeyError, err)
+			os.Exit(1)
+		}
+
+		tokenVerifier = verifier
+		websocketServer.routes.Use(jwtAuth(tokenVerifier))
+	}
+
 	setupWebsocketRoutes(websocketServer.routes)
 	websocketServer.initServer()
 
 	ClientHandler.Broadcast = make(chan models.Entry, config.AppConfig.General.BufferSizes.BroadcastManager)
+
+	if config.AppConfig.Webserver.Replay.Enabled {
+		ClientHandler.EnableReplay(config.AppConfig.Webserver.Replay.Size, config.AppConfig.Webserver.Replay.File)
+	}
+
+	if config.AppConfig.Webserver.Stats.Enabled {
+		ClientHandler.EnableLatest(config.AppConfig.Webserver.Stats.LatestSize)
+	}
+
+	ClientHandler.StartShards(config.AppConfig.Webserver.BroadcastShards)
 	go ClientHandler.broadcaster()
 
 	return websocketServer
@@ -294,7 +412,7 @@ func NewWebsocketServer(networkIf string, port int, certPath, keyPath string) *S
 
 // Start initializes the webserver and starts listening for connections.
 func (ws *Server) Start() {
-	log.Printf("Starting webserver on %s\n", ws.server.Addr)
+	logger.Info("Starting webserver", "addr", ws.server.Addr)
 
 	var err error
 	if ws.keyPath != "" && ws.certPath != "" {
@@ -304,16 +422,18 @@ func (ws *Server) Start() {
 	}
 
 	if err != nil {
-		log.Fatal("Error while serving webserver: ", err)
+		logger.Error("Error while serving webserver", logging.KeyError, err)
+		os.Exit(1)
 	}
 }
 
 // Stop tries to stop the webserver gracefully. If it doesn't stop within 15 seconds, it is forcefully closed.
 func (ws *Server) Stop() {
-	log.Println("Stopping webserver...")
+	logger.Info("Stopping webserver...")
 
 	if err := ws.shutdown(); err != nil {
-		log.Fatal("Error while stopping webserver: ", err)
+		logger.Error("Error while stopping webserver", logging.KeyError, err)
+		os.Exit(1)
 	}
 }
 
@@ -0,0 +1,183 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+)
+
+// SlowConsumerPolicy defines how the BroadcastManager handles clients whose buffer is full.
+type SlowConsumerPolicy int
+
+const (
+	// PolicyDropNewest drops the entry that doesn't fit into the client's buffer anymore.
+	PolicyDropNewest SlowConsumerPolicy = iota
+	// PolicyDropOldest drops the oldest entry in the client's buffer to make room for the new one.
+	PolicyDropOldest
+	// PolicyDisconnect drops the entry and disconnects the client if it keeps falling behind.
+	PolicyDisconnect
+	// PolicyGapNotice drops the entry and notifies the client about the number of skipped entries in-band.
+	PolicyGapNotice
+)
+
+// parseSlowConsumerPolicy converts the name of a policy to a SlowConsumerPolicy.
+func parseSlowConsumerPolicy(name string) (SlowConsumerPolicy, bool) {
+	switch name {
+	case "drop_newest":
+		return PolicyDropNewest, true
+	case "drop_oldest":
+		return PolicyDropOldest, true
+	case "disconnect":
+		return PolicyDisconnect, true
+	case "gap_notice":
+		return PolicyGapNotice, true
+	default:
+		return PolicyDropNewest, false
+	}
+}
+
+// slowConsumerPolicyFor returns the policy for a new client. The client's own choice via query parameter takes
+// precedence over the policy configured for the endpoint, which in turn takes precedence over the default policy.
+func slowConsumerPolicyFor(r *http.Request, subType SubscriptionType) SlowConsumerPolicy {
+	conf := config.AppConfig.Webserver.SlowConsumer
+
+	if conf.AllowClientOverride {
+		if requested := r.URL.Query().Get("slow_consumer_policy"); requested != "" {
+			if policy, ok := parseSlowConsumerPolicy(requested); ok {
+				return policy
+			}
+
+			logger.Info("Client requested unknown slow consumer policy", "remote_addr", r.RemoteAddr, "policy", requested)
+		}
+	}
+
+	return configuredSlowConsumerPolicy(subType)
+}
+
+// configuredSlowConsumerPolicy returns the policy configured for the endpoint of the subscription type or the
+// default policy if the endpoint has none.
+func configuredSlowConsumerPolicy(subType SubscriptionType) SlowConsumerPolicy {
+	conf := config.AppConfig.Webserver.SlowConsumer
+
+	if endpointPolicy, ok := conf.EndpointPolicies[subType.endpointName()]; ok {
+		if policy, valid := parseSlowConsumerPolicy(endpointPolicy); valid {
+			return policy
+		}
+	}
+
+	policy, _ := parseSlowConsumerPolicy(conf.Policy)
+
+	return policy
+}
+
+// deliver hands the data to the client's broadcast channel. If the channel is full, the client's
+// slow consumer policy is applied. It must only be called by the shard that owns the client, since the delivery
+// counters and the pending gap of the client aren't synchronized.
+func (c *client) deliver(data []byte) {
+	if c.policy == PolicyGapNotice && c.pendingGap > 0 {
+		c.sendGapNotice()
+	}
+
+	select {
+	case c.broadcastChan <- data:
+		c.sentCerts++
+		c.consecutiveDrops = 0
+
+		return
+	default:
+		// Default case is executed if the client's broadcast channel is full.
+	}
+
+	switch c.policy {
+	case PolicyDropOldest:
+		// Make room by discarding the oldest entry. The broadcastHandler might have emptied the channel in the
+		// meantime, so none of the operations must block.
+		select {
+		case <-c.broadcastChan:
+		default:
+		}
+
+		select {
+		case c.broadcastChan <- data:
+			c.sentCerts++
+		default:
+		}
+	case PolicyGapNotice:
+		c.pendingGap++
+	case PolicyDisconnect:
+		c.consecutiveDrops++
+		if reason, disconnect := c.exceedsLossLimits(); disconnect {
+			c.disconnect(reason)
+		}
+	case PolicyDropNewest:
+	}
+
+	skipped := c.skippedCerts.Add(1)
+	if skipped%1000 == 1 {
+		c.logger().Debug("Not providing client with cert because client's buffer is full. The client can't keep up.", "skipped_certs", skipped)
+	}
+}
+
+// sendGapNotice tries to send a gap message with the number of entries skipped since the last notice.
+func (c *client) sendGapNotice() {
+	gapMessage, err := c.encodeGap(c.pendingGap)
+	if err != nil {
+		c.logger().Error("Error while encoding gap notice", logging.KeyError, err)
+		return
+	}
+
+	select {
+	case c.broadcastChan <- gapMessage:
+		c.pendingGap = 0
+	default:
+	}
+}
+
+// exceedsLossLimits checks whether the client dropped too many entries in a row or lost too many entries in total.
+func (c *client) exceedsLossLimits() (reason string, exceeded bool) {
+	conf := config.AppConfig.Webserver.SlowConsumer
+
+	if conf.DisconnectAfterDrops > 0 && c.consecutiveDrops >= uint64(conf.DisconnectAfterDrops) {
+		return fmt.Sprintf("Client too slow: %d consecutive entries dropped", c.consecutiveDrops), true
+	}
+
+	// Only check the loss ratio after a reasonable number of entries to prevent disconnects right after connecting
+	skipped := c.skippedCerts.Load()
+	total := c.sentCerts + skipped + 1
+	if conf.DisconnectLossPercent > 0 && total >= 1000 {
+		loss := float64(skipped+1) / float64(total) * 100
+		if loss >= conf.DisconnectLossPercent {
+			return fmt.Sprintf("Client too slow: %.1f%% of entries dropped", loss), true
+		}
+	}
+
+	return "", false
+}
+
+// disconnect asks the client's broadcastHandler to close the connection with the given reason.
+func (c *client) disconnect(reason string) {
+	select {
+	case c.kick <- reason:
+		c.logger().Info("Disconnecting client", "reason", reason)
+	default:
+		// A disconnect is already pending
+	}
+}
+
+// endpointName returns the name of the endpoint for the SubscriptionType as used in the config.
+func (s SubscriptionType) endpointName() string {
+	switch s {
+	case SubTypeFull:
+		return "full"
+	case SubTypeLite:
+		return "lite"
+	case SubTypeDomain:
+		return "domains"
+	case SubTypeNewDomains:
+		return "new_domains"
+	default:
+		return ""
+	}
+}
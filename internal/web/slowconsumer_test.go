@@ -0,0 +1,195 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	certstreamv1 "github.com/d-Rickyy-b/certstream-server-go/api/certstream/v1"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+)
+
+// newTestClient creates a client without connection with the given buffer size and policy.
+func newTestClient(t *testing.T, bufferSize int, policy SlowConsumerPolicy) *client {
+	t.Helper()
+
+	c := newClient(nil, SubTypeLite, "test-client", bufferSize)
+	c.policy = policy
+
+	return c
+}
+
+func TestDeliver_DropNewest(t *testing.T) {
+	c := newTestClient(t, 1, PolicyDropNewest)
+
+	c.deliver([]byte("1"))
+	c.deliver([]byte("2"))
+
+	if got := string(<-c.broadcastChan); got != "1" {
+		t.Errorf("want oldest entry '1' to be kept, got %q", got)
+	}
+	if got := c.skippedCerts.Load(); got != 1 {
+		t.Errorf("skippedCerts: want 1, got %d", got)
+	}
+}
+
+func TestDeliver_DropOldest(t *testing.T) {
+	c := newTestClient(t, 1, PolicyDropOldest)
+
+	c.deliver([]byte("1"))
+	c.deliver([]byte("2"))
+
+	if got := string(<-c.broadcastChan); got != "2" {
+		t.Errorf("want newest entry '2' to be kept, got %q", got)
+	}
+	if got := c.skippedCerts.Load(); got != 1 {
+		t.Errorf("skippedCerts: want 1, got %d", got)
+	}
+}
+
+func TestDeliver_GapNotice(t *testing.T) {
+	c := newTestClient(t, 2, PolicyGapNotice)
+
+	for _, data := range []string{"1", "2", "3", "4"} {
+		c.deliver([]byte(data))
+	}
+
+	// Free the buffer, so the gap notice and the next entry fit in
+	<-c.broadcastChan
+	<-c.broadcastChan
+
+	c.deliver([]byte("5"))
+
+	if got := string(<-c.broadcastChan); got != `{"message_type":"gap","skipped":2}` {
+		t.Errorf("unexpected gap notice: %s", got)
+	}
+	if got := string(<-c.broadcastChan); got != "5" {
+		t.Errorf("want entry '5' after gap notice, got %q", got)
+	}
+}
+
+func TestDeliver_DisconnectAfterConsecutiveDrops(t *testing.T) {
+	config.AppConfig.Webserver.SlowConsumer.DisconnectAfterDrops = 3
+	t.Cleanup(func() { config.AppConfig.Webserver.SlowConsumer.DisconnectAfterDrops = 0 })
+
+	c := newTestClient(t, 1, PolicyDisconnect)
+
+	for range 3 {
+		c.deliver([]byte("data"))
+	}
+
+	select {
+	case reason := <-c.kick:
+		t.Errorf("client should not be disconnected yet: %s", reason)
+	default:
+	}
+
+	c.deliver([]byte("data"))
+
+	select {
+	case reason := <-c.kick:
+		if !strings.Contains(reason, "3 consecutive") {
+			t.Errorf("unexpected disconnect reason: %s", reason)
+		}
+	default:
+		t.Errorf("client should have been disconnected")
+	}
+}
+
+func TestSlowConsumerPolicyFor(t *testing.T) {
+	config.AppConfig.Webserver.SlowConsumer = config.SlowConsumerConfig{
+		Policy:              "drop_newest",
+		EndpointPolicies:    map[string]string{"full": "disconnect"},
+		AllowClientOverride: true,
+	}
+	t.Cleanup(func() { config.AppConfig.Webserver.SlowConsumer = config.SlowConsumerConfig{} })
+
+	cases := []struct {
+		url     string
+		subType SubscriptionType
+		want    SlowConsumerPolicy
+	}{
+		{"/", SubTypeLite, PolicyDropNewest},
+		{"/", SubTypeFull, PolicyDisconnect},
+		{"/?slow_consumer_policy=gap_notice", SubTypeFull, PolicyGapNotice},
+		{"/?slow_consumer_policy=invalid", SubTypeLite, PolicyDropNewest},
+	}
+
+	for _, testcase := range cases {
+		r := httptest.NewRequest(http.MethodGet, testcase.url, nil)
+		if got := slowConsumerPolicyFor(r, testcase.subType); got != testcase.want {
+			t.Errorf("%s (%d): want %d, got %d", testcase.url, testcase.subType, testcase.want, got)
+		}
+	}
+}
+
+// heartbeatStream is a gRPC stream that reports the heartbeats sent to it.
+type heartbeatStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	heartbeats chan []byte
+}
+
+func (s *heartbeatStream) Context() context.Context { return s.ctx }
+
+func (s *heartbeatStream) Send(message *certstreamv1.Message) error { return s.SendMsg(message) }
+
+func (s *heartbeatStream) SendMsg(message any) error {
+	if raw, ok := message.(rawMessage); ok && bytes.Contains(raw, []byte("heartbeat")) {
+		select {
+		case s.heartbeats <- raw:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// TestDeliver_ConcurrentHeartbeat fills the client's buffer while the client's handler sends heartbeats containing
+// the number of skipped entries. Run with -race to detect unsynchronized access to the counters.
+func TestDeliver_ConcurrentHeartbeat(t *testing.T) {
+	config.AppConfig.Webserver.Heartbeat = config.HeartbeatConfig{Enabled: true, Interval: 1}
+	t.Cleanup(func() { config.AppConfig.Webserver.Heartbeat = config.HeartbeatConfig{} })
+
+	c := newTestClient(t, 1, PolicyDropNewest)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &heartbeatStream{ctx: ctx, heartbeats: make(chan []byte, 1)}
+
+	handlerDone := make(chan error)
+	go func() { handlerDone <- c.streamTo(stream) }()
+
+	deliverDone := make(chan struct{})
+	go func() {
+		defer close(deliverDone)
+
+		for ctx.Err() == nil {
+			c.deliver([]byte("entry"))
+		}
+	}()
+
+	select {
+	case <-stream.heartbeats:
+	case <-time.After(5 * time.Second):
+		t.Error("want a heartbeat to be sent")
+	}
+
+	cancel()
+	<-deliverDone
+
+	if err := <-handlerDone; err != nil {
+		t.Errorf("want handler to stop without error, got %v", err)
+	}
+
+	if c.skippedCerts.Load() == 0 {
+		t.Errorf("want entries to be skipped while the buffer is full")
+	}
+}
@@ -0,0 +1,118 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/logging"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+)
+
+// startTime is used to calculate the uptime reported by the /stats endpoint.
+var startTime = time.Now()
+
+// statsResponse is returned by the /stats endpoint.
+type statsResponse struct {
+	Version                  string            `json:"version"`
+	UptimeSeconds            int64             `json:"uptime_seconds"`
+	ProcessedCertificates    int64             `json:"processed_certificates"`
+	ProcessedPrecertificates int64             `json:"processed_precertificates"`
+	Clients                  clientStats       `json:"clients"`
+	Logs                     metrics.CTMetrics `json:"logs"`
+}
+
+type clientStats struct {
+	Full       int64 `json:"full"`
+	Lite       int64 `json:"lite"`
+	Domains    int64 `json:"domains"`
+	NewDomains int64 `json:"new_domains"`
+	Total      int64 `json:"total"`
+}
+
+// statsHandler handles requests to the /stats endpoint.
+// It returns the number of processed certificates overall and per log, the connected clients, uptime and version.
+func statsHandler(w http.ResponseWriter, _ *http.Request) {
+	clients := clientStats{
+		Full:       ClientHandler.ClientFullCount(),
+		Lite:       ClientHandler.ClientLiteCount(),
+		Domains:    ClientHandler.ClientDomainsCount(),
+		NewDomains: ClientHandler.ClientNewDomainsCount(),
+	}
+	clients.Total = clients.Full + clients.Lite + clients.Domains + clients.NewDomains
+
+	writeJSON(w, http.StatusOK, statsResponse{
+		Version:                  config.Version,
+		UptimeSeconds:            int64(time.Since(startTime).Seconds()),
+		ProcessedCertificates:    metrics.GetProcessedCerts(),
+		ProcessedPrecertificates: metrics.GetProcessedPrecerts(),
+		Clients:                  clients,
+		Logs:                     metrics.GetCertMetrics(),
+	})
+}
+
+// latestHandler handles requests to the /latest.json endpoint.
+// It returns the most recent entries in the lite format, ordered from newest to oldest.
+func latestHandler(w http.ResponseWriter, _ *http.Request) {
+	entries := ClientHandler.LatestEntries(config.AppConfig.Webserver.Stats.LatestSize)
+
+	messages := make([]json.RawMessage, len(entries))
+	for i := range entries {
+		messages[i] = bytes.TrimSuffix(entries[i].JSONLite(), []byte{'\n'})
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Messages []json.RawMessage `json:"messages"`
+	}{messages})
+}
+
+// issuerStatsHandler handles requests to the /stats/issuers endpoint.
+// It returns the number of certificates per issuer, log operator and signature algorithm within the last minute,
+// hour and day. The "sort" query parameter selects the window the results are ordered by (default 24h) and
+// "limit" the number of issuers (default top_n, 0 for all).
+func issuerStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if metrics.Issuers == nil {
+		http.Error(w, "Not Found", http.StatusNotFound)
+		return
+	}
+
+	sortWindow := r.URL.Query().Get("sort")
+	if sortWindow == "" {
+		sortWindow = metrics.WindowDay
+	}
+
+	if sortWindow != metrics.WindowMinute && sortWindow != metrics.WindowHour && sortWindow != metrics.WindowDay {
+		http.Error(w, "Bad Request: sort must be 1m, 1h or 24h", http.StatusBadRequest)
+		return
+	}
+
+	limit := config.AppConfig.IssuerStats.TopN
+
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+			http.Error(w, "Bad Request: limit must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, metrics.Issuers.Snapshot(sortWindow, limit))
+}
+
+// writeJSON writes the JSON representation of v to the response with the given status code.
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logger.Error("Error encoding JSON response", logging.KeyError, err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(data) //nolint:errcheck
+}
@@ -0,0 +1,113 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+func TestStatsEndpoints(t *testing.T) {
+	previousHandler, previousConfig := ClientHandler, config.AppConfig.Webserver
+	t.Cleanup(func() { ClientHandler, config.AppConfig.Webserver = previousHandler, previousConfig })
+
+	config.AppConfig.Webserver.FullURL = "/full-stream"
+	config.AppConfig.Webserver.LiteURL = "/"
+	config.AppConfig.Webserver.DomainsOnlyURL = "/domains-only"
+	config.AppConfig.Webserver.Stats = config.StatsConfig{Enabled: true, LatestSize: 2}
+
+	ClientHandler = NewBroadcastManager()
+	ClientHandler.Broadcast = make(chan models.Entry)
+	ClientHandler.EnableLatest(5)
+	ClientHandler.StartShards(1)
+
+	go ClientHandler.broadcaster()
+
+	ClientHandler.registerClient(newClient(nil, SubTypeLite, "lite-client", 10))
+
+	for range 3 {
+		ClientHandler.Broadcast <- models.Entry{MessageType: "certificate_update"}
+	}
+
+	ClientHandler.sync()
+
+	router := chi.NewRouter()
+	setupWebsocketRoutes(router)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/latest.json", nil))
+
+	var latest struct {
+		Messages []models.Entry `json:"messages"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &latest); err != nil {
+		t.Fatalf("/latest.json returned invalid JSON: %v", err)
+	}
+
+	if len(latest.Messages) != 2 || latest.Messages[0].Seq != 3 || latest.Messages[1].Seq != 2 {
+		t.Errorf("want entries 3 and 2, got %+v", latest.Messages)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/stats", nil))
+
+	var stats statsResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("/stats returned invalid JSON: %v", err)
+	}
+
+	if stats.Version != config.Version || stats.Clients.Lite != 1 || stats.Clients.Total != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestIssuerStatsEndpoint(t *testing.T) {
+	previousConfig, previousIssuers := config.AppConfig, metrics.Issuers
+	t.Cleanup(func() { config.AppConfig, metrics.Issuers = previousConfig, previousIssuers })
+
+	config.AppConfig.Webserver.FullURL = "/full-stream"
+	config.AppConfig.Webserver.LiteURL = "/"
+	config.AppConfig.Webserver.DomainsOnlyURL = "/domains-only"
+	config.AppConfig.IssuerStats = config.IssuerStatsConfig{Enabled: true, IssuerKey: "name", TopN: 1, MaxIssuers: 10}
+
+	metrics.Issuers = metrics.NewIssuerStats(config.AppConfig.IssuerStats)
+
+	for _, name := range []string{"/CN=R10", "/CN=R11", "/CN=R11"} {
+		entry := models.Entry{}
+		entry.Data.LeafCert.Issuer.Aggregated = &name
+		entry.Data.Source.Operator = "Google"
+		metrics.Issuers.Observe(&entry)
+	}
+
+	router := chi.NewRouter()
+	setupWebsocketRoutes(router)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/stats/issuers?sort=1m", nil))
+
+	var snapshot metrics.IssuerStatsSnapshot
+	if err := json.Unmarshal(recorder.Body.Bytes(), &snapshot); err != nil {
+		t.Fatalf("/stats/issuers returned invalid JSON: %v", err)
+	}
+
+	if len(snapshot.Issuers) != 1 || snapshot.Issuers[0].Name != "/CN=R11" || snapshot.Issuers[0].Counts.Minute != 2 {
+		t.Errorf("want only the top issuer, got %+v", snapshot.Issuers)
+	}
+
+	if len(snapshot.Operators) != 1 || snapshot.Operators[0].Counts.Day != 3 {
+		t.Errorf("unexpected operators: %+v", snapshot.Operators)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/stats/issuers?sort=1d", nil))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("want 400 for unknown window, got %d", recorder.Code)
+	}
+}
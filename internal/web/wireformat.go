@@ -0,0 +1,180 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/config"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/metrics"
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+// cborSubprotocol is the websocket subprotocol clients can request to receive CBOR encoded messages.
+const cborSubprotocol = "certstream.cbor"
+
+// WireFormat defines how messages are encoded for a client.
+type WireFormat int
+
+const (
+	// FormatJSON sends JSON encoded messages as websocket text frames.
+	FormatJSON WireFormat = iota
+	// FormatCBOR sends CBOR encoded messages as websocket binary frames.
+	FormatCBOR
+	// FormatProtobuf encodes messages as protobuf. It's only used by gRPC clients.
+	FormatProtobuf
+)
+
+// subscription is the combination of a stream type and the format its entries are encoded in.
+type subscription struct {
+	subType SubscriptionType
+	format  WireFormat
+	// grouped indicates that the domains of the domains-only stream are grouped by registrable domain.
+	grouped bool
+}
+
+// encode returns the representation of the entry for the subscription or nil if the subscription is invalid.
+func (s subscription) encode(entry *models.Entry) []byte {
+	switch s.format {
+	case FormatJSON:
+		switch s.subType {
+		case SubTypeFull:
+			return entry.JSON()
+		case SubTypeLite:
+			return entry.JSONLite()
+		case SubTypeDomain:
+			if s.grouped {
+				return entry.JSONDomainsGrouped()
+			}
+
+			return entry.JSONDomains()
+		case SubTypeNewDomains:
+			return entry.JSONNewDomains()
+		}
+	case FormatCBOR:
+		switch s.subType {
+		case SubTypeFull:
+			return entry.CBOR()
+		case SubTypeLite:
+			return entry.CBORLite()
+		case SubTypeDomain:
+			if s.grouped {
+				return entry.CBORDomainsGrouped()
+			}
+
+			return entry.CBORDomains()
+		case SubTypeNewDomains:
+			return entry.CBORNewDomains()
+		}
+	case FormatProtobuf:
+		switch s.subType {
+		case SubTypeFull:
+			return marshalProtobuf(entry.ProtoMessage(false))
+		case SubTypeLite:
+			return marshalProtobuf(entry.ProtoMessage(true))
+		case SubTypeDomain:
+			if s.grouped {
+				return marshalProtobuf(entry.ProtoDomainsGroupedMessage())
+			}
+
+			return marshalProtobuf(entry.ProtoDomainsMessage())
+		case SubTypeNewDomains:
+			return marshalProtobuf(entry.ProtoNewDomainsMessage())
+		}
+	}
+
+	return nil
+}
+
+// groupedFor returns true if the client requested the domains grouped by registrable domain via the "group_by"
+// query parameter. Grouping is only supported on the domains-only stream.
+func groupedFor(r *http.Request, subType SubscriptionType) bool {
+	return subType == SubTypeDomain && r.URL.Query().Get("group_by") == "registrable_domain"
+}
+
+// wireFormatFor returns the format requested by the client. It can be selected via the "format" query parameter
+// or the "certstream.cbor" subprotocol. If the format was requested via subprotocol, the subprotocol is returned
+// as well, since it must be echoed by the server.
+func wireFormatFor(r *http.Request) (format WireFormat, subprotocol string) {
+	switch requested := r.URL.Query().Get("format"); requested {
+	case "", "json":
+	case "cbor":
+		return FormatCBOR, ""
+	default:
+		logger.Info("Client requested unknown format, using JSON", "remote_addr", r.RemoteAddr, "format", requested)
+	}
+
+	for _, protocol := range strings.Split(r.Header.Get("Sec-WebSocket-Protocol"), ",") {
+		if strings.TrimSpace(protocol) == cborSubprotocol {
+			return FormatCBOR, cborSubprotocol
+		}
+	}
+
+	return FormatJSON, ""
+}
+
+// subscription returns the stream type, format and grouping of the client.
+func (c *client) subscription() subscription {
+	return subscription{subType: c.subType, format: c.format, grouped: c.grouped}
+}
+
+// messageType returns the websocket message type used for the client's format.
+func (c *client) messageType() int {
+	if c.format == FormatCBOR {
+		return websocket.BinaryMessage
+	}
+
+	return websocket.TextMessage
+}
+
+// encodeGap returns a gap message in the client's format.
+func (c *client) encodeGap(skipped uint64) ([]byte, error) {
+	gapEntry := models.GapEntry{MessageType: "gap", Skipped: skipped}
+
+	switch c.format {
+	case FormatCBOR:
+		return gapEntry.CBOR(), nil
+	case FormatProtobuf:
+		return proto.Marshal(gapEntry.ProtoMessage())
+	case FormatJSON:
+	}
+
+	return json.Marshal(gapEntry)
+}
+
+// encodeHeartbeat returns a heartbeat message with the current stats in the client's format.
+func (c *client) encodeHeartbeat() ([]byte, error) {
+	heartbeat := models.HeartbeatEntry{
+		MessageType: "heartbeat",
+		Timestamp:   float64(time.Now().UnixMilli()) / 1_000,
+		Stats: models.HeartbeatStats{
+			ProcessedCertificates:    metrics.GetProcessedCerts(),
+			ProcessedPrecertificates: metrics.GetProcessedPrecerts(),
+			Skipped:                  c.skippedCerts.Load(),
+		},
+	}
+
+	switch c.format {
+	case FormatCBOR:
+		return heartbeat.CBOR(), nil
+	case FormatProtobuf:
+		return proto.Marshal(heartbeat.ProtoMessage())
+	case FormatJSON:
+	}
+
+	return json.Marshal(heartbeat)
+}
+
+// newHeartbeatTicker returns a ticker for sending heartbeat messages or nil if heartbeats are disabled.
+func newHeartbeatTicker() *time.Ticker {
+	conf := config.AppConfig.Webserver.Heartbeat
+	if !conf.Enabled || conf.Interval <= 0 {
+		return nil
+	}
+
+	return time.NewTicker(time.Duration(conf.Interval) * time.Second)
+}
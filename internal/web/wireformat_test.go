@@ -0,0 +1,138 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/d-Rickyy-b/certstream-server-go/internal/models"
+)
+
+func TestWireFormatFor(t *testing.T) {
+	tests := []struct {
+		name            string
+		url             string
+		protocols       string
+		wantFormat      WireFormat
+		wantSubprotocol string
+	}{
+		{"default", "/", "", FormatJSON, ""},
+		{"query parameter", "/?format=cbor", "", FormatCBOR, ""},
+		{"unknown query parameter", "/?format=xml", "", FormatJSON, ""},
+		{"subprotocol", "/", "apikey.secret, certstream.cbor", FormatCBOR, cborSubprotocol},
+		{"other subprotocol", "/", "apikey.secret", FormatJSON, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.protocols != "" {
+				r.Header.Set("Sec-WebSocket-Protocol", tt.protocols)
+			}
+
+			format, subprotocol := wireFormatFor(r)
+			if format != tt.wantFormat || subprotocol != tt.wantSubprotocol {
+				t.Errorf("want (%d, %q), got (%d, %q)", tt.wantFormat, tt.wantSubprotocol, format, subprotocol)
+			}
+		})
+	}
+}
+
+func TestEncodeHeartbeat(t *testing.T) {
+	c := newClient(nil, SubTypeLite, "test-client", 1)
+	c.skippedCerts.Store(42)
+
+	data, err := c.encodeHeartbeat()
+	if err != nil {
+		t.Fatalf("failed to encode heartbeat: %v", err)
+	}
+
+	var heartbeat models.HeartbeatEntry
+	if err := json.Unmarshal(data, &heartbeat); err != nil {
+		t.Fatalf("heartbeat is not valid JSON: %v", err)
+	}
+
+	if heartbeat.MessageType != "heartbeat" || heartbeat.Timestamp == 0 || heartbeat.Stats.Skipped != 42 {
+		t.Errorf("unexpected heartbeat: %+v", heartbeat)
+	}
+
+	c.format = FormatCBOR
+
+	data, err = c.encodeHeartbeat()
+	if err != nil {
+		t.Fatalf("failed to encode heartbeat: %v", err)
+	}
+
+	var decoded map[int]any
+	if err := cbor.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("heartbeat is not valid CBOR: %v", err)
+	}
+
+	if decoded[2] != "heartbeat" {
+		t.Errorf("want message type 'heartbeat' at key 2, got %v", decoded[2])
+	}
+}
+
+func TestClientEncode_Grouped(t *testing.T) {
+	entry := models.Entry{Data: models.Data{LeafCert: models.LeafCert{
+		AllDomains: []string{"example.com", "www.example.com", "example.org"},
+		Domains: []models.Domain{
+			{Name: "example.com", RegistrableDomain: "example.com"},
+			{Name: "www.example.com", RegistrableDomain: "example.com"},
+			{Name: "example.org", RegistrableDomain: "example.org"},
+		},
+	}}}
+
+	c := newClient(nil, SubTypeDomain, "grouped-client", 1)
+	c.grouped = true
+
+	var grouped models.GroupedDomainsEntry
+	if err := json.Unmarshal(c.encode(&entry), &grouped); err != nil {
+		t.Fatal(err)
+	}
+
+	if grouped.MessageType != "dns_entries_grouped" || len(grouped.Data) != 2 || len(grouped.Data[0].Domains) != 2 {
+		t.Errorf("unexpected grouped domains: %+v", grouped)
+	}
+
+	c.format = FormatCBOR
+
+	var decoded map[int]any
+	if err := cbor.Unmarshal(c.encode(&entry), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if groups, _ := decoded[1].([]any); decoded[2] != "dns_entries_grouped" || len(groups) != 2 {
+		t.Errorf("unexpected grouped CBOR domains: %v", decoded)
+	}
+
+	c.format = FormatJSON
+	c.grouped = false
+
+	var ungrouped models.DomainsEntry
+	if err := json.Unmarshal(c.encode(&entry), &ungrouped); err != nil {
+		t.Fatal(err)
+	}
+
+	if ungrouped.MessageType != "dns_entries" || len(ungrouped.Data) != 3 {
+		t.Errorf("unexpected domains: %+v", ungrouped)
+	}
+}
+
+func TestGroupedFor(t *testing.T) {
+	r := httptest.NewRequest("GET", "/domains-only?group_by=registrable_domain", nil)
+
+	if !groupedFor(r, SubTypeDomain) {
+		t.Errorf("want grouped domains-only stream")
+	}
+
+	if groupedFor(r, SubTypeLite) {
+		t.Errorf("want lite stream not to be grouped")
+	}
+
+	if groupedFor(httptest.NewRequest("GET", "/domains-only?group_by=fqdn", nil), SubTypeDomain) {
+		t.Errorf("want unknown grouping to be ignored")
+	}
+}